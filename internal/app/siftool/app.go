@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2022, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -8,12 +8,15 @@ package siftool
 import (
 	"io"
 	"os"
+	"runtime"
 )
 
 // appOpts contains configured options.
 type appOpts struct {
-	out io.Writer
-	err io.Writer
+	out      io.Writer
+	err      io.Writer
+	format   OutputFormat
+	hostArch string
 }
 
 // AppOpt are used to configure optional behavior.
@@ -40,15 +43,39 @@ func OptAppError(w io.Writer) AppOpt {
 	}
 }
 
+// OptAppOutputFormat specifies that output should be written in the specified format.
+func OptAppOutputFormat(f OutputFormat) AppOpt {
+	return func(o *appOpts) error {
+		o.format = f
+		return nil
+	}
+}
+
+// OptAppHostArch specifies the CPU architecture of the host, using the Go runtime naming
+// convention (for example, "amd64").
+func OptAppHostArch(arch string) AppOpt {
+	return func(o *appOpts) error {
+		o.hostArch = arch
+		return nil
+	}
+}
+
 // New creates a new App configured with opts.
 //
 // By default, application output and errors are written to os.Stdout and os.Stderr respectively.
 // To modify this behavior, consider using OptAppOutput and/or OptAppError.
+//
+// By default, output is written in human-readable text. To modify this behavior, consider using
+// OptAppOutputFormat.
+//
+// By default, the CPU architecture of the host is runtime.GOARCH. To modify this behavior,
+// consider using OptAppHostArch.
 func New(opts ...AppOpt) (*App, error) {
 	a := App{
 		opts: appOpts{
-			out: os.Stdout,
-			err: os.Stderr,
+			out:      os.Stdout,
+			err:      os.Stderr,
+			hostArch: runtime.GOARCH,
 		},
 	}
 
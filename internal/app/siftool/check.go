@@ -0,0 +1,48 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+var errCheckFailed = errors.New("image check failed")
+
+// Check checks a SIF file for structural problems, and displays the problems found. If any
+// problems of error severity are found, an error is returned.
+func (a *App) Check(path string) error {
+	return withFileImage(path, false, func(f *sif.FileImage) error {
+		findings, err := sif.Check(f)
+		if err != nil {
+			return err
+		}
+
+		if len(findings) == 0 {
+			fmt.Fprintln(a.opts.out, "No problems found.")
+			return nil
+		}
+
+		var errs, warnings int
+		for _, fi := range findings {
+			fmt.Fprintln(a.opts.out, fi)
+
+			if fi.Severity == sif.SeverityError {
+				errs++
+			} else {
+				warnings++
+			}
+		}
+
+		if errs > 0 {
+			return fmt.Errorf("%w: %d error(s), %d warning(s)", errCheckFailed, errs, warnings)
+		}
+
+		return nil
+	})
+}
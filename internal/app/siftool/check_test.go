@@ -0,0 +1,88 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestApp_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		corrupt func(*testing.T, string)
+		wantErr error
+	}{
+		{
+			name:  "OneGroup",
+			image: "one-group.sif",
+		},
+		{
+			name:  "TwoGroupsSignedPGP",
+			image: "two-groups-signed-pgp.sif",
+		},
+		{
+			name:  "Truncated",
+			image: "one-group.sif",
+			corrupt: func(t *testing.T, path string) {
+				t.Helper()
+
+				if err := os.Truncate(path, 36864); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: errCheckFailed,
+		},
+		{
+			name:  "PrimaryArchWithoutPartition",
+			image: "one-object-generic-json.sif",
+			corrupt: func(t *testing.T, path string) {
+				t.Helper()
+
+				f, err := os.OpenFile(path, os.O_WRONLY, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer f.Close()
+
+				// Set architecture in global header to amd64.
+				if _, err := f.WriteAt([]byte("02\x00"), 45); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(corpus, tt.image)
+
+			if tt.corrupt != nil {
+				path = copyTestImage(t, tt.image)
+				tt.corrupt(t, path)
+			}
+
+			var b bytes.Buffer
+
+			a, err := New(OptAppOutput(&b))
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			if got, want := a.Check(path), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// describeObjects returns a human-readable representation of the object(s) in od.
+func describeObjects(od sif.ObjectDifference) string {
+	switch {
+	case od.A == nil:
+		return fmt.Sprintf("object %d (%v)", od.B.ID(), od.B.DataType())
+	case od.B == nil || od.A.ID() == od.B.ID():
+		return fmt.Sprintf("object %d (%v)", od.A.ID(), od.A.DataType())
+	default:
+		return fmt.Sprintf("object %d -> %d (%v)", od.A.ID(), od.B.ID(), od.A.DataType())
+	}
+}
+
+// isTextObject returns true if d contains text suitable for a line-oriented diff.
+func isTextObject(d *sif.Descriptor) bool {
+	switch d.DataType() {
+	case sif.DataDeffile, sif.DataEnvVar, sif.DataLabels:
+		return true
+	}
+	return false
+}
+
+// getText returns the text content of d. JSON labels are indented, so that differences are
+// reported per label.
+func getText(d *sif.Descriptor) (string, error) {
+	b, err := d.GetData()
+	if err != nil {
+		return "", err
+	}
+
+	if d.DataType() == sif.DataLabels {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, "", "  "); err == nil {
+			return buf.String(), nil
+		}
+	}
+
+	return string(b), nil
+}
+
+// writeContentDiff writes a line-oriented diff of the content of the objects in od to w.
+func writeContentDiff(w io.Writer, od sif.ObjectDifference) error {
+	a, err := getText(od.A)
+	if err != nil {
+		return err
+	}
+
+	b, err := getText(od.B)
+	if err != nil {
+		return err
+	}
+
+	writeLineDiff(w, "    ", splitLines(a), splitLines(b))
+	return nil
+}
+
+// Diff displays the differences between the SIF files at pathA and pathB. If content is true, a
+// line-oriented diff is displayed for modified text objects, such as definition files,
+// environment variables and labels.
+func (a *App) Diff(pathA, pathB string, content bool) error {
+	return withFileImage(pathA, false, func(fa *sif.FileImage) error {
+		return withFileImage(pathB, false, func(fb *sif.FileImage) error {
+			diffs, err := sif.Diff(fa, fb)
+			if err != nil {
+				return err
+			}
+
+			if len(diffs.Header) == 0 && len(diffs.Objects) == 0 {
+				fmt.Fprintln(a.opts.out, "No differences found.")
+				return nil
+			}
+
+			for _, fd := range diffs.Header {
+				fmt.Fprintf(a.opts.out, "header: %v: %q -> %q\n", fd.Name, fd.A, fd.B)
+			}
+
+			for _, od := range diffs.Objects {
+				fmt.Fprintf(a.opts.out, "%v: %v\n", od.Type, describeObjects(od))
+
+				var contentChanged bool
+				for _, fd := range od.Fields {
+					fmt.Fprintf(a.opts.out, "  %v: %v -> %v\n", fd.Name, fd.A, fd.B)
+
+					contentChanged = contentChanged || fd.Name == "content"
+				}
+
+				if content && contentChanged && isTextObject(od.A) {
+					if err := writeContentDiff(a.opts.out, od); err != nil {
+						return err
+					}
+				}
+			}
+
+			return nil
+		})
+	})
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// makeDiffSIF returns the path to a SIF containing a definition file, environment variables and
+// labels with the supplied content.
+func makeDiffSIF(t *testing.T, deffile, env, labels string) string {
+	t.Helper()
+
+	var dis []sif.DescriptorInput
+	for _, o := range []struct {
+		dt sif.DataType
+		s  string
+	}{
+		{sif.DataDeffile, deffile},
+		{sif.DataEnvVar, env},
+		{sif.DataLabels, labels},
+	} {
+		di, err := sif.NewDescriptorInput(o.dt, strings.NewReader(o.s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dis = append(dis, di)
+	}
+
+	path := filepath.Join(t.TempDir(), "image.sif")
+
+	f, err := sif.CreateContainerAtPath(path,
+		sif.OptCreateDeterministic(),
+		sif.OptCreateWithDescriptors(dis...),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.UnloadContainer(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestApp_Diff(t *testing.T) {
+	v1 := makeDiffSIF(t,
+		"bootstrap: docker\nfrom: alpine:3.19\n",
+		"export A=1\n",
+		`{"org.label-schema.version":"1.0","maintainer":"a"}`,
+	)
+	v2 := makeDiffSIF(t,
+		"bootstrap: docker\nfrom: alpine:3.20\n",
+		"export A=1\n",
+		`{"org.label-schema.version":"1.1","maintainer":"a"}`,
+	)
+
+	tests := []struct {
+		name    string
+		pathA   string
+		pathB   string
+		content bool
+		wantErr error
+	}{
+		{
+			name:    "NotExist",
+			pathA:   "not-exist.sif",
+			pathB:   filepath.Join(corpus, "one-group.sif"),
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name:  "Identical",
+			pathA: filepath.Join(corpus, "one-group.sif"),
+			pathB: filepath.Join(corpus, "one-group.sif"),
+		},
+		{
+			name:  "OneGroupSigned",
+			pathA: filepath.Join(corpus, "one-group.sif"),
+			pathB: filepath.Join(corpus, "one-group-signed-pgp.sif"),
+		},
+		{
+			name:  "TwoGroups",
+			pathA: filepath.Join(corpus, "two-groups.sif"),
+			pathB: filepath.Join(corpus, "one-group.sif"),
+		},
+		{
+			name:  "Text",
+			pathA: v1,
+			pathB: v2,
+		},
+		{
+			name:    "TextContent",
+			pathA:   v1,
+			pathB:   v2,
+			content: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			a, err := New(OptAppOutput(&b))
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			if got, want := a.Diff(tt.pathA, tt.pathB, tt.content), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if tt.wantErr == nil {
+				g := goldie.New(t, goldie.WithTestNameForDir(true))
+				g.Assert(t, tt.name, b.Bytes())
+			}
+		})
+	}
+}
+
+// numberedLines returns n lines, each consisting of prefix followed by the line number.
+func numberedLines(prefix string, n int) string {
+	var sb strings.Builder
+	for i := range n {
+		fmt.Fprintf(&sb, "%v%v\n", prefix, i)
+	}
+	return sb.String()
+}
+
+func TestWriteLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{"Empty", "", ""},
+		{"Added", "", "a\nb\n"},
+		{"Removed", "a\nb\n", ""},
+		{"Changed", "a\nb\nc\n", "a\nx\nc\n"},
+		{"Interleaved", "a\nb\nc\nd\n", "b\nx\nd\ny\n"},
+		{"TooLarge", "x\n" + numberedLines("a", 2100) + "y\n", "x\n" + numberedLines("b", 2100) + "y\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			writeLineDiff(&b, "", splitLines(tt.a), splitLines(tt.b))
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
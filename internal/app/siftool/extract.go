@@ -0,0 +1,261 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+	"github.com/sylabs/sif/v2/pkg/squashfs"
+)
+
+var (
+	errUnsafePath      = errors.New("refusing to extract unsafe path")
+	errUnsupportedType = errors.New("unsupported file type")
+)
+
+// xattrFS is implemented by file systems that support extended attributes.
+type xattrFS interface {
+	Xattrs(name string) (map[string][]byte, error)
+}
+
+// extractor extracts the contents of a file system to a directory.
+type extractor struct {
+	fsys  fs.FS
+	dir   string
+	warn  io.Writer
+	chown bool              // Whether to set file ownership.
+	links map[uint32]string // Path of first extracted file, keyed by inode number.
+	dirs  []extractedDir    // Extracted directories, whose metadata is set last.
+}
+
+// extractedDir is a directory that has been extracted.
+type extractedDir struct {
+	name string
+	fi   fs.FileInfo
+}
+
+// warnf writes a warning to e.warn. Warnings are used for operations that commonly require
+// privileges, such as creating device nodes, so that the remaining contents are extracted.
+func (e *extractor) warnf(format string, a ...any) {
+	fmt.Fprintf(e.warn, "Warning: "+format+"\n", a...)
+}
+
+// extract extracts the contents of e.fsys to e.dir. The metadata of the root directory of e.fsys
+// is applied to e.dir only if e.dir does not already exist, so that the metadata of an existing
+// directory is not modified.
+func (e *extractor) extract() error {
+	_, err := os.Lstat(e.dir)
+	created := errors.Is(err, fs.ErrNotExist)
+	if err != nil && !created {
+		return err
+	}
+
+	if err := os.MkdirAll(e.dir, 0o700); err != nil {
+		return err
+	}
+
+	if err := fs.WalkDir(e.fsys, ".", e.extractEntry); err != nil {
+		return err
+	}
+
+	// Set directory metadata in reverse order, so that restrictive permissions on a parent do
+	// not prevent setting the metadata of its children.
+	for _, d := range slices.Backward(e.dirs) {
+		if d.name == "." && !created {
+			continue
+		}
+
+		if err := e.setMetadata(d.name, d.fi); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// path returns the path on the host corresponding to name, refusing paths that are not local
+// to the destination directory.
+func (e *extractor) path(name string) (string, error) {
+	p := filepath.FromSlash(name)
+	if !filepath.IsLocal(p) && name != "." {
+		return "", fmt.Errorf("%w: %q", errUnsafePath, name)
+	}
+	return filepath.Join(e.dir, p), nil
+}
+
+// extractEntry extracts the entry with the specified name.
+func (e *extractor) extractEntry(name string, d fs.DirEntry, err error) error {
+	if err != nil {
+		return err
+	}
+
+	path, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	fi, err := d.Info()
+	if err != nil {
+		return err
+	}
+
+	switch mode := fi.Mode(); {
+	case mode.IsDir():
+		if name != "." {
+			if err := os.Mkdir(path, 0o700); err != nil {
+				return err
+			}
+		}
+		e.dirs = append(e.dirs, extractedDir{name, fi})
+		return nil
+
+	case mode.IsRegular():
+		if ok, err := e.extractHardLink(path, fi); ok || err != nil {
+			return err
+		}
+
+		if err := e.extractFile(name, path); err != nil {
+			return err
+		}
+
+	case mode&fs.ModeSymlink != 0:
+		target, err := fs.ReadLink(e.fsys, name)
+		if err != nil {
+			return err
+		}
+
+		if err := os.Symlink(target, path); err != nil {
+			return err
+		}
+
+	case mode&(fs.ModeDevice|fs.ModeNamedPipe|fs.ModeSocket) != 0:
+		var major, minor uint32
+		if in, ok := fi.Sys().(*squashfs.Inode); ok {
+			major, minor = in.Major, in.Minor
+		}
+
+		if err := mknod(path, mode, major, minor); err != nil {
+			e.warnf("failed to create %v: %v", name, err)
+			return nil
+		}
+
+	default:
+		return fmt.Errorf("%w: %v: %v", errUnsupportedType, name, mode.Type())
+	}
+
+	return e.setMetadata(name, fi)
+}
+
+// extractHardLink creates a hard link at path if fi describes a file that has already been
+// extracted. It reports whether a link was created.
+func (e *extractor) extractHardLink(path string, fi fs.FileInfo) (bool, error) {
+	in, ok := fi.Sys().(*squashfs.Inode)
+	if !ok || in.Nlink < 2 {
+		return false, nil
+	}
+
+	if first, ok := e.links[in.Number]; ok {
+		return true, os.Link(first, path)
+	}
+
+	e.links[in.Number] = path
+	return false, nil
+}
+
+// extractFile writes the contents of the regular file with the specified name to path.
+func (e *extractor) extractFile(name, path string) error {
+	src, err := e.fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+
+	return dst.Close()
+}
+
+// setMetadata sets the ownership, permissions, extended attributes and modification time of the
+// extracted entry with the specified name.
+func (e *extractor) setMetadata(name string, fi fs.FileInfo) error {
+	path, err := e.path(name)
+	if err != nil {
+		return err
+	}
+
+	isLink := fi.Mode()&fs.ModeSymlink != 0
+
+	// Ownership is set before permissions, since changing ownership may clear setuid/setgid.
+	if in, ok := fi.Sys().(*squashfs.Inode); ok && e.chown {
+		if err := os.Lchown(path, int(in.UID), int(in.GID)); err != nil {
+			return err
+		}
+	}
+
+	if !isLink {
+		perm := fi.Mode() & (fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)
+		if err := os.Chmod(path, perm); err != nil {
+			return err
+		}
+	}
+
+	if xfs, ok := e.fsys.(xattrFS); ok {
+		xattrs, err := xfs.Xattrs(name)
+		if err != nil {
+			return err
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(xattrs)) {
+			if err := lsetxattr(path, k, xattrs[k]); err != nil {
+				e.warnf("failed to set extended attribute %v on %v: %v", k, name, err)
+			}
+		}
+	}
+
+	return lchtimes(path, fi.ModTime())
+}
+
+// Extract extracts the contents of the file system in the partition selected by fn from the SIF
+// file at path, to directory dir.
+func (a *App) Extract(path string, fn sif.DescriptorSelectorFunc, dir string) error {
+	return withFileImage(path, false, func(f *sif.FileImage) error {
+		d, err := f.GetDescriptor(fn)
+		if err != nil {
+			return err
+		}
+
+		fsys, err := squashfs.NewFSFromDescriptor(d)
+		if err != nil {
+			return err
+		}
+
+		e := extractor{
+			fsys:  fsys,
+			dir:   dir,
+			warn:  a.opts.err,
+			chown: os.Geteuid() == 0,
+			links: make(map[uint32]string),
+		}
+
+		return e.extract()
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+//go:build !unix
+
+package siftool
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+var errUnsupportedPlatform = errors.New("not supported on this platform")
+
+// mknod creates a device node, named pipe or socket at path.
+func mknod(string, fs.FileMode, uint32, uint32) error {
+	return errUnsupportedPlatform
+}
+
+// lsetxattr sets the extended attribute name of path to value, without following symbolic links.
+func lsetxattr(string, string, []byte) error {
+	return errUnsupportedPlatform
+}
+
+// lchtimes sets the access and modification times of path to t, without following symbolic
+// links.
+func lchtimes(path string, t time.Time) error {
+	if fi, err := os.Lstat(path); err != nil || fi.Mode()&fs.ModeSymlink != 0 {
+		return err
+	}
+	return os.Chtimes(path, t, t)
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// makeExtractSIF returns the path to a SIF containing a squashfs primary system partition.
+func makeExtractSIF(t *testing.T, a *App) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "sif")
+
+	if err := a.New(path); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(filepath.Join("..", "..", "..", "test", "input", "extract.squashfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := a.Add(path, sif.DataPartition, f,
+		sif.OptPartitionMetadata(sif.FsSquash, sif.PartPrimSys, "amd64"),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// writeTree writes a listing of the directory tree at dir to w.
+func writeTree(t *testing.T, w io.Writer, dir string) {
+	t.Helper()
+
+	err := filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		fi, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%v %v %v", fi.Mode(), fi.ModTime().Unix(), filepath.ToSlash(rel))
+
+		switch {
+		case fi.Mode().IsRegular():
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, " %q", b)
+
+		case fi.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, " -> %v", target)
+		}
+
+		fmt.Fprintln(w)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApp_Extract(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		fn      sif.DescriptorSelectorFunc
+		wantErr error
+	}{
+		{
+			name:    "NotExist",
+			path:    "not-exist.sif",
+			fn:      sif.WithID(1),
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name:    "ObjectNotFound",
+			fn:      sif.WithID(2),
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name: "ID",
+			fn:   sif.WithID(1),
+		},
+		{
+			name: "Primary",
+			fn:   sif.WithPartitionType(sif.PartPrimSys),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			// Warnings depend on host privileges and file system support, so are not checked.
+			a, err := New(OptAppOutput(&b), OptAppError(io.Discard))
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			path := tt.path
+			if path == "" {
+				path = makeExtractSIF(t, a)
+			}
+
+			dir := filepath.Join(t.TempDir(), "rootfs")
+
+			if got, want := a.Extract(path, tt.fn, dir), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if tt.wantErr == nil {
+				t.Cleanup(func() {
+					// Restore write permission, so that the directory can be removed.
+					if err := os.Chmod(filepath.Join(dir, "ro"), 0o755); err != nil {
+						t.Error(err)
+					}
+				})
+
+				sh, err := os.Stat(filepath.Join(dir, "bin", "sh"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				bash, err := os.Stat(filepath.Join(dir, "bin", "bash"))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !os.SameFile(sh, bash) {
+					t.Errorf("hard link not preserved")
+				}
+
+				writeTree(t, &b, dir)
+
+				g := goldie.New(t, goldie.WithTestNameForDir(true))
+				g.Assert(t, tt.name, b.Bytes())
+			}
+		})
+	}
+}
+
+func Test_extractor_extract(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fs.FS
+		wantErr error
+	}{
+		{
+			name: "OK",
+			fsys: fstest.MapFS{
+				"dir/file": &fstest.MapFile{Data: []byte("file"), Mode: 0o644},
+				"link":     &fstest.MapFile{Data: []byte("/etc"), Mode: fs.ModeSymlink | 0o777},
+			},
+		},
+		{
+			name: "UnsafePath",
+			fsys: fstest.MapFS{
+				"../evil": &fstest.MapFile{Data: []byte("evil"), Mode: 0o644},
+			},
+			wantErr: errUnsafePath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "rootfs")
+
+			e := extractor{
+				fsys:  tt.fsys,
+				dir:   dir,
+				warn:  io.Discard,
+				links: make(map[uint32]string),
+			}
+
+			if got, want := e.extract(), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if _, err := os.Lstat(filepath.Join(dir, "..", "evil")); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("unsafe path extracted")
+			}
+		})
+	}
+}
+
+func Test_extractor_extract_RootMetadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		".":    &fstest.MapFile{Mode: fs.ModeDir | 0o700, ModTime: time.Unix(946702800, 0)},
+		"file": &fstest.MapFile{Data: []byte("file"), Mode: 0o644},
+	}
+
+	tests := []struct {
+		name      string
+		exists    bool
+		wantMode  fs.FileMode
+		wantMtime time.Time
+	}{
+		{
+			name:      "Created",
+			wantMode:  fs.ModeDir | 0o700,
+			wantMtime: time.Unix(946702800, 0),
+		},
+		{
+			name:     "Exists",
+			exists:   true,
+			wantMode: fs.ModeDir | 0o751,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "rootfs")
+
+			if tt.exists {
+				if err := os.Mkdir(dir, 0o700); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.Chmod(dir, 0o751); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			e := extractor{
+				fsys:  fsys,
+				dir:   dir,
+				warn:  io.Discard,
+				links: make(map[uint32]string),
+			}
+
+			if err := e.extract(); err != nil {
+				t.Fatal(err)
+			}
+
+			fi, err := os.Stat(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := fi.Mode(), tt.wantMode; got != want {
+				t.Errorf("got mode %v, want %v", got, want)
+			}
+
+			// Extracting entries updates the modification time of an existing directory.
+			if got, want := fi.ModTime(), tt.wantMtime; !tt.exists && !got.Equal(want) {
+				t.Errorf("got modification time %v, want %v", got, want)
+			}
+		})
+	}
+}
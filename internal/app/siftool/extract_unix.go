@@ -0,0 +1,45 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+//go:build unix
+
+package siftool
+
+import (
+	"io/fs"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// mknod creates a device node, named pipe or socket at path.
+func mknod(path string, mode fs.FileMode, major, minor uint32) error {
+	m := uint32(mode.Perm())
+
+	switch {
+	case mode&fs.ModeCharDevice != 0:
+		m |= unix.S_IFCHR
+	case mode&fs.ModeDevice != 0:
+		m |= unix.S_IFBLK
+	case mode&fs.ModeNamedPipe != 0:
+		m |= unix.S_IFIFO
+	case mode&fs.ModeSocket != 0:
+		m |= unix.S_IFSOCK
+	}
+
+	return unix.Mknod(path, m, int(unix.Mkdev(major, minor))) //nolint:gosec // Device numbers are 32-bit.
+}
+
+// lsetxattr sets the extended attribute name of path to value, without following symbolic links.
+func lsetxattr(path, name string, value []byte) error {
+	return unix.Lsetxattr(path, name, value, 0)
+}
+
+// lchtimes sets the access and modification times of path to t, without following symbolic
+// links.
+func lchtimes(path string, t time.Time) error {
+	ts := []unix.Timespec{unix.NsecToTimespec(t.UnixNano()), unix.NsecToTimespec(t.UnixNano())}
+	return unix.UtimesNanoAt(unix.AT_FDCWD, path, ts, unix.AT_SYMLINK_NOFOLLOW)
+}
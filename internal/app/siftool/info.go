@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2023, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2018, Divya Cote <divya.cote@gmail.com> All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
@@ -11,7 +11,9 @@ package siftool
 import (
 	"fmt"
 	"io"
+	"maps"
 	"math"
+	"slices"
 	"strings"
 	"text/tabwriter"
 
@@ -36,8 +38,37 @@ func readableSize(size int64) string {
 	return fmt.Sprintf("%.0f %ciB", math.Round(float64(size)/float64(div)), units[exp])
 }
 
-// writeHeader writes header information in f to w.
-func writeHeader(w io.Writer, f *sif.FileImage) error {
+// getPrimaryArchs returns the architectures of the primary system partitions in f.
+func getPrimaryArchs(f *sif.FileImage) []string {
+	var archs []string
+
+	f.WithDescriptors(func(d sif.Descriptor) bool {
+		if _, pt, arch, err := d.PartitionMetadata(); err == nil && pt == sif.PartPrimSys {
+			archs = append(archs, arch)
+		}
+		return false
+	})
+
+	return archs
+}
+
+// getHostPartitionID returns the ID of the primary system partition for hostArch, if f contains
+// primary system partitions for multiple architectures. Otherwise, zero is returned.
+func getHostPartitionID(f *sif.FileImage, hostArch string) uint32 {
+	if len(getPrimaryArchs(f)) < 2 {
+		return 0
+	}
+
+	d, err := f.GetDescriptor(sif.WithPrimaryPartitionForArch(hostArch))
+	if err != nil {
+		return 0
+	}
+	return d.ID()
+}
+
+// writeHeader writes header information in f to w. If f contains primary system partitions for
+// multiple architectures, the partition for hostArch is identified.
+func writeHeader(w io.Writer, f *sif.FileImage, hostArch string) error {
 	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
 
 	if s := f.LaunchScript(); s != "" {
@@ -50,6 +81,14 @@ func writeHeader(w io.Writer, f *sif.FileImage) error {
 		fmt.Fprintf(tw, "Primary Architecture:\t%v\n", arch)
 	}
 
+	if archs := getPrimaryArchs(f); len(archs) > 1 {
+		fmt.Fprintf(tw, "Primary Architectures:\t%v\n", strings.Join(archs, ", "))
+	}
+
+	if id := getHostPartitionID(f, hostArch); id != 0 {
+		fmt.Fprintf(tw, "Host Primary Partition:\t%v (%v)\n", id, hostArch)
+	}
+
 	if id := f.ID(); id != uuid.Nil.String() {
 		fmt.Fprintf(tw, "ID:\t%v\n", id)
 	}
@@ -75,12 +114,22 @@ func writeHeader(w io.Writer, f *sif.FileImage) error {
 // Header displays a SIF file global header.
 func (a *App) Header(path string) error {
 	return withFileImage(path, false, func(f *sif.FileImage) error {
-		return writeHeader(a.opts.out, f)
+		if a.opts.format != OutputFormatText {
+			return writeStructured(a.opts.out, a.opts.format, headerOutput{
+				SchemaVersion: outputSchemaVersion,
+				Header:        newHeaderValue(f, a.opts.hostArch),
+			})
+		}
+
+		return writeHeader(a.opts.out, f, a.opts.hostArch)
 	})
 }
 
-// writeList writes the list of descriptors in f to w.
-func writeList(w io.Writer, f *sif.FileImage) error {
+// writeList writes the list of descriptors in f to w. If f contains primary system partitions for
+// multiple architectures, the partition for hostArch is identified.
+func writeList(w io.Writer, f *sif.FileImage, hostArch string) error {
+	hostID := getHostPartitionID(f, hostArch)
+
 	fmt.Fprintln(w, ("------------------------------------------------------------------------------"))
 	fmt.Fprintf(w, "%-4s %-8s %-8s %-26s %s\n", "ID", "|GROUP", "|LINK", "|SIF POSITION (start-end)", "|TYPE")
 	fmt.Fprintln(w, ("------------------------------------------------------------------------------"))
@@ -109,7 +158,13 @@ func writeList(w io.Writer, f *sif.FileImage) error {
 		case sif.DataPartition:
 			fs, pt, arch, err := d.PartitionMetadata()
 			if err == nil {
-				fmt.Fprintf(w, "|%s (%s/%s/%s)\n", dt, fs, pt, arch)
+				fmt.Fprintf(w, "|%s (%s/%s/%s)", dt, fs, pt, arch)
+
+				if d.ID() == hostID {
+					fmt.Fprint(w, " [host]")
+				}
+
+				fmt.Fprintln(w)
 			}
 
 		case sif.DataSignature:
@@ -143,7 +198,11 @@ func writeList(w io.Writer, f *sif.FileImage) error {
 // List displays a list of all active descriptors from a SIF file.
 func (a *App) List(path string) error {
 	return withFileImage(path, false, func(f *sif.FileImage) error {
-		return writeList(a.opts.out, f)
+		if a.opts.format != OutputFormatText {
+			return writeStructuredList(a.opts.out, a.opts.format, f, a.opts.hostArch)
+		}
+
+		return writeList(a.opts.out, f, a.opts.hostArch)
 	})
 }
 
@@ -193,10 +252,23 @@ func writeInfo(w io.Writer, v sif.Descriptor) error {
 			return err
 		}
 
+		p, err := v.PartitionPlatform()
+		if err != nil {
+			return err
+		}
+
 		fmt.Fprintf(tw, "\tFilesystem Type:\t%v\n", fs)
 		fmt.Fprintf(tw, "\tPartition Type:\t%v\n", pt)
 		fmt.Fprintf(tw, "\tArchitecture:\t%v\n", arch)
 
+		if p.Variant != "" {
+			fmt.Fprintf(tw, "\tArchitecture Variant:\t%v\n", p.Variant)
+		}
+
+		if p.OS != "" {
+			fmt.Fprintf(tw, "\tOS:\t%v\n", p.OS)
+		}
+
 	case sif.DataSignature:
 		ht, fp, err := v.SignatureMetadata()
 		if err != nil {
@@ -235,6 +307,19 @@ func writeInfo(w io.Writer, v sif.Descriptor) error {
 		fmt.Fprintf(tw, "\tDigest:\t%s\n", h)
 	}
 
+	annotations, err := v.Annotations()
+	if err != nil {
+		return err
+	}
+
+	if len(annotations) > 0 {
+		fmt.Fprintln(tw, "\tAnnotations:")
+
+		for _, k := range slices.Sorted(maps.Keys(annotations)) {
+			fmt.Fprintf(tw, "\t  %v:\t%v\n", k, annotations[k])
+		}
+	}
+
 	return tw.Flush()
 }
 
@@ -246,6 +331,18 @@ func (a *App) Info(path string, id uint32) error {
 			return err
 		}
 
+		if a.opts.format != OutputFormatText {
+			v, err := newDescriptorValue(d)
+			if err != nil {
+				return err
+			}
+
+			return writeStructured(a.opts.out, a.opts.format, infoOutput{
+				SchemaVersion: outputSchemaVersion,
+				Descriptor:    v,
+			})
+		}
+
 		return writeInfo(a.opts.out, d)
 	})
 }
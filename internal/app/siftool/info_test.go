@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -79,6 +79,7 @@ func TestApp_Header(t *testing.T) {
 	tests := []struct {
 		name    string
 		path    string
+		opts    []AppOpt
 		wantErr error
 	}{
 		{
@@ -162,12 +163,47 @@ func TestApp_Header(t *testing.T) {
 			name: "TwoGroupsSignedPGP",
 			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
 		},
+		{
+			name: "MultiArch",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			opts: []AppOpt{OptAppHostArch("amd64")},
+		},
+		{
+			name: "MultiArchOtherHost",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			opts: []AppOpt{OptAppHostArch("arm64")},
+		},
+		{
+			name: "EmptyJSON",
+			path: filepath.Join(corpus, "empty.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "OneObjectTimeJSON",
+			path: filepath.Join(corpus, "one-object-time.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "TwoGroupsSignedPGPJSON",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "MultiArchJSON",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			opts: []AppOpt{OptAppHostArch("amd64"), OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "TwoGroupsSignedPGPYAML",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatYAML)},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var b bytes.Buffer
 
-			a, err := New(OptAppOutput(&b))
+			a, err := New(append([]AppOpt{OptAppOutput(&b)}, tt.opts...)...)
 			if err != nil {
 				t.Fatalf("failed to create app: %v", err)
 			}
@@ -189,6 +225,7 @@ func TestApp_List(t *testing.T) {
 	tests := []struct {
 		name    string
 		path    string
+		opts    []AppOpt
 		wantErr error
 	}{
 		{
@@ -272,12 +309,52 @@ func TestApp_List(t *testing.T) {
 			name: "TwoGroupsSignedPGP",
 			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
 		},
+		{
+			name: "MultiArch",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			opts: []AppOpt{OptAppHostArch("amd64")},
+		},
+		{
+			name: "MultiArchOtherHost",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			opts: []AppOpt{OptAppHostArch("arm64")},
+		},
+		{
+			name: "EmptyJSON",
+			path: filepath.Join(corpus, "empty.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "EmptyYAML",
+			path: filepath.Join(corpus, "empty.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatYAML)},
+		},
+		{
+			name: "OneObjectTimeJSON",
+			path: filepath.Join(corpus, "one-object-time.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "TwoGroupsSignedPGPJSON",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "MultiArchJSON",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			opts: []AppOpt{OptAppHostArch("amd64"), OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "TwoGroupsSignedPGPYAML",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatYAML)},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var b bytes.Buffer
 
-			a, err := New(OptAppOutput(&b))
+			a, err := New(append([]AppOpt{OptAppOutput(&b)}, tt.opts...)...)
 			if err != nil {
 				t.Fatalf("failed to create app: %v", err)
 			}
@@ -299,6 +376,7 @@ func TestApp_Info(t *testing.T) {
 		name    string
 		path    string
 		id      uint32
+		opts    []AppOpt
 		wantErr error
 	}{
 		{
@@ -356,12 +434,99 @@ func TestApp_Info(t *testing.T) {
 			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
 			id:   4,
 		},
+		{
+			name: "DataPartitionPlatform",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			id:   2,
+		},
+		{
+			name: "Annotated",
+			path: filepath.Join(corpus, "one-group-annotated.sif"),
+			id:   1,
+		},
+		{
+			name: "Annotations",
+			path: filepath.Join(corpus, "one-group-annotated.sif"),
+			id:   3,
+		},
+		{
+			name: "GenericJSONJSON",
+			path: filepath.Join(corpus, "one-object-generic-json.sif"),
+			id:   1,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "CryptMessageJSON",
+			path: filepath.Join(corpus, "one-object-crypt-message.sif"),
+			id:   1,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "SBOMJSON",
+			path: filepath.Join(corpus, "one-object-sbom.sif"),
+			id:   1,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "OCIRootIndexJSON",
+			path: filepath.Join(corpus, "one-object-oci-root-index.sif"),
+			id:   1,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "OCIBlobJSON",
+			path: filepath.Join(corpus, "one-object-oci-blob.sif"),
+			id:   1,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "DataPartitionPlatformJSON",
+			path: filepath.Join(corpus, "multi-arch.sif"),
+			id:   2,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "AnnotatedJSON",
+			path: filepath.Join(corpus, "one-group-annotated.sif"),
+			id:   1,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "AnnotatedYAML",
+			path: filepath.Join(corpus, "one-group-annotated.sif"),
+			id:   1,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatYAML)},
+		},
+		{
+			name: "DataPartitionSquashFSJSON",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			id:   2,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "DataPartitionSquashFSYAML",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			id:   2,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatYAML)},
+		},
+		{
+			name: "DataSignatureJSON",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			id:   4,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "DataSignatureYAML",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			id:   4,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatYAML)},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var b bytes.Buffer
 
-			a, err := New(OptAppOutput(&b))
+			a, err := New(append([]AppOpt{OptAppOutput(&b)}, tt.opts...)...)
 			if err != nil {
 				t.Fatalf("failed to create app: %v", err)
 			}
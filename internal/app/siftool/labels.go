@@ -0,0 +1,60 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/sylabs/sif/v2/pkg/container"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// Labels displays the labels of the container in the SIF file at path.
+func (a *App) Labels(path string) error {
+	return withFileImage(path, false, func(f *sif.FileImage) error {
+		labels, err := container.Labels(f)
+		if err != nil {
+			return err
+		}
+
+		if a.opts.format != OutputFormatText {
+			return writeStructured(a.opts.out, a.opts.format, labelsOutput{
+				SchemaVersion: outputSchemaVersion,
+				Labels:        labels,
+			})
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(labels)) {
+			fmt.Fprintf(a.opts.out, "%v=%v\n", k, labels[k])
+		}
+
+		return nil
+	})
+}
+
+// SetLabels updates the labels of the container in the SIF file at path. Labels in set are added
+// or updated, and labels in unset are removed. Other labels are retained.
+func (*App) SetLabels(path string, set map[string]string, unset []string) error {
+	return withFileImage(path, true, func(f *sif.FileImage) error {
+		labels, err := container.Labels(f)
+		if errors.Is(err, sif.ErrObjectNotFound) {
+			labels = make(map[string]string)
+		} else if err != nil {
+			return err
+		}
+
+		maps.Copy(labels, set)
+
+		for _, k := range unset {
+			delete(labels, k)
+		}
+
+		return container.SetLabels(f, labels)
+	})
+}
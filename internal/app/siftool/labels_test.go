@@ -0,0 +1,108 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+func TestApp_Labels(t *testing.T) {
+	labelled := makeDiffSIF(t, "bootstrap: docker\n", "", `{"b":"2","a":"1"}`)
+
+	tests := []struct {
+		name    string
+		path    string
+		opts    []AppOpt
+		wantErr error
+	}{
+		{
+			name:    "NoLabels",
+			path:    filepath.Join(corpus, "one-group.sif"),
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name: "Text",
+			path: labelled,
+		},
+		{
+			name: "JSON",
+			path: labelled,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatJSON)},
+		},
+		{
+			name: "YAML",
+			path: labelled,
+			opts: []AppOpt{OptAppOutputFormat(OutputFormatYAML)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			a, err := New(append([]AppOpt{OptAppOutput(&b)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			if got, want := a.Labels(tt.path), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if tt.wantErr == nil {
+				g := goldie.New(t, goldie.WithTestNameForDir(true))
+				g.Assert(t, tt.name, b.Bytes())
+			}
+		})
+	}
+}
+
+func TestApp_SetLabels(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		set   map[string]string
+		unset []string
+	}{
+		{
+			name: "Add",
+			path: copyTestImage(t, "one-group.sif"),
+			set:  map[string]string{"a": "1"},
+		},
+		{
+			name:  "Update",
+			path:  makeDiffSIF(t, "bootstrap: docker\n", "", `{"a":"1","b":"2","c":"3"}`),
+			set:   map[string]string{"a": "4", "d": "5"},
+			unset: []string{"b", "e"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			a, err := New(OptAppOutput(&b))
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			if err := a.SetLabels(tt.path, tt.set, tt.unset); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := a.Labels(tt.path); err != nil {
+				t.Fatal(err)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
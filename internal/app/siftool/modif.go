@@ -49,3 +49,10 @@ func (*App) Setprim(path string, id uint32) error {
 		return f.SetPrimPart(id)
 	})
 }
+
+// Compact relocates the data objects in the SIF file to eliminate unused space.
+func (*App) Compact(path string) error {
+	return withFileImage(path, true, func(f *sif.FileImage) error {
+		return f.Compact()
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"crypto"
 	"errors"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -148,3 +149,54 @@ func TestApp_Setprim(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestApp_Compact(t *testing.T) {
+	a, err := New()
+	if err != nil {
+		t.Fatalf("failed to create app: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "sif")
+
+	if err := a.New(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range [][]byte{{0xfa, 0xce}, {0xfe, 0xed}, {0xde, 0xad, 0xbe, 0xef}} {
+		if err := a.Add(path, sif.DataGeneric, bytes.NewReader(b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := a.Del(path, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Compact(path); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := sif.LoadContainerFromPath(path, sif.OptLoadWithFlag(os.O_RDONLY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.UnloadContainer()
+
+	d, err := f.GetDescriptor(sif.WithID(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := d.Offset(), f.DataOffset(); got != want {
+		t.Errorf("got offset %v, want %v", got, want)
+	}
+
+	b, err := d.GetData()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := b, []byte{0xfe, 0xed}; !bytes.Equal(got, want) {
+		t.Errorf("got data %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sylabs/sif/v2/internal/pkg/exectest"
+	"github.com/sylabs/sif/v2/pkg/user" //nolint:staticcheck // No replacement is available yet.
+)
+
+func TestApp_Mount(t *testing.T) {
+	var out, err bytes.Buffer
+
+	a, e := New(OptAppOutput(&out), OptAppError(&err))
+	if e != nil {
+		t.Fatalf("failed to create app: %v", e)
+	}
+
+	if e := a.Mount(context.Background(), filepath.Join(corpus, "one-group.sif"), t.TempDir(),
+		user.OptMountSquashfusePath(exectest.Script(t, "squashfuse", `echo squashfuse "$1"; echo "$2" >&2`)),
+	); e != nil {
+		t.Fatal(e)
+	}
+
+	if got, want := out.String(), "squashfuse -o\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+
+	if got, want := err.String(), "ro,offset=36864\n"; got != want {
+		t.Errorf("got error output %q, want %q", got, want)
+	}
+}
+
+func TestApp_Unmount(t *testing.T) {
+	var out, err bytes.Buffer
+
+	a, e := New(OptAppOutput(&out), OptAppError(&err))
+	if e != nil {
+		t.Fatalf("failed to create app: %v", e)
+	}
+
+	if e := a.Unmount(context.Background(), t.TempDir(),
+		user.OptUnmountFusermountPath(exectest.Script(t, "fusermount", `echo fusermount "$1"`)),
+	); e != nil {
+		t.Fatal(e)
+	}
+
+	if got, want := out.String(), "fusermount -u\n"; got != want {
+		t.Errorf("got output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"crypto"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sylabs/sif/v2/pkg/sif"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat specifies the format of command output.
+type OutputFormat int
+
+// List of supported output formats.
+const (
+	OutputFormatText OutputFormat = iota // Human-readable text.
+	OutputFormatJSON                     // JSON, according to the output schema.
+	OutputFormatYAML                     // YAML, according to the output schema.
+)
+
+// outputSchemaVersion is the version of the schema used for structured output. It must be
+// incremented when an incompatible change is made to the schema. Adding fields is not considered
+// an incompatible change.
+const outputSchemaVersion = 1
+
+var errUnsupportedOutputFormat = errors.New("unsupported output format")
+
+// headerOutput is the structured output of the header command.
+type headerOutput struct {
+	SchemaVersion int         `json:"schemaVersion" yaml:"schemaVersion"`
+	Header        headerValue `json:"header"        yaml:"header"`
+}
+
+// listOutput is the structured output of the list command.
+type listOutput struct {
+	SchemaVersion int               `json:"schemaVersion" yaml:"schemaVersion"`
+	Descriptors   []descriptorValue `json:"descriptors"   yaml:"descriptors"`
+}
+
+// infoOutput is the structured output of the info command.
+type infoOutput struct {
+	SchemaVersion int             `json:"schemaVersion" yaml:"schemaVersion"`
+	Descriptor    descriptorValue `json:"descriptor"    yaml:"descriptor"`
+}
+
+// labelsOutput is the structured output of the labels get command.
+type labelsOutput struct {
+	SchemaVersion int               `json:"schemaVersion" yaml:"schemaVersion"`
+	Labels        map[string]string `json:"labels"        yaml:"labels"`
+}
+
+// headerValue describes the global header of an image.
+type headerValue struct {
+	LaunchScript      string     `json:"launchScript,omitempty"    yaml:"launchScript,omitempty"`
+	Version           string     `json:"version"                   yaml:"version"`
+	PrimaryArch       string     `json:"primaryArch,omitempty"     yaml:"primaryArch,omitempty"`
+	PrimaryArchs      []string   `json:"primaryArchs,omitempty"    yaml:"primaryArchs,omitempty"`
+	HostPartitionID   uint32     `json:"hostPartitionID,omitempty" yaml:"hostPartitionID,omitempty"`
+	ID                string     `json:"id,omitempty"              yaml:"id,omitempty"`
+	CreatedAt         *time.Time `json:"createdAt,omitempty"       yaml:"createdAt,omitempty"`
+	ModifiedAt        *time.Time `json:"modifiedAt,omitempty"      yaml:"modifiedAt,omitempty"`
+	DescriptorsFree   int64      `json:"descriptorsFree"           yaml:"descriptorsFree"`
+	DescriptorsTotal  int64      `json:"descriptorsTotal"          yaml:"descriptorsTotal"`
+	DescriptorsOffset int64      `json:"descriptorsOffset"         yaml:"descriptorsOffset"`
+	DescriptorsSize   int64      `json:"descriptorsSize"           yaml:"descriptorsSize"`
+	DataOffset        int64      `json:"dataOffset"                yaml:"dataOffset"`
+	DataSize          int64      `json:"dataSize"                  yaml:"dataSize"`
+}
+
+// descriptorValue describes a data object descriptor.
+type descriptorValue struct {
+	ID            uint32              `json:"id"                      yaml:"id"`
+	DataType      string              `json:"dataType"                yaml:"dataType"`
+	GroupID       uint32              `json:"groupID,omitempty"       yaml:"groupID,omitempty"`
+	Link          *linkValue          `json:"link,omitempty"          yaml:"link,omitempty"`
+	Offset        int64               `json:"offset"                  yaml:"offset"`
+	Size          int64               `json:"size"                    yaml:"size"`
+	CreatedAt     *time.Time          `json:"createdAt,omitempty"     yaml:"createdAt,omitempty"`
+	ModifiedAt    *time.Time          `json:"modifiedAt,omitempty"    yaml:"modifiedAt,omitempty"`
+	Name          string              `json:"name,omitempty"          yaml:"name,omitempty"`
+	Annotations   map[string]string   `json:"annotations,omitempty"   yaml:"annotations,omitempty"`
+	Partition     *partitionValue     `json:"partition,omitempty"     yaml:"partition,omitempty"`
+	Signature     *signatureValue     `json:"signature,omitempty"     yaml:"signature,omitempty"`
+	CryptoMessage *cryptoMessageValue `json:"cryptoMessage,omitempty" yaml:"cryptoMessage,omitempty"`
+	SBOM          *sbomValue          `json:"sbom,omitempty"          yaml:"sbom,omitempty"`
+	OCIBlob       *ociBlobValue       `json:"ociBlob,omitempty"       yaml:"ociBlob,omitempty"`
+	Host          bool                `json:"host,omitempty"          yaml:"host,omitempty"`
+}
+
+// linkValue describes the object or object group a descriptor is linked to.
+type linkValue struct {
+	ID    uint32 `json:"id"    yaml:"id"`
+	Group bool   `json:"group" yaml:"group"`
+}
+
+type partitionValue struct {
+	FSType   string `json:"fsType"            yaml:"fsType"`
+	PartType string `json:"partType"          yaml:"partType"`
+	Arch     string `json:"arch"              yaml:"arch"`
+	Variant  string `json:"variant,omitempty" yaml:"variant,omitempty"`
+	OS       string `json:"os,omitempty"      yaml:"os,omitempty"`
+}
+
+type signatureValue struct {
+	HashType string `json:"hashType"         yaml:"hashType"`
+	Entity   string `json:"entity,omitempty" yaml:"entity,omitempty"`
+}
+
+type cryptoMessageValue struct {
+	FormatType  string `json:"formatType"  yaml:"formatType"`
+	MessageType string `json:"messageType" yaml:"messageType"`
+}
+
+type sbomValue struct {
+	Format string `json:"format" yaml:"format"`
+}
+
+type ociBlobValue struct {
+	Digest string `json:"digest" yaml:"digest"`
+}
+
+// Schema identifiers of enumerated values. Unlike the String methods of the corresponding sif
+// types, these are stable.
+var (
+	dataTypeNames = map[sif.DataType]string{
+		sif.DataDeffile:       "deffile",
+		sif.DataEnvVar:        "envvars",
+		sif.DataLabels:        "labels",
+		sif.DataPartition:     "partition",
+		sif.DataSignature:     "signature",
+		sif.DataGenericJSON:   "generic-json",
+		sif.DataGeneric:       "generic",
+		sif.DataCryptoMessage: "crypto-message",
+		sif.DataSBOM:          "sbom",
+		sif.DataOCIRootIndex:  "oci-root-index",
+		sif.DataOCIBlob:       "oci-blob",
+	}
+
+	fsTypeNames = map[sif.FSType]string{
+		sif.FsSquash:            "squashfs",
+		sif.FsExt3:              "ext3",
+		sif.FsImmuObj:           "archive",
+		sif.FsRaw:               "raw",
+		sif.FsEncryptedSquashfs: "encrypted-squashfs",
+	}
+
+	partTypeNames = map[sif.PartType]string{
+		sif.PartSystem:  "system",
+		sif.PartPrimSys: "primary-system",
+		sif.PartData:    "data",
+		sif.PartOverlay: "overlay",
+	}
+
+	formatTypeNames = map[sif.FormatType]string{
+		sif.FormatOpenPGP: "openpgp",
+		sif.FormatPEM:     "pem",
+	}
+
+	messageTypeNames = map[sif.MessageType]string{
+		sif.MessageClearSignature: "clear-signature",
+		sif.MessageRSAOAEP:        "rsa-oaep",
+	}
+
+	hashTypeNames = map[crypto.Hash]string{
+		crypto.SHA256:      "SHA-256",
+		crypto.SHA384:      "SHA-384",
+		crypto.SHA512:      "SHA-512",
+		crypto.BLAKE2s_256: "BLAKE2s-256",
+		crypto.BLAKE2b_256: "BLAKE2b-256",
+	}
+
+	sbomFormatNames = map[sif.SBOMFormat]string{
+		sif.SBOMFormatCycloneDXJSON: "cyclonedx-json",
+		sif.SBOMFormatCycloneDXXML:  "cyclonedx-xml",
+		sif.SBOMFormatGitHubJSON:    "github-json",
+		sif.SBOMFormatSPDXJSON:      "spdx-json",
+		sif.SBOMFormatSPDXRDF:       "spdx-rdf",
+		sif.SBOMFormatSPDXTagValue:  "spdx-tag-value",
+		sif.SBOMFormatSPDXYAML:      "spdx-yaml",
+		sif.SBOMFormatSyftJSON:      "syft-json",
+	}
+)
+
+// schemaName returns the schema identifier of v, or "unknown" if v is not recognized.
+func schemaName[K comparable](names map[K]string, v K) string {
+	if s, ok := names[v]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// optionalTime returns a pointer to t in UTC, or nil if t is the zero time.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
+
+// newHeaderValue returns the structured representation of the global header of f. If f contains
+// primary system partitions for multiple architectures, the partition for hostArch is identified.
+func newHeaderValue(f *sif.FileImage, hostArch string) headerValue {
+	v := headerValue{
+		LaunchScript:      f.LaunchScript(),
+		Version:           f.Version(),
+		CreatedAt:         optionalTime(f.CreatedAt()),
+		ModifiedAt:        optionalTime(f.ModifiedAt()),
+		DescriptorsFree:   f.DescriptorsFree(),
+		DescriptorsTotal:  f.DescriptorsTotal(),
+		DescriptorsOffset: f.DescriptorsOffset(),
+		DescriptorsSize:   f.DescriptorsSize(),
+		DataOffset:        f.DataOffset(),
+		DataSize:          f.DataSize(),
+	}
+
+	if arch := f.PrimaryArch(); arch != "unknown" {
+		v.PrimaryArch = arch
+	}
+
+	if archs := getPrimaryArchs(f); len(archs) > 1 {
+		v.PrimaryArchs = archs
+	}
+
+	v.HostPartitionID = getHostPartitionID(f, hostArch)
+
+	if id := f.ID(); id != uuid.Nil.String() {
+		v.ID = id
+	}
+
+	return v
+}
+
+// newDescriptorValue returns the structured representation of d.
+func newDescriptorValue(d sif.Descriptor) (descriptorValue, error) {
+	v := descriptorValue{
+		ID:         d.ID(),
+		DataType:   schemaName(dataTypeNames, d.DataType()),
+		GroupID:    d.GroupID(),
+		Offset:     d.Offset(),
+		Size:       d.Size(),
+		CreatedAt:  optionalTime(d.CreatedAt()),
+		ModifiedAt: optionalTime(d.ModifiedAt()),
+		Name:       d.Name(),
+	}
+
+	if id, isGroup := d.LinkedID(); id != 0 {
+		v.Link = &linkValue{ID: id, Group: isGroup}
+	}
+
+	annotations, err := d.Annotations()
+	if err != nil {
+		return descriptorValue{}, err
+	}
+
+	if len(annotations) > 0 {
+		v.Annotations = annotations
+	}
+
+	switch d.DataType() {
+	case sif.DataPartition:
+		fs, pt, arch, err := d.PartitionMetadata()
+		if err != nil {
+			return descriptorValue{}, err
+		}
+
+		p, err := d.PartitionPlatform()
+		if err != nil {
+			return descriptorValue{}, err
+		}
+
+		v.Partition = &partitionValue{
+			FSType:   schemaName(fsTypeNames, fs),
+			PartType: schemaName(partTypeNames, pt),
+			Arch:     arch,
+			Variant:  p.Variant,
+			OS:       p.OS,
+		}
+
+	case sif.DataSignature:
+		ht, fp, err := d.SignatureMetadata()
+		if err != nil {
+			return descriptorValue{}, err
+		}
+
+		v.Signature = &signatureValue{
+			HashType: schemaName(hashTypeNames, ht),
+			Entity:   hex.EncodeToString(fp),
+		}
+
+	case sif.DataCryptoMessage:
+		ft, mt, err := d.CryptoMessageMetadata()
+		if err != nil {
+			return descriptorValue{}, err
+		}
+
+		v.CryptoMessage = &cryptoMessageValue{
+			FormatType:  schemaName(formatTypeNames, ft),
+			MessageType: schemaName(messageTypeNames, mt),
+		}
+
+	case sif.DataSBOM:
+		f, err := d.SBOMMetadata()
+		if err != nil {
+			return descriptorValue{}, err
+		}
+
+		v.SBOM = &sbomValue{Format: schemaName(sbomFormatNames, f)}
+
+	case sif.DataOCIRootIndex, sif.DataOCIBlob:
+		h, err := d.OCIBlobDigest()
+		if err != nil {
+			return descriptorValue{}, err
+		}
+
+		v.OCIBlob = &ociBlobValue{Digest: h.String()}
+	}
+
+	return v, nil
+}
+
+// writeStructuredList writes the list of descriptors in f to w in the specified format. If f
+// contains primary system partitions for multiple architectures, the partition for hostArch is
+// identified.
+func writeStructuredList(w io.Writer, format OutputFormat, f *sif.FileImage, hostArch string) error {
+	hostID := getHostPartitionID(f, hostArch)
+
+	o := listOutput{
+		SchemaVersion: outputSchemaVersion,
+		Descriptors:   make([]descriptorValue, 0, f.DescriptorsTotal()-f.DescriptorsFree()),
+	}
+
+	var err error
+	f.WithDescriptors(func(d sif.Descriptor) bool {
+		var v descriptorValue
+		if v, err = newDescriptorValue(d); err != nil {
+			return true
+		}
+		v.Host = d.ID() == hostID
+		o.Descriptors = append(o.Descriptors, v)
+		return false
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeStructured(w, format, o)
+}
+
+// writeStructured writes v to w in the specified format.
+func writeStructured(w io.Writer, format OutputFormat, v any) error {
+	switch format {
+	case OutputFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+
+	case OutputFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	return fmt.Errorf("%w: %v", errUnsupportedOutputFormat, format)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+func Test_schemaName(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"HashSHA256", schemaName(hashTypeNames, crypto.SHA256), "SHA-256"},
+		{"HashBLAKE2b", schemaName(hashTypeNames, crypto.BLAKE2b_256), "BLAKE2b-256"},
+		{"HashUnknown", schemaName(hashTypeNames, crypto.MD5), "unknown"},
+		{"SBOMFormatSPDXJSON", schemaName(sbomFormatNames, sif.SBOMFormatSPDXJSON), "spdx-json"},
+		{"SBOMFormatSyftJSON", schemaName(sbomFormatNames, sif.SBOMFormatSyftJSON), "syft-json"},
+		{"SBOMFormatUnknown", schemaName(sbomFormatNames, sif.SBOMFormat(0)), "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
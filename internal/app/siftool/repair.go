@@ -0,0 +1,38 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"fmt"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// Repair repairs recoverable structural problems in a SIF file, and displays the repairs made. If
+// dryRun is true, the repairs are displayed, but not made.
+func (a *App) Repair(path string, dryRun bool) error {
+	return withFileImage(path, !dryRun, func(f *sif.FileImage) error {
+		fixes, err := sif.Repair(f, sif.OptRepairDryRun(dryRun))
+		if err != nil {
+			return err
+		}
+
+		if len(fixes) == 0 {
+			fmt.Fprintln(a.opts.out, "No repairs required.")
+			return nil
+		}
+
+		for _, x := range fixes {
+			fmt.Fprintln(a.opts.out, x)
+		}
+
+		if dryRun {
+			fmt.Fprintln(a.opts.out, "Dry run, image not modified.")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestApp_Repair(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		truncate int64
+		dryRun   bool
+		wantErr  error
+	}{
+		{
+			name:    "NotExist",
+			image:   "not-exist.sif",
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name:  "OneGroup",
+			image: "one-group.sif",
+		},
+		{
+			name:     "Truncated",
+			image:    "one-group.sif",
+			truncate: 36864,
+		},
+		{
+			name:     "TruncatedDryRun",
+			image:    "one-group.sif",
+			truncate: 36864,
+			dryRun:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := "not-exist.sif"
+
+			if tt.wantErr == nil {
+				path = copyTestImage(t, tt.image)
+			}
+
+			if tt.truncate > 0 {
+				if err := os.Truncate(path, tt.truncate); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var b bytes.Buffer
+
+			a, err := New(OptAppOutput(&b))
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			if got, want := a.Repair(path, tt.dryRun), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if tt.wantErr == nil {
+				g := goldie.New(t, goldie.WithTestNameForDir(true))
+				g.Assert(t, tt.name, b.Bytes())
+
+				// Check image following repair.
+				b.Reset()
+
+				err := a.Check(path)
+				if got, want := err != nil, tt.dryRun; got != want {
+					t.Errorf("got check error %v, want error %v", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sylabs/sif/v2/pkg/integrity"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// Sign adds digital signature(s) to the SIF file at path, according to opts.
+func (*App) Sign(path string, opts ...integrity.SignerOpt) error {
+	return withFileImage(path, true, func(f *sif.FileImage) error {
+		s, err := integrity.NewSigner(f, opts...)
+		if err != nil {
+			return err
+		}
+
+		return s.Sign()
+	})
+}
+
+// keyDescription returns a human-readable description of pub.
+func keyDescription(pub crypto.PublicKey) string {
+	var alg string
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		alg = fmt.Sprintf("RSA (%d bits)", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		alg = fmt.Sprintf("ECDSA (%s)", k.Curve.Params().Name)
+	case ed25519.PublicKey:
+		alg = "Ed25519"
+	default:
+		return "Unknown"
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return alg
+	}
+
+	sum := sha256.Sum256(der)
+	return fmt.Sprintf("%v SHA256:%v", alg, base64.RawStdEncoding.EncodeToString(sum[:]))
+}
+
+// writeVerifyResult writes a report describing r to w.
+func writeVerifyResult(w io.Writer, r integrity.VerifyResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	sig := r.Signature()
+
+	fmt.Fprintf(tw, "Signature ID:\t%v\n", sig.ID())
+
+	if ht, fp, err := sig.SignatureMetadata(); err == nil {
+		fmt.Fprintf(tw, "Hash Type:\t%v\n", ht)
+
+		if len(fp) > 0 {
+			fmt.Fprintf(tw, "Fingerprint:\t%X\n", fp)
+		}
+	}
+
+	if e := r.Entity(); e != nil {
+		fmt.Fprintf(tw, "Entity:\t%X\n", e.PrimaryKey.Fingerprint)
+	}
+
+	for _, k := range r.Keys() {
+		fmt.Fprintf(tw, "Key:\t%v\n", keyDescription(k))
+	}
+
+	if ods := r.Verified(); len(ods) > 0 {
+		ids := make([]string, 0, len(ods))
+		for _, od := range ods {
+			ids = append(ids, fmt.Sprint(od.ID()))
+		}
+
+		fmt.Fprintf(tw, "Verified Objects:\t%v\n", strings.Join(ids, ", "))
+	}
+
+	if err := r.Error(); err != nil {
+		fmt.Fprintf(tw, "Status:\tFAILED (%v)\n", err)
+	} else {
+		fmt.Fprintf(tw, "Status:\tOK\n")
+	}
+
+	fmt.Fprintln(tw)
+
+	return tw.Flush()
+}
+
+// Verify verifies the digital signature(s) in the SIF file at path, according to opts. A report
+// is written for each signature that is examined.
+func (a *App) Verify(path string, opts ...integrity.VerifierOpt) error {
+	return withFileImage(path, false, func(f *sif.FileImage) error {
+		var werr error
+
+		opts = append(opts, integrity.OptVerifyCallback(func(r integrity.VerifyResult) bool {
+			if err := writeVerifyResult(a.opts.out, r); err != nil && werr == nil {
+				werr = err
+			}
+			return false
+		}))
+
+		v, err := integrity.NewVerifier(f, opts...)
+		if err != nil {
+			return err
+		}
+
+		if err := v.Verify(); err != nil {
+			return err
+		}
+
+		return werr
+	})
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/sebdah/goldie/v2"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sylabs/sif/v2/pkg/integrity"
+)
+
+var keys = filepath.Join("..", "..", "..", "test", "keys")
+
+// getTestEntityList returns the fixed test PGP entity list.
+func getTestEntityList(t *testing.T) openpgp.EntityList {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join(keys, "private.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	el, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return el
+}
+
+// getTestSigner returns a Signer read from the PEM file with the specified name.
+func getTestSigner(t *testing.T, name string) signature.Signer { //nolint:ireturn
+	t.Helper()
+
+	s, err := signature.LoadSignerFromPEMFile(filepath.Join(keys, name), crypto.SHA256, cryptoutils.SkipPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// getTestVerifier returns a Verifier read from the PEM file with the specified name.
+func getTestVerifier(t *testing.T, name string) signature.Verifier { //nolint:ireturn
+	t.Helper()
+
+	v, err := signature.LoadVerifierFromPEMFile(filepath.Join(keys, name), crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+// copyTestImage copies the test image with the specified name to a temporary directory, and
+// returns the path of the copy.
+func copyTestImage(t *testing.T, name string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join(corpus, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestApp_Sign(t *testing.T) {
+	signTime := func() time.Time { return time.Date(2020, 6, 30, 0, 1, 56, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		path       string
+		opts       []integrity.SignerOpt
+		verifyOpts []integrity.VerifierOpt
+		wantErr    error
+	}{
+		{
+			name:    "NoKeyMaterial",
+			path:    "one-group.sif",
+			wantErr: integrity.ErrNoKeyMaterial,
+		},
+		{
+			name: "ED25519",
+			path: "one-group.sif",
+			opts: []integrity.SignerOpt{
+				integrity.OptSignWithSigner(getTestSigner(t, "ed25519-private.pem")),
+			},
+			verifyOpts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithVerifier(getTestVerifier(t, "ed25519-public.pem")),
+			},
+		},
+		{
+			name: "RSAGroup",
+			path: "two-groups.sif",
+			opts: []integrity.SignerOpt{
+				integrity.OptSignWithSigner(getTestSigner(t, "rsa-private.pem")),
+				integrity.OptSignGroup(2),
+			},
+			verifyOpts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithVerifier(getTestVerifier(t, "rsa-public.pem")),
+				integrity.OptVerifyGroup(2),
+			},
+		},
+		{
+			name: "PGPObjects",
+			path: "two-groups.sif",
+			opts: []integrity.SignerOpt{
+				integrity.OptSignWithEntity(getTestEntityList(t)[0]),
+				integrity.OptSignObjects(1, 3),
+			},
+			verifyOpts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithKeyRing(getTestEntityList(t)),
+				integrity.OptVerifyObject(1),
+				integrity.OptVerifyObject(3),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := New(OptAppOutput(io.Discard))
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			path := copyTestImage(t, tt.path)
+
+			opts := make([]integrity.SignerOpt, 0, len(tt.opts)+2)
+			opts = append(opts, tt.opts...)
+			opts = append(opts, integrity.OptSignWithTime(signTime), integrity.OptSignDeterministic())
+
+			if got, want := a.Sign(path, opts...), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if tt.wantErr == nil {
+				if err := a.Verify(path, tt.verifyOpts...); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestApp_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		opts    []integrity.VerifierOpt
+		wantErr error
+	}{
+		{
+			name:    "NotExist",
+			path:    "not-exist.sif",
+			wantErr: os.ErrNotExist,
+		},
+		{
+			name: "OneGroupSignedDSSE",
+			path: filepath.Join(corpus, "one-group-signed-dsse.sif"),
+			opts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithVerifier(getTestVerifier(t, "ed25519-public.pem")),
+			},
+		},
+		{
+			name: "OneGroupSignedPGP",
+			path: filepath.Join(corpus, "one-group-signed-pgp.sif"),
+			opts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithKeyRing(getTestEntityList(t)),
+			},
+		},
+		{
+			name: "TwoGroupsSignedPGPGroup",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			opts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithKeyRing(getTestEntityList(t)),
+				integrity.OptVerifyGroup(2),
+			},
+		},
+		{
+			name: "OneGroupSignedLegacyAll",
+			path: filepath.Join(corpus, "one-group-signed-legacy-all.sif"),
+			opts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithKeyRing(getTestEntityList(t)),
+				integrity.OptVerifyLegacyAll(),
+			},
+		},
+		{
+			name: "WrongKey",
+			path: filepath.Join(corpus, "one-group-signed-dsse.sif"),
+			opts: []integrity.VerifierOpt{
+				integrity.OptVerifyWithVerifier(getTestVerifier(t, "ecdsa-public.pem")),
+			},
+			wantErr: &integrity.SignatureNotValidError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			a, err := New(OptAppOutput(&b))
+			if err != nil {
+				t.Fatalf("failed to create app: %v", err)
+			}
+
+			if got, want := a.Verify(tt.path, tt.opts...), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if !errors.Is(tt.wantErr, os.ErrNotExist) {
+				g := goldie.New(t, goldie.WithTestNameForDir(true))
+				g.Assert(t, tt.name, b.Bytes())
+			}
+		})
+	}
+}
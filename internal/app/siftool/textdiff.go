@@ -0,0 +1,91 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// splitLines splits s into lines, ignoring a trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// maxDiffCells is the maximum size of the table used to compute a line-oriented diff, which
+// bounds the time and memory used. Content too large to diff is reported as differing.
+const maxDiffCells = 1 << 22
+
+// writeLineDiff writes a line-oriented diff of a and b to w. Each line of output is prefixed with
+// indent, followed by "-" for lines only in a, "+" for lines only in b, or " " for lines in both.
+//
+// Lines common to the start and end of a and b are matched directly. If the remaining lines are
+// too numerous to diff, a single line prefixed with "!", noting that the content differs, is
+// written in their place.
+func writeLineDiff(w io.Writer, indent string, a, b []string) {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, l := range a[:prefix] {
+		fmt.Fprintf(w, "%s %s\n", indent, l)
+	}
+
+	if ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]; (len(ma)+1)*(len(mb)+1) > maxDiffCells {
+		fmt.Fprintf(w, "%s!content differs: %d lines removed, %d lines added (too large to diff)\n",
+			indent, len(ma), len(mb))
+	} else {
+		writeLCSDiff(w, indent, ma, mb)
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		fmt.Fprintf(w, "%s %s\n", indent, l)
+	}
+}
+
+// writeLCSDiff writes a line-oriented diff of a and b to w, as described for writeLineDiff, using
+// a table of the longest common subsequences of a and b.
+func writeLCSDiff(w io.Writer, indent string, a, b []string) {
+	// Compute the lengths of the longest common subsequences of each suffix of a and b.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(w, "%s %s\n", indent, a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(w, "%s-%s\n", indent, a[i])
+			i++
+		default:
+			fmt.Fprintf(w, "%s+%s\n", indent, b[j])
+			j++
+		}
+	}
+}
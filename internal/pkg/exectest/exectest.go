@@ -0,0 +1,29 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+// Package exectest provides utilities for testing code that runs external commands.
+package exectest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Script writes an executable shell script with the specified name and body to a temporary
+// directory, and returns its path. The directory is removed when t and all its subtests complete.
+func Script(t testing.TB, name, body string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	script := "#!/bin/sh\n" + body + "\n"
+
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil { //nolint:gosec // Must be executable.
+		t.Fatal(err)
+	}
+
+	return path
+}
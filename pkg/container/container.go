@@ -0,0 +1,96 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package container
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// options accumulates container configuration options.
+type options struct {
+	groupID uint32
+	t       *time.Time
+}
+
+// Opt are used to specify container configuration options.
+type Opt func(*options) error
+
+// OptGroupID specifies groupID as the object group containing the container configuration.
+func OptGroupID(groupID uint32) Opt {
+	return func(o *options) error {
+		if groupID == 0 {
+			return sif.ErrInvalidGroupID
+		}
+		o.groupID = groupID
+		return nil
+	}
+}
+
+// OptWithTime specifies t as the image/object modification time when updating the container
+// configuration.
+func OptWithTime(t time.Time) Opt {
+	return func(o *options) error {
+		o.t = &t
+		return nil
+	}
+}
+
+// getOptions returns options populated with defaults, and modified according to opts.
+func getOptions(opts ...Opt) (options, error) {
+	o := options{
+		groupID: sif.DefaultObjectGroup,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return options{}, fmt.Errorf("%w", err)
+		}
+	}
+
+	return o, nil
+}
+
+// latest returns the most recently created data object of type dt in the object group with
+// groupID. If multiple data objects have the same creation time, the one with the highest ID is
+// returned. If no such data object is found, an error wrapping sif.ErrObjectNotFound is returned.
+func latest(f *sif.FileImage, dt sif.DataType, groupID uint32) (sif.Descriptor, error) {
+	var d sif.Descriptor
+
+	for od, err := range f.Descriptors(sif.WithDataType(dt), sif.WithGroupID(groupID)) {
+		if err != nil {
+			return sif.Descriptor{}, err
+		}
+
+		if t := od.CreatedAt(); d.ID() == 0 || t.After(d.CreatedAt()) || (t.Equal(d.CreatedAt()) && od.ID() > d.ID()) {
+			d = od
+		}
+	}
+
+	if d.ID() == 0 {
+		return sif.Descriptor{}, fmt.Errorf("%w", sif.ErrObjectNotFound)
+	}
+
+	return d, nil
+}
+
+// getData returns the content of the most recently created data object of type dt, according to
+// opts.
+func getData(f *sif.FileImage, dt sif.DataType, opts ...Opt) ([]byte, error) {
+	o, err := getOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := latest(f, dt, o.groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.GetData()
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+var errInvalidHeaderLine = errors.New("invalid header line")
+
+// sectionNames are the names of the sections recognized in a definition file.
+var sectionNames = []string{
+	"help", "setup", "files", "labels", "environment", "pre", "post", "runscript", "test",
+	"startscript", "arguments", "appinstall", "appfiles", "appenv", "applabels", "apphelp",
+	"apprun", "appstart", "apptest",
+}
+
+// Section is a section of a definition file, such as %post or %environment.
+type Section struct {
+	Name    string // Name of section, in lower case and without the leading '%'.
+	Args    string // Arguments following the section name, such as the name of an app.
+	Content string // Content of section, without leading or trailing blank lines.
+}
+
+// Definition is a definition file used to build a container.
+type Definition struct {
+	Header   map[string]string // Header keywords, in lower case, and their values.
+	Sections []Section         // Sections, in the order they appear.
+}
+
+// Section returns the first section of d with the specified name.
+func (d Definition) Section(name string) (Section, bool) {
+	for _, s := range d.Sections {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
+
+// parseSectionLine returns the section started by line. If line does not start a section, ok is
+// false.
+func parseSectionLine(line string) (Section, bool) {
+	rest, ok := strings.CutPrefix(line, "%")
+	if !ok {
+		return Section{}, false
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || !slices.Contains(sectionNames, strings.ToLower(fields[0])) {
+		return Section{}, false
+	}
+
+	args := strings.TrimPrefix(strings.TrimSpace(rest), fields[0])
+
+	return Section{Name: strings.ToLower(fields[0]), Args: strings.TrimSpace(args)}, true
+}
+
+// trimContent returns content with leading and trailing blank lines removed.
+func trimContent(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// parseDeffile parses the definition file read from r.
+func parseDeffile(r io.Reader) (Definition, error) {
+	d := Definition{
+		Header: make(map[string]string),
+	}
+
+	var cur *Section
+	var lines []string
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+
+		if sec, ok := parseSectionLine(line); ok {
+			if cur != nil {
+				cur.Content = trimContent(lines)
+				d.Sections = append(d.Sections, *cur)
+			}
+
+			cur, lines = &sec, nil
+			continue
+		}
+
+		if cur != nil {
+			lines = append(lines, line)
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			return Definition{}, fmt.Errorf("%w: %q", errInvalidHeaderLine, line)
+		}
+
+		d.Header[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+	}
+
+	if err := s.Err(); err != nil {
+		return Definition{}, err
+	}
+
+	if cur != nil {
+		cur.Content = trimContent(lines)
+		d.Sections = append(d.Sections, *cur)
+	}
+
+	return d, nil
+}
+
+// Deffile returns the definition file used to build the container in f, according to opts. If no
+// definition file object is found, an error wrapping sif.ErrObjectNotFound is returned.
+//
+// Lines before the first section are parsed as header keywords. Each section extends until the
+// next line that starts with a recognized section name, such as %post or %environment. Header
+// keywords of subsequent stages of a multi-stage definition are returned as section content.
+//
+// By default, the definition file is read from the DefaultObjectGroup object group. To override
+// this, consider using OptGroupID.
+func Deffile(f *sif.FileImage, opts ...Opt) (Definition, error) {
+	b, err := getData(f, sif.DataDeffile, opts...)
+	if err != nil {
+		return Definition{}, fmt.Errorf("%w", err)
+	}
+
+	d, err := parseDeffile(bytes.NewReader(b))
+	if err != nil {
+		return Definition{}, fmt.Errorf("failed to parse definition file: %w", err)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package container
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+func TestDeffile(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []object
+		opts    []Opt
+		wantDef Definition
+		wantErr error
+	}{
+		{
+			name:    "NoObjects",
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name: "HeaderOnly",
+			objects: []object{
+				{dt: sif.DataDeffile, data: "# Comment\nBootstrap: docker\nFrom:  alpine:3.20 \n"},
+			},
+			wantDef: Definition{
+				Header: map[string]string{
+					"bootstrap": "docker",
+					"from":      "alpine:3.20",
+				},
+			},
+		},
+		{
+			name: "Sections",
+			objects: []object{
+				{dt: sif.DataDeffile, data: "bootstrap: docker\n" +
+					"from: alpine\n" +
+					"\n" +
+					"%POST -c /bin/bash\n" +
+					"    apk add curl\n" +
+					"\n" +
+					"    printf '%s\\n' done\n" +
+					"\n" +
+					"%environment\n" +
+					"    export A=1\n" +
+					"%labels\n" +
+					"%apprun foo\n" +
+					"    exec foo \"$@\"\n" +
+					"%unknown\n"},
+			},
+			wantDef: Definition{
+				Header: map[string]string{
+					"bootstrap": "docker",
+					"from":      "alpine",
+				},
+				Sections: []Section{
+					{Name: "post", Args: "-c /bin/bash", Content: "    apk add curl\n\n    printf '%s\\n' done"},
+					{Name: "environment", Content: "    export A=1"},
+					{Name: "labels"},
+					{Name: "apprun", Args: "foo", Content: "    exec foo \"$@\"\n%unknown"},
+				},
+			},
+		},
+		{
+			name: "InvalidHeaderLine",
+			objects: []object{
+				{dt: sif.DataDeffile, data: "bootstrap docker\n"},
+			},
+			wantErr: errInvalidHeaderLine,
+		},
+		{
+			name: "Latest",
+			objects: []object{
+				{dt: sif.DataDeffile, data: "bootstrap: docker\n"},
+				{dt: sif.DataDeffile, data: "bootstrap: library\n"},
+			},
+			wantDef: Definition{
+				Header: map[string]string{
+					"bootstrap": "library",
+				},
+			},
+		},
+		{
+			name: "GroupID",
+			objects: []object{
+				{dt: sif.DataDeffile, data: "bootstrap: docker\n"},
+				{dt: sif.DataDeffile, data: "bootstrap: library\n", opts: []sif.DescriptorInputOpt{
+					sif.OptGroupID(2),
+				}},
+			},
+			opts: []Opt{OptGroupID(2)},
+			wantDef: Definition{
+				Header: map[string]string{
+					"bootstrap": "library",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImage(t, tt.objects...)
+
+			def, err := Deffile(f, tt.opts...)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := def, tt.wantDef; !reflect.DeepEqual(got, want) {
+				t.Errorf("got definition %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDefinition_Section(t *testing.T) {
+	d := Definition{
+		Sections: []Section{
+			{Name: "post", Content: "a"},
+			{Name: "apprun", Args: "foo", Content: "b"},
+			{Name: "apprun", Args: "bar", Content: "c"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		section     string
+		wantSection Section
+		wantOK      bool
+	}{
+		{"Found", "post", Section{Name: "post", Content: "a"}, true},
+		{"First", "apprun", Section{Name: "apprun", Args: "foo", Content: "b"}, true},
+		{"NotFound", "test", Section{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := d.Section(tt.section)
+			if got, want := ok, tt.wantOK; got != want {
+				t.Errorf("got ok %v, want %v", got, want)
+			}
+
+			if got, want := s, tt.wantSection; !reflect.DeepEqual(got, want) {
+				t.Errorf("got section %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+/*
+Package container implements functions to read and update the container configuration stored in a
+SIF image, such as labels, environment variables and the definition file used to build the
+container.
+
+Each of these is stored in a data object of a specific type within an object group. If an object
+group contains more than one data object of a given type, the object created most recently takes
+precedence. By default, the DefaultObjectGroup object group is used. To override this, consider
+using OptGroupID.
+
+# Labels
+
+To obtain the labels of a container:
+
+	labels, err := container.Labels(f)
+
+To set the labels of a container:
+
+	err := container.SetLabels(f, labels)
+
+# Environment
+
+To obtain the environment variables set by the environment script of a container:
+
+	vars, err := container.EnvVars(f)
+
+# Definition File
+
+To obtain the definition file used to build a container:
+
+	def, err := container.Deffile(f)
+*/
+package container
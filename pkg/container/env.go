@@ -0,0 +1,121 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// EnvVar is an environment variable set by the environment script of a container.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// isName returns true if s is a valid shell variable name.
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, c := range s {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// unquote returns the value of the shell word s. If s is not a single word, ok is false.
+//
+//nolint:nonamedreturns // Named returns effective as documentation.
+func unquote(s string) (value string, ok bool) {
+	switch {
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		value = s[1 : len(s)-1]
+		return value, !strings.Contains(value, "'")
+
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		var sb strings.Builder
+
+		for i := 1; i < len(s)-1; i++ {
+			c := s[i]
+
+			if c == '\\' && i+1 < len(s)-1 && strings.IndexByte("$`\"\\", s[i+1]) >= 0 {
+				i++
+				c = s[i]
+			} else if c == '"' {
+				return "", false
+			}
+
+			sb.WriteByte(c)
+		}
+
+		return sb.String(), true
+
+	default:
+		return s, !strings.ContainsAny(s, " \t'\"")
+	}
+}
+
+// parseEnv parses variable assignments from the environment script read from r.
+func parseEnv(r io.Reader) ([]EnvVar, error) {
+	var vars []EnvVar
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || !isName(name) {
+			continue
+		}
+
+		if value, ok = unquote(value); ok {
+			vars = append(vars, EnvVar{Name: name, Value: value})
+		}
+	}
+
+	return vars, s.Err()
+}
+
+// EnvVars returns the environment variables set by the environment script of the container in f,
+// according to opts. If no environment object is found, an error wrapping sif.ErrObjectNotFound
+// is returned.
+//
+// Variables are returned in the order they are set by the script. Only simple assignments, with
+// or without the export keyword, are recognized. Values are unquoted, but parameter expansion and
+// command substitution are not performed. Other shell code, including comments, is ignored.
+//
+// By default, the environment script is read from the DefaultObjectGroup object group. To
+// override this, consider using OptGroupID.
+func EnvVars(f *sif.FileImage, opts ...Opt) ([]EnvVar, error) {
+	b, err := getData(f, sif.DataEnvVar, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	vars, err := parseEnv(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment: %w", err)
+	}
+
+	return vars, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package container
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+func TestEnvVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		objects  []object
+		opts     []Opt
+		wantVars []EnvVar
+		wantErr  error
+	}{
+		{
+			name:    "NoObjects",
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name: "Empty",
+			objects: []object{
+				{dt: sif.DataEnvVar},
+			},
+		},
+		{
+			name: "Assignments",
+			objects: []object{
+				{dt: sif.DataEnvVar, data: "#!/bin/sh\n" +
+					"# Custom environment shell code should follow\n" +
+					"\n" +
+					"export A=1\n" +
+					"B=2\n" +
+					"export\tC=3\n" +
+					"  export D=\n"},
+			},
+			wantVars: []EnvVar{
+				{Name: "A", Value: "1"},
+				{Name: "B", Value: "2"},
+				{Name: "C", Value: "3"},
+				{Name: "D", Value: ""},
+			},
+		},
+		{
+			name: "Quoted",
+			objects: []object{
+				{dt: sif.DataEnvVar, data: "export A='a b'\n" +
+					`export B="a \"b\" \$c \\ d"` + "\n" +
+					`export C="a\nb"` + "\n"},
+			},
+			wantVars: []EnvVar{
+				{Name: "A", Value: "a b"},
+				{Name: "B", Value: `a "b" $c \ d`},
+				{Name: "C", Value: `a\nb`},
+			},
+		},
+		{
+			name: "Ignored",
+			objects: []object{
+				{dt: sif.DataEnvVar, data: "if [ -z \"$A\" ]; then\n" +
+					"    export A=1\n" +
+					"fi\n" +
+					"export PATH\n" +
+					"export 1A=1\n" +
+					"export A-B=1\n" +
+					"export B=a b\n" +
+					"export C='a'b'\n" +
+					"export D=\"a\"b\"\n" +
+					"echo B=1\n"},
+			},
+			wantVars: []EnvVar{
+				{Name: "A", Value: "1"},
+			},
+		},
+		{
+			name: "Duplicate",
+			objects: []object{
+				{dt: sif.DataEnvVar, data: "export A=1\nexport A=2\n"},
+			},
+			wantVars: []EnvVar{
+				{Name: "A", Value: "1"},
+				{Name: "A", Value: "2"},
+			},
+		},
+		{
+			name: "Latest",
+			objects: []object{
+				{dt: sif.DataEnvVar, data: "export A=1\n"},
+				{dt: sif.DataEnvVar, data: "export A=2\n"},
+			},
+			wantVars: []EnvVar{
+				{Name: "A", Value: "2"},
+			},
+		},
+		{
+			name: "GroupID",
+			objects: []object{
+				{dt: sif.DataEnvVar, data: "export A=1\n"},
+				{dt: sif.DataEnvVar, data: "export A=2\n", opts: []sif.DescriptorInputOpt{
+					sif.OptGroupID(2),
+				}},
+			},
+			opts: []Opt{OptGroupID(2)},
+			wantVars: []EnvVar{
+				{Name: "A", Value: "2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImage(t, tt.objects...)
+
+			vars, err := EnvVars(f, tt.opts...)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := vars, tt.wantVars; !reflect.DeepEqual(got, want) {
+				t.Errorf("got vars %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package container
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// Labels returns the labels of the container in f, according to opts. If no labels object is
+// found, an error wrapping sif.ErrObjectNotFound is returned.
+//
+// By default, labels are read from the DefaultObjectGroup object group. To override this,
+// consider using OptGroupID.
+func Labels(f *sif.FileImage, opts ...Opt) (map[string]string, error) {
+	b, err := getData(f, sif.DataLabels, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	labels := make(map[string]string)
+	if err := json.Unmarshal(b, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// SetLabels sets the labels of the container in f to labels, according to opts.
+//
+// If the object group contains a labels object, the data of the most recently created labels
+// object is replaced. Otherwise, a new labels object is added to the object group. Any signatures
+// of the object group are invalidated.
+//
+// By default, labels are written to the DefaultObjectGroup object group. To override this,
+// consider using OptGroupID. By default, the image/object modification times are set according to
+// the defaults of the sif package. To override this, consider using OptWithTime.
+func SetLabels(f *sif.FileImage, labels map[string]string, opts ...Opt) error {
+	o, err := getOptions(opts...)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(labels)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	d, err := latest(f, sif.DataLabels, o.groupID)
+	if err == nil {
+		var ropts []sif.ReplaceOpt
+		if o.t != nil {
+			ropts = append(ropts, sif.OptReplaceWithTime(*o.t))
+		}
+
+		if _, err := f.ReplaceObject(d.ID(), bytes.NewReader(b), ropts...); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		return nil
+	} else if !errors.Is(err, sif.ErrObjectNotFound) {
+		return fmt.Errorf("%w", err)
+	}
+
+	di, err := sif.NewDescriptorInput(sif.DataLabels, bytes.NewReader(b), sif.OptGroupID(o.groupID))
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	var aopts []sif.AddOpt
+	if o.t != nil {
+		aopts = append(aopts, sif.OptAddWithTime(*o.t))
+	}
+
+	if err := f.AddObject(di, aopts...); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package container
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// object describes a data object to add to a test image.
+type object struct {
+	dt   sif.DataType
+	data string
+	opts []sif.DescriptorInputOpt
+}
+
+// newImage returns a FileImage containing the specified objects.
+func newImage(t *testing.T, objects ...object) *sif.FileImage {
+	t.Helper()
+
+	dis := make([]sif.DescriptorInput, 0, len(objects))
+	for _, o := range objects {
+		di, err := sif.NewDescriptorInput(o.dt, strings.NewReader(o.data), o.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dis = append(dis, di)
+	}
+
+	f, err := sif.CreateContainer(&sif.Buffer{},
+		sif.OptCreateDeterministic(),
+		sif.OptCreateWithDescriptors(dis...),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func TestLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		objects    []object
+		opts       []Opt
+		wantLabels map[string]string
+		wantErr    error
+	}{
+		{
+			name:    "NoObjects",
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name: "NoLabels",
+			objects: []object{
+				{dt: sif.DataDeffile, data: "bootstrap: docker"},
+			},
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name: "One",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1","b":"2"}`},
+			},
+			wantLabels: map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			name: "LatestID",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1"}`},
+				{dt: sif.DataLabels, data: `{"a":"2"}`},
+			},
+			wantLabels: map[string]string{"a": "2"},
+		},
+		{
+			name: "LatestTime",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1"}`, opts: []sif.DescriptorInputOpt{
+					sif.OptObjectTime(time.Unix(946702800, 0)),
+				}},
+				{dt: sif.DataLabels, data: `{"a":"2"}`},
+			},
+			wantLabels: map[string]string{"a": "1"},
+		},
+		{
+			name: "GroupID",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1"}`},
+				{dt: sif.DataLabels, data: `{"a":"2"}`, opts: []sif.DescriptorInputOpt{
+					sif.OptGroupID(2),
+				}},
+			},
+			opts:       []Opt{OptGroupID(2)},
+			wantLabels: map[string]string{"a": "2"},
+		},
+		{
+			name:    "InvalidGroupID",
+			opts:    []Opt{OptGroupID(0)},
+			wantErr: sif.ErrInvalidGroupID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImage(t, tt.objects...)
+
+			labels, err := Labels(f, tt.opts...)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := labels, tt.wantLabels; !reflect.DeepEqual(got, want) {
+				t.Errorf("got labels %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetLabels(t *testing.T) {
+	tests := []struct {
+		name        string
+		objects     []object
+		opts        []Opt
+		wantObjects int
+	}{
+		{
+			name:        "NoObjects",
+			wantObjects: 1,
+		},
+		{
+			name: "Add",
+			objects: []object{
+				{dt: sif.DataDeffile, data: "bootstrap: docker"},
+			},
+			wantObjects: 1,
+		},
+		{
+			name: "Replace",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1"}`},
+			},
+			wantObjects: 1,
+		},
+		{
+			name: "ReplaceLatest",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1"}`},
+				{dt: sif.DataLabels, data: `{"a":"2"}`},
+			},
+			wantObjects: 2,
+		},
+		{
+			name: "GroupID",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1"}`},
+			},
+			opts:        []Opt{OptGroupID(2)},
+			wantObjects: 2,
+		},
+		{
+			name: "WithTime",
+			objects: []object{
+				{dt: sif.DataLabels, data: `{"a":"1"}`},
+			},
+			opts:        []Opt{OptWithTime(time.Unix(946702800, 0))},
+			wantObjects: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newImage(t, tt.objects...)
+
+			want := map[string]string{"org.example.version": "1.0"}
+
+			if err := SetLabels(f, want, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Labels(f, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got labels %v, want %v", got, want)
+			}
+
+			ds, err := f.GetDescriptors(sif.WithDataType(sif.DataLabels))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := len(ds), tt.wantObjects; got != want {
+				t.Errorf("got %v labels objects, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// diffuse applies the LUKS anti-forensic diffusion function to b in place, using hash function h.
+func diffuse(b []byte, h func() hash.Hash) {
+	hh := h()
+	size := hh.Size()
+
+	var iv [4]byte
+
+	for i := 0; i*size < len(b); i++ {
+		chunk := b[i*size : min((i+1)*size, len(b))]
+
+		binary.BigEndian.PutUint32(iv[:], uint32(i)) //nolint:gosec // Bounded by len(b).
+
+		hh.Reset()
+		hh.Write(iv[:])
+		hh.Write(chunk)
+		copy(chunk, hh.Sum(nil))
+	}
+}
+
+// xorBytes sets dst[i] = dst[i] ^ src[i] for each i.
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// afSplit splits key into the specified number of stripes, using random data read from rand and
+// hash function h, as described in the LUKS specification.
+func afSplit(rand io.Reader, key []byte, stripes int, h func() hash.Hash) ([]byte, error) {
+	n := len(key)
+	b := make([]byte, n*stripes)
+	block := make([]byte, n)
+
+	for i := range stripes - 1 {
+		s := b[i*n : (i+1)*n]
+
+		if _, err := io.ReadFull(rand, s); err != nil {
+			return nil, fmt.Errorf("failed to read random data: %w", err)
+		}
+
+		xorBytes(block, s)
+		diffuse(block, h)
+	}
+
+	last := b[(stripes-1)*n:]
+	copy(last, key)
+	xorBytes(last, block)
+
+	return b, nil
+}
+
+// afMerge recovers a key of size n from the split key material in b, which contains the
+// specified number of stripes, using hash function h.
+func afMerge(b []byte, n, stripes int, h func() hash.Hash) []byte {
+	block := make([]byte, n)
+
+	for i := range stripes - 1 {
+		xorBytes(block, b[i*n:(i+1)*n])
+		diffuse(block, h)
+	}
+
+	xorBytes(block, b[(stripes-1)*n:stripes*n])
+
+	return block
+}
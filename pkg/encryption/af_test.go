@@ -0,0 +1,56 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"testing"
+)
+
+func Test_afSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		keySize int
+		stripes int
+		h       func() hash.Hash
+	}{
+		{name: "SHA256", keySize: 64, stripes: 4000, h: sha256.New},
+		{name: "SHA512", keySize: 64, stripes: 4000, h: sha512.New},
+		{name: "PartialBlock", keySize: 33, stripes: 10, h: sha256.New},
+		{name: "OneStripe", keySize: 32, stripes: 1, h: sha256.New},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := bytes.Repeat([]byte{0xa5}, tt.keySize)
+
+			b, err := afSplit(newTestRandom(), key, tt.stripes, tt.h)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := len(b), tt.keySize*tt.stripes; got != want {
+				t.Fatalf("got %v bytes, want %v", got, want)
+			}
+
+			if got := afMerge(b, tt.keySize, tt.stripes, tt.h); !bytes.Equal(got, key) {
+				t.Errorf("got key %x, want %x", got, key)
+			}
+
+			// Modifying any stripe must prevent recovery of the key.
+			if tt.stripes > 1 {
+				b[0] ^= 1
+
+				if got := afMerge(b, tt.keySize, tt.stripes, tt.h); bytes.Equal(got, key) {
+					t.Errorf("key recovered from modified key material")
+				}
+			}
+		})
+	}
+}
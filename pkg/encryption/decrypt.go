@@ -0,0 +1,76 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+var (
+	errNotPartition       = errors.New("descriptor is not a partition")
+	errNotEncrypted       = errors.New("partition is not encrypted")
+	errUnsupportedMessage = errors.New("unsupported crypto message")
+	errNoReaderAt         = errors.New("reader does not implement io.ReaderAt")
+)
+
+// NewReader returns a reader for the decrypted contents of the encrypted partition described by
+// d, which must be contained in f.
+//
+// The passphrase required to decrypt the partition is obtained from the crypto message linked to
+// the partition, which is decrypted using key.
+func NewReader(f *sif.FileImage, d sif.Descriptor, key *rsa.PrivateKey) (*io.SectionReader, error) {
+	if dt := d.DataType(); dt != sif.DataPartition {
+		return nil, fmt.Errorf("%w: %v", errNotPartition, dt)
+	}
+
+	fs, _, _, err := d.PartitionMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if fs != sif.FsEncryptedSquashfs {
+		return nil, fmt.Errorf("%w: %v", errNotEncrypted, fs)
+	}
+
+	m, err := f.GetDescriptor(
+		sif.WithDataType(sif.DataCryptoMessage),
+		sif.WithLinkedID(d.ID()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get crypto message: %w", err)
+	}
+
+	ft, mt, err := m.CryptoMessageMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if ft != sif.FormatPEM || mt != sif.MessageRSAOAEP {
+		return nil, fmt.Errorf("%w: %v/%v", errUnsupportedMessage, ft, mt)
+	}
+
+	b, err := m.GetData()
+	if err != nil {
+		return nil, err
+	}
+
+	passphrase, err := unwrapPassphrase(key, b)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := d.GetReader().(io.ReaderAt)
+	if !ok {
+		return nil, errNoReaderAt
+	}
+
+	return newLUKSReader(r, d.Size(), passphrase)
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+/*
+Package encryption implements functions to add and read encrypted squashfs partitions in a SIF
+image.
+
+An encrypted partition has a filesystem type of sif.FsEncryptedSquashfs, and contains a LUKS2
+device, the payload of which is a squashfs filesystem. The device is encrypted using
+aes-xts-plain64, and can be opened by cryptsetup. The passphrase that protects the device is
+stored in a crypto message object linked to the partition, encrypted using RSA-OAEP with SHA-256.
+
+# Encrypt
+
+To add an encrypted partition containing the squashfs filesystem read from r to a SIF image,
+with the passphrase encrypted using RSA public key pub:
+
+	err := encryption.AddSquashFS(f, r, pub)
+
+# Decrypt
+
+To read the decrypted contents of the encrypted partition described by d using RSA private key
+key:
+
+	r, err := encryption.NewReader(f, d, key)
+
+The returned reader implements io.ReaderAt, so the filesystem can be accessed using the squashfs
+package:
+
+	fsys, err := squashfs.NewFS(r)
+
+Keys in PEM format can be parsed using cryptoutils.UnmarshalPEMToPrivateKey and
+cryptoutils.UnmarshalPEMToPublicKey, from github.com/sigstore/sigstore/pkg/cryptoutils.
+*/
+package encryption
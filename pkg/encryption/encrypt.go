@@ -0,0 +1,146 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// passphraseSize is the size of the random passphrase used to protect the volume key.
+const passphraseSize = 32
+
+var errPartitionNotFound = errors.New("added partition not found")
+
+// encryptOpts accumulates encryption options.
+type encryptOpts struct {
+	pt   sif.PartType
+	arch string
+	rand io.Reader
+}
+
+// EncryptOpt are used to specify encryption options.
+type EncryptOpt func(*encryptOpts) error
+
+// OptEncryptPartitionType specifies pt as the partition type of the encrypted partition.
+func OptEncryptPartitionType(pt sif.PartType) EncryptOpt {
+	return func(eo *encryptOpts) error {
+		eo.pt = pt
+		return nil
+	}
+}
+
+// OptEncryptArch specifies arch as the architecture of the encrypted partition. The value of arch
+// is specified in the format of runtime.GOARCH.
+func OptEncryptArch(arch string) EncryptOpt {
+	return func(eo *encryptOpts) error {
+		eo.arch = arch
+		return nil
+	}
+}
+
+// OptEncryptRandomSource specifies r as the source of entropy used to generate keys.
+func OptEncryptRandomSource(r io.Reader) EncryptOpt {
+	return func(eo *encryptOpts) error {
+		eo.rand = r
+		return nil
+	}
+}
+
+// objectIDs returns the set of IDs of the objects in f.
+func objectIDs(f *sif.FileImage) map[uint32]bool {
+	ids := make(map[uint32]bool)
+	f.WithDescriptors(func(d sif.Descriptor) bool {
+		ids[d.ID()] = true
+		return false
+	})
+	return ids
+}
+
+// AddSquashFS adds an encrypted partition containing the squashfs filesystem read from r to f,
+// along with a crypto message linked to the partition, which contains the key required to
+// decrypt the partition encrypted with pub.
+//
+// The partition contains a LUKS2 device, encrypted using AES-XTS with a random volume key. The
+// volume key is protected by a random passphrase, which is encrypted with pub using RSA-OAEP, and
+// stored in the crypto message.
+//
+// By default, the partition is added as a primary system partition, with an architecture
+// matching the host. To override this, consider using OptEncryptPartitionType and/or
+// OptEncryptArch.
+//
+// By default, keys are generated using entropy from crypto/rand. To override this, consider using
+// OptEncryptRandomSource.
+func AddSquashFS(f *sif.FileImage, r io.Reader, pub *rsa.PublicKey, opts ...EncryptOpt) error {
+	eo := encryptOpts{
+		pt:   sif.PartPrimSys,
+		arch: runtime.GOARCH,
+		rand: rand.Reader,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&eo); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	passphrase := make([]byte, passphraseSize)
+	if _, err := io.ReadFull(eo.rand, passphrase); err != nil {
+		return fmt.Errorf("failed to generate passphrase: %w", err)
+	}
+
+	msg, err := wrapPassphrase(eo.rand, pub, passphrase)
+	if err != nil {
+		return err
+	}
+
+	w := luksWriter{rand: eo.rand, passphrase: passphrase}
+
+	lr, err := w.newReader(r)
+	if err != nil {
+		return err
+	}
+
+	di, err := sif.NewDescriptorInput(sif.DataPartition, lr,
+		sif.OptPartitionMetadata(sif.FsEncryptedSquashfs, eo.pt, eo.arch),
+	)
+	if err != nil {
+		return err
+	}
+
+	ids := objectIDs(f)
+
+	if err := f.AddObject(di); err != nil {
+		return err
+	}
+
+	d, err := f.GetDescriptor(func(d sif.Descriptor) (bool, error) { return !ids[d.ID()], nil })
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPartitionNotFound, err)
+	}
+
+	di, err = sif.NewDescriptorInput(sif.DataCryptoMessage, bytes.NewReader(msg),
+		sif.OptLinkedID(d.ID()),
+		sif.OptCryptoMessageMetadata(sif.FormatPEM, sif.MessageRSAOAEP),
+	)
+	if err == nil {
+		err = f.AddObject(di)
+	}
+
+	// Without the crypto message, the partition cannot be decrypted, so remove it.
+	if err != nil {
+		return errors.Join(err, f.DeleteObject(d.ID()))
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+	"github.com/sylabs/sif/v2/pkg/squashfs"
+)
+
+// makeEncryptedImage returns an image containing an encrypted partition holding the squashfs
+// filesystem at test/input/extract.squashfs, along with the contents of the filesystem.
+func makeEncryptedImage(t *testing.T, opts ...EncryptOpt) (*sif.FileImage, []byte) {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("..", "..", "test", "input", "extract.squashfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := sif.CreateContainer(sif.NewBuffer(nil),
+		sif.OptCreateDeterministic(),
+		sif.OptCreateWithDescriptorCapacity(8),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts = append([]EncryptOpt{OptEncryptRandomSource(newTestRandom())}, opts...)
+
+	if err := AddSquashFS(f, bytes.NewReader(b), getTestPublicKey(t, "rsa-public.pem"), opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	return f, b
+}
+
+func TestAddSquashFS(t *testing.T) {
+	f, want := makeEncryptedImage(t,
+		OptEncryptPartitionType(sif.PartData),
+		OptEncryptArch("arm64"),
+	)
+
+	d, err := f.GetDescriptor(sif.WithID(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fsType, pt, arch, err := d.PartitionMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fsType != sif.FsEncryptedSquashfs || pt != sif.PartData || arch != "arm64" {
+		t.Errorf("got partition metadata %v/%v/%v", fsType, pt, arch)
+	}
+
+	m, err := f.GetDescriptor(sif.WithID(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id, isGroup := m.LinkedID(); id != 1 || isGroup {
+		t.Errorf("got linked ID %v (group %v), want 1", id, isGroup)
+	}
+
+	if ft, mt, err := m.CryptoMessageMetadata(); err != nil {
+		t.Fatal(err)
+	} else if ft != sif.FormatPEM || mt != sif.MessageRSAOAEP {
+		t.Errorf("got crypto message metadata %v/%v", ft, mt)
+	}
+
+	r, err := NewReader(f, d, getTestPrivateKey(t, "rsa-private.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]byte, len(want))
+	if _, err := r.ReadAt(got, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("decrypted partition does not match")
+	}
+
+	fsys, err := squashfs.NewFS(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.ReadFile(fsys, "etc/os-release"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAddSquashFS_NoCapacity(t *testing.T) {
+	f, err := sif.CreateContainer(sif.NewBuffer(nil),
+		sif.OptCreateDeterministic(),
+		sif.OptCreateWithDescriptorCapacity(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = AddSquashFS(f, bytes.NewReader(nil), getTestPublicKey(t, "rsa-public.pem"),
+		OptEncryptRandomSource(newTestRandom()),
+	)
+	if err == nil {
+		t.Fatal("unexpected success")
+	}
+
+	// The partition must not be left behind without a crypto message.
+	if got, want := f.DescriptorsFree(), int64(1); got != want {
+		t.Errorf("got %v free descriptors, want %v", got, want)
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	f, _ := makeEncryptedImage(t)
+
+	generic, err := sif.NewDescriptorInput(sif.DataGeneric, bytes.NewReader([]byte{0xfa, 0xce}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	squash, err := sif.NewDescriptorInput(sif.DataPartition, bytes.NewReader([]byte{0xfa, 0xce}),
+		sif.OptPartitionMetadata(sif.FsSquash, sif.PartData, "amd64"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := sif.NewDescriptorInput(sif.DataPartition, bytes.NewReader([]byte{0xfa, 0xce}),
+		sif.OptPartitionMetadata(sif.FsEncryptedSquashfs, sif.PartData, "amd64"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, di := range []sif.DescriptorInput{generic, squash, encrypted} {
+		if err := f.AddObject(di); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		id      uint32
+		wantErr error
+	}{
+		{name: "OK", id: 1},
+		{name: "NotPartition", id: 3, wantErr: errNotPartition},
+		{name: "NotEncrypted", id: 4, wantErr: errNotEncrypted},
+		{name: "NoMessage", id: 5, wantErr: sif.ErrObjectNotFound},
+	}
+
+	key := getTestPrivateKey(t, "rsa-private.pem")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := f.GetDescriptor(sif.WithID(tt.id))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := NewReader(f, d, key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,661 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	luksBinaryHeaderSize = 4096      // Size of binary header.
+	luksMinHeaderSize    = 16 << 10  // Minimum size of header, including JSON area.
+	luksMaxHeaderSize    = 4 << 20   // Maximum size of header, including JSON area.
+	luksWriteHeaderSize  = 16 << 10  // Size of header used when writing headers.
+	luksVersion          = 2         // Supported LUKS version.
+	luksChecksumAlg      = "sha256"  // Checksum algorithm used when writing headers.
+	luksKeySize          = 64        // Size of volume key, for AES-256 in XTS mode.
+	luksStripes          = 4000      // Number of anti-forensic stripes.
+	luksSaltSize         = 32        // Size of salt used by KDFs.
+	luksIterations       = 1000      // PBKDF2 iterations used when writing headers.
+	luksSectorSize       = 512       // Sector size used when writing headers.
+	luksKeyslotAlignment = 4096      // Alignment of keyslot area.
+	luksHash             = "sha256"  // Hash used when writing headers.
+	luksKeyslotType      = "luks2"   // Supported keyslot type.
+	luksSegmentType      = "crypt"   // Supported segment type.
+	luksDynamicSize      = "dynamic" // Segment size indicating the segment extends to end of device.
+	luksMaxArgon2Time    = 100       // Maximum argon2 passes accepted when reading headers.
+	luksMaxArgon2Memory  = 1 << 20   // Maximum argon2 memory (KiB) accepted when reading headers.
+	luksMaxArgon2CPUs    = 16        // Maximum argon2 parallelism accepted when reading headers.
+)
+
+var (
+	luksMagicPrimary   = [6]byte{'L', 'U', 'K', 'S', 0xba, 0xbe}
+	luksMagicSecondary = [6]byte{'S', 'K', 'U', 'L', 0xba, 0xbe}
+)
+
+var (
+	errInvalidMagic         = errors.New("invalid LUKS magic")
+	errUnsupportedVersion   = errors.New("unsupported LUKS version")
+	errInvalidHeaderSize    = errors.New("invalid LUKS header size")
+	errInvalidChecksum      = errors.New("LUKS header checksum mismatch")
+	errUnsupportedHash      = errors.New("unsupported hash")
+	errUnsupportedKDF       = errors.New("unsupported key derivation function")
+	errUnsupportedKeyslot   = errors.New("unsupported keyslot")
+	errUnsupportedSegment   = errors.New("unsupported segment")
+	errUnsupportedDigest    = errors.New("unsupported digest")
+	errNoSegment            = errors.New("no encrypted segment found")
+	errIncorrectPassphrase  = errors.New("no keyslot matches passphrase")
+	errKeyslotAreaTooSmall  = errors.New("keyslot area too small for key material")
+	errSegmentOutOfBounds   = errors.New("segment extends beyond end of device")
+	errInvalidSectorSize    = errors.New("invalid sector size")
+	errInvalidKeyslotParams = errors.New("invalid keyslot parameters")
+	errInvalidKDFParams     = errors.New("invalid key derivation function parameters")
+)
+
+// luksBinaryHeader is the binary header at the start of each copy of the LUKS2 header.
+type luksBinaryHeader struct {
+	Magic       [6]byte
+	Version     uint16
+	HdrSize     uint64 // Size of header, including JSON area.
+	SeqID       uint64
+	Label       [48]byte
+	ChecksumAlg [32]byte
+	Salt        [64]byte
+	UUID        [40]byte
+	Subsystem   [48]byte
+	HdrOffset   uint64 // Offset of this header from start of device.
+	_           [184]byte
+	Csum        [64]byte
+	_           [7 * 512]byte
+}
+
+// luksMetadata is the JSON metadata that follows the binary header.
+type luksMetadata struct {
+	Keyslots map[string]luksKeyslot     `json:"keyslots"`
+	Tokens   map[string]json.RawMessage `json:"tokens"`
+	Segments map[string]luksSegment     `json:"segments"`
+	Digests  map[string]luksDigest      `json:"digests"`
+	Config   luksConfig                 `json:"config"`
+}
+
+type luksKeyslot struct {
+	Type    string   `json:"type"`
+	KeySize int      `json:"key_size"`
+	AF      luksAF   `json:"af"`
+	Area    luksArea `json:"area"`
+	KDF     luksKDF  `json:"kdf"`
+}
+
+type luksAF struct {
+	Type    string `json:"type"`
+	Stripes int    `json:"stripes"`
+	Hash    string `json:"hash"`
+}
+
+type luksArea struct {
+	Type       string `json:"type"`
+	Offset     int64  `json:"offset,string"`
+	Size       int64  `json:"size,string"`
+	Encryption string `json:"encryption"`
+	KeySize    int    `json:"key_size"`
+}
+
+type luksKDF struct {
+	Type       string `json:"type"`
+	Hash       string `json:"hash,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	Time       uint32 `json:"time,omitempty"`
+	Memory     uint32 `json:"memory,omitempty"`
+	CPUs       uint8  `json:"cpus,omitempty"`
+	Salt       []byte `json:"salt"`
+}
+
+type luksSegment struct {
+	Type       string `json:"type"`
+	Offset     int64  `json:"offset,string"`
+	Size       string `json:"size"`
+	IVTweak    uint64 `json:"iv_tweak,string"`
+	Encryption string `json:"encryption"`
+	SectorSize int64  `json:"sector_size"`
+}
+
+type luksDigest struct {
+	Type       string   `json:"type"`
+	Keyslots   []string `json:"keyslots"`
+	Segments   []string `json:"segments"`
+	Hash       string   `json:"hash"`
+	Iterations int      `json:"iterations"`
+	Salt       []byte   `json:"salt"`
+	Digest     []byte   `json:"digest"`
+}
+
+type luksConfig struct {
+	JSONSize     int64 `json:"json_size,string"`
+	KeyslotsSize int64 `json:"keyslots_size,string"`
+}
+
+// hashFunc returns the hash function with the specified name.
+func hashFunc(name string) (func() hash.Hash, error) {
+	switch name {
+	case "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("%w: %v", errUnsupportedHash, name)
+}
+
+// cString returns the NUL-terminated string in b.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// readLUKSHeaderAt reads and validates the LUKS2 header copy at offset off in r, with the
+// expected magic value.
+func readLUKSHeaderAt(r io.ReaderAt, off int64, magic [6]byte) (*luksMetadata, error) {
+	var bh luksBinaryHeader
+	if err := binary.Read(io.NewSectionReader(r, off, luksBinaryHeaderSize), binary.BigEndian, &bh); err != nil {
+		return nil, err
+	}
+
+	if bh.Magic != magic {
+		return nil, errInvalidMagic
+	}
+
+	if bh.Version != luksVersion {
+		return nil, fmt.Errorf("%w: %v", errUnsupportedVersion, bh.Version)
+	}
+
+	hdrSize := bh.HdrSize
+	if hdrSize < luksMinHeaderSize || hdrSize > luksMaxHeaderSize || hdrSize&(hdrSize-1) != 0 {
+		return nil, fmt.Errorf("%w: %v", errInvalidHeaderSize, hdrSize)
+	}
+
+	b := make([]byte, hdrSize)
+	if _, err := r.ReadAt(b, off); err != nil {
+		return nil, err
+	}
+
+	h, err := hashFunc(cString(bh.ChecksumAlg[:]))
+	if err != nil {
+		return nil, err
+	}
+
+	// The checksum is calculated with the checksum field zeroed.
+	csumOff := luksBinaryHeaderSize - 7*512 - len(bh.Csum)
+	hh := h()
+	csum := bh.Csum[:hh.Size()]
+	clear(b[csumOff : csumOff+len(bh.Csum)])
+	hh.Write(b)
+
+	if subtle.ConstantTimeCompare(hh.Sum(nil), csum) != 1 {
+		return nil, errInvalidChecksum
+	}
+
+	var md luksMetadata
+	if err := json.Unmarshal(bytes.TrimRight(b[luksBinaryHeaderSize:], "\x00"), &md); err != nil {
+		return nil, fmt.Errorf("failed to parse LUKS metadata: %w", err)
+	}
+
+	return &md, nil
+}
+
+// readLUKSHeader reads the LUKS2 metadata from r. If the primary header is invalid, the secondary
+// header is used.
+func readLUKSHeader(r io.ReaderAt) (*luksMetadata, error) {
+	md, err := readLUKSHeaderAt(r, 0, luksMagicPrimary)
+	if err == nil {
+		return md, nil
+	}
+
+	// The secondary header immediately follows the primary header, the size of which is unknown
+	// if the primary header is corrupt.
+	for off := int64(luksMinHeaderSize); off <= luksMaxHeaderSize; off <<= 1 {
+		if md, err := readLUKSHeaderAt(r, off, luksMagicSecondary); err == nil {
+			return md, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to read LUKS header: %w", err)
+}
+
+// checkArgon2 checks that the argon2 parameters of kdf are within the bounds accepted, so that a
+// crafted header cannot cause excessive memory or CPU use.
+func (kdf luksKDF) checkArgon2() error {
+	if kdf.Time < 1 || kdf.Time > luksMaxArgon2Time {
+		return fmt.Errorf("%w: time %v", errInvalidKDFParams, kdf.Time)
+	}
+
+	if kdf.Memory < 1 || kdf.Memory > luksMaxArgon2Memory {
+		return fmt.Errorf("%w: memory %v", errInvalidKDFParams, kdf.Memory)
+	}
+
+	if kdf.CPUs < 1 || kdf.CPUs > luksMaxArgon2CPUs {
+		return fmt.Errorf("%w: cpus %v", errInvalidKDFParams, kdf.CPUs)
+	}
+
+	return nil
+}
+
+// deriveKey derives a key of size n from passphrase using the KDF described by kdf. The
+// parameters of argon2 KDFs are rejected if they exceed the maximums accepted.
+func (kdf luksKDF) deriveKey(passphrase []byte, n int) ([]byte, error) {
+	switch kdf.Type {
+	case "pbkdf2":
+		h, err := hashFunc(kdf.Hash)
+		if err != nil {
+			return nil, err
+		}
+		return pbkdf2.Key(h, string(passphrase), kdf.Salt, kdf.Iterations, n)
+
+	case "argon2i":
+		if err := kdf.checkArgon2(); err != nil {
+			return nil, err
+		}
+		return argon2.Key(passphrase, kdf.Salt, kdf.Time, kdf.Memory, kdf.CPUs, uint32(n)), nil //nolint:gosec
+
+	case "argon2id":
+		if err := kdf.checkArgon2(); err != nil {
+			return nil, err
+		}
+		return argon2.IDKey(passphrase, kdf.Salt, kdf.Time, kdf.Memory, kdf.CPUs, uint32(n)), nil //nolint:gosec
+	}
+
+	return nil, fmt.Errorf("%w: %v", errUnsupportedKDF, kdf.Type)
+}
+
+// afSize returns the size of the anti-forensic key material stored in the keyslot area, which is
+// padded to a whole number of sectors.
+func (ks luksKeyslot) afSize() int64 {
+	n := int64(ks.KeySize) * int64(ks.AF.Stripes)
+	return (n + luksSectorSize - 1) / luksSectorSize * luksSectorSize
+}
+
+// decryptKey recovers the volume key from the keyslot ks, using passphrase. The returned key
+// must be verified using a digest.
+func (ks luksKeyslot) decryptKey(r io.ReaderAt, passphrase []byte) ([]byte, error) {
+	if ks.Type != luksKeyslotType || ks.AF.Type != "luks1" || ks.Area.Type != "raw" {
+		return nil, fmt.Errorf("%w: %v/%v/%v", errUnsupportedKeyslot, ks.Type, ks.AF.Type, ks.Area.Type)
+	}
+
+	if ks.KeySize <= 0 || ks.AF.Stripes <= 0 || ks.Area.Offset < 0 {
+		return nil, errInvalidKeyslotParams
+	}
+
+	h, err := hashFunc(ks.AF.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	n := ks.afSize()
+	if n > ks.Area.Size {
+		return nil, errKeyslotAreaTooSmall
+	}
+
+	key, err := ks.KDF.deriveKey(passphrase, ks.Area.KeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newSectorCipher(ks.Area.Encryption, key, luksSectorSize, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, n)
+	if _, err := r.ReadAt(b, ks.Area.Offset); err != nil {
+		return nil, fmt.Errorf("failed to read key material: %w", err)
+	}
+
+	c.decrypt(b, 0)
+
+	return afMerge(b, ks.KeySize, ks.AF.Stripes, h), nil
+}
+
+// verify reports whether key matches the digest.
+func (d luksDigest) verify(key []byte) (bool, error) {
+	if d.Type != "pbkdf2" {
+		return false, fmt.Errorf("%w: %v", errUnsupportedDigest, d.Type)
+	}
+
+	h, err := hashFunc(d.Hash)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := pbkdf2.Key(h, string(key), d.Salt, d.Iterations, len(d.Digest))
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(b, d.Digest) == 1, nil
+}
+
+// sortedKeys returns the keys of m, sorted numerically.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.SortedFunc(maps.Keys(m), func(a, b string) int {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	})
+}
+
+// unlock returns the volume key and the segment it decrypts, using passphrase.
+func (md *luksMetadata) unlock(r io.ReaderAt, passphrase []byte) ([]byte, luksSegment, error) {
+	// Keyslots that cannot be used are skipped, in case another keyslot matches the passphrase.
+	// If none do, the first such error is returned.
+	var firstErr error
+
+	for _, id := range sortedKeys(md.Digests) {
+		d := md.Digests[id]
+
+		for _, ksID := range d.Keyslots {
+			ks, ok := md.Keyslots[ksID]
+			if !ok {
+				continue
+			}
+
+			key, err := ks.decryptKey(r, passphrase)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("keyslot %v: %w", ksID, err)
+				}
+				continue
+			}
+
+			if ok, err := d.verify(key); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("digest %v: %w", id, err)
+				}
+				continue
+			} else if !ok {
+				continue
+			}
+
+			for _, segID := range d.Segments {
+				if seg, ok := md.Segments[segID]; ok && seg.Type == luksSegmentType {
+					return key, seg, nil
+				}
+			}
+
+			return nil, luksSegment{}, errNoSegment
+		}
+	}
+
+	if firstErr != nil {
+		return nil, luksSegment{}, firstErr
+	}
+	return nil, luksSegment{}, errIncorrectPassphrase
+}
+
+// newLUKSReader returns a reader for the decrypted contents of the LUKS2 device of the specified
+// size read from r, using passphrase.
+func newLUKSReader(r io.ReaderAt, size int64, passphrase []byte) (*io.SectionReader, error) {
+	md, err := readLUKSHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	key, seg, err := md.unlock(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	switch seg.SectorSize {
+	case 512, 1024, 2048, 4096:
+	default:
+		return nil, fmt.Errorf("%w: %v", errInvalidSectorSize, seg.SectorSize)
+	}
+
+	if seg.Offset < 0 || seg.Offset > size {
+		return nil, errSegmentOutOfBounds
+	}
+
+	n := (size - seg.Offset) / seg.SectorSize * seg.SectorSize
+	if seg.Size != luksDynamicSize {
+		if n, err = strconv.ParseInt(seg.Size, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: size %q", errUnsupportedSegment, seg.Size)
+		}
+	}
+
+	if n < 0 || seg.Offset+n > size {
+		return nil, errSegmentOutOfBounds
+	}
+
+	c, err := newSectorCipher(seg.Encryption, key, seg.SectorSize, seg.IVTweak)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NewSectionReader(&decryptReader{r: r, off: seg.Offset, c: c}, 0, n), nil
+}
+
+// luksWriter writes LUKS2 devices.
+type luksWriter struct {
+	rand       io.Reader
+	passphrase []byte
+}
+
+// keyslotsSize returns the size of the keyslot area.
+func (w *luksWriter) keyslotsSize() int64 {
+	n := int64(luksKeySize * luksStripes)
+	return (n + luksKeyslotAlignment - 1) / luksKeyslotAlignment * luksKeyslotAlignment
+}
+
+// dataOffset returns the offset of the encrypted data segment.
+func (w *luksWriter) dataOffset() int64 {
+	return 2*luksWriteHeaderSize + w.keyslotsSize()
+}
+
+// readRandom returns n bytes read from w.rand.
+func (w *luksWriter) readRandom(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(w.rand, b); err != nil {
+		return nil, fmt.Errorf("failed to read random data: %w", err)
+	}
+	return b, nil
+}
+
+// metadata returns the LUKS2 metadata and the encrypted key material for a device containing a
+// single keyslot that protects volume key with w.passphrase.
+func (w *luksWriter) metadata(key []byte) (*luksMetadata, []byte, error) {
+	h, err := hashFunc(luksHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ks := luksKeyslot{
+		Type:    luksKeyslotType,
+		KeySize: luksKeySize,
+		AF:      luksAF{Type: "luks1", Stripes: luksStripes, Hash: luksHash},
+		Area: luksArea{
+			Type:       "raw",
+			Offset:     2 * luksWriteHeaderSize,
+			Size:       w.keyslotsSize(),
+			Encryption: cipherAESXTSPlain64,
+			KeySize:    luksKeySize,
+		},
+		// The passphrase is random, so an expensive KDF adds little.
+		KDF: luksKDF{Type: "pbkdf2", Hash: luksHash, Iterations: luksIterations},
+	}
+
+	if ks.KDF.Salt, err = w.readRandom(luksSaltSize); err != nil {
+		return nil, nil, err
+	}
+
+	d := luksDigest{
+		Type:       "pbkdf2",
+		Keyslots:   []string{"0"},
+		Segments:   []string{"0"},
+		Hash:       luksHash,
+		Iterations: luksIterations,
+	}
+
+	if d.Salt, err = w.readRandom(luksSaltSize); err != nil {
+		return nil, nil, err
+	}
+
+	if d.Digest, err = pbkdf2.Key(h, string(key), d.Salt, d.Iterations, h().Size()); err != nil {
+		return nil, nil, err
+	}
+
+	// Split the volume key, and encrypt it using a key derived from the passphrase.
+	af, err := afSplit(w.rand, key, ks.AF.Stripes, h)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := make([]byte, ks.afSize())
+	copy(b, af)
+
+	kek, err := ks.KDF.deriveKey(w.passphrase, ks.Area.KeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := newSectorCipher(ks.Area.Encryption, kek, luksSectorSize, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.encrypt(b, 0)
+
+	md := &luksMetadata{
+		Keyslots: map[string]luksKeyslot{"0": ks},
+		Tokens:   map[string]json.RawMessage{},
+		Segments: map[string]luksSegment{"0": {
+			Type:       luksSegmentType,
+			Offset:     w.dataOffset(),
+			Size:       luksDynamicSize,
+			Encryption: cipherAESXTSPlain64,
+			SectorSize: luksSectorSize,
+		}},
+		Digests: map[string]luksDigest{"0": d},
+		Config: luksConfig{
+			JSONSize:     luksWriteHeaderSize - luksBinaryHeaderSize,
+			KeyslotsSize: w.keyslotsSize(),
+		},
+	}
+
+	return md, b, nil
+}
+
+// headerCopy returns a copy of the LUKS2 header, including binary header and JSON area.
+func (w *luksWriter) headerCopy(md []byte, magic [6]byte, offset int64, id uuid.UUID) ([]byte, error) {
+	bh := luksBinaryHeader{
+		Magic:     magic,
+		Version:   luksVersion,
+		HdrSize:   uint64(luksWriteHeaderSize), //nolint:gosec // Constant.
+		SeqID:     1,
+		HdrOffset: uint64(offset), //nolint:gosec // Constant.
+	}
+	copy(bh.ChecksumAlg[:], luksChecksumAlg)
+	copy(bh.UUID[:], id.String())
+
+	if _, err := io.ReadFull(w.rand, bh.Salt[:]); err != nil {
+		return nil, fmt.Errorf("failed to read random data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.BigEndian, bh); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, luksWriteHeaderSize)
+	copy(b, buf.Bytes())
+	copy(b[luksBinaryHeaderSize:], md)
+
+	h, err := hashFunc(luksChecksumAlg)
+	if err != nil {
+		return nil, err
+	}
+
+	hh := h()
+	hh.Write(b)
+	copy(b[luksBinaryHeaderSize-7*512-len(bh.Csum):], hh.Sum(nil))
+
+	return b, nil
+}
+
+// header returns the LUKS2 header, keyslot area, and any padding that precedes the data segment,
+// for a device with the specified volume key.
+func (w *luksWriter) header(key []byte) ([]byte, error) {
+	md, material, err := w.metadata(key)
+	if err != nil {
+		return nil, err
+	}
+
+	mdb, err := json.Marshal(md)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(mdb)) >= md.Config.JSONSize {
+		return nil, fmt.Errorf("%w: metadata exceeds JSON area", errInvalidHeaderSize)
+	}
+
+	id, err := uuid.NewRandomFromReader(w.rand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate UUID: %w", err)
+	}
+
+	b := make([]byte, w.dataOffset())
+
+	for i, magic := range [][6]byte{luksMagicPrimary, luksMagicSecondary} {
+		off := int64(i) * luksWriteHeaderSize
+
+		hdr, err := w.headerCopy(mdb, magic, off, id)
+		if err != nil {
+			return nil, err
+		}
+
+		copy(b[off:], hdr)
+	}
+
+	copy(b[2*luksWriteHeaderSize:], material)
+
+	return b, nil
+}
+
+// newReader returns a reader that produces a LUKS2 device containing the data read from r,
+// encrypted with a random volume key that is protected by w.passphrase.
+func (w *luksWriter) newReader(r io.Reader) (io.Reader, error) {
+	key, err := w.readRandom(luksKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	hdr, err := w.header(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newSectorCipher(cipherAESXTSPlain64, key, luksSectorSize, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.MultiReader(bytes.NewReader(hdr), &encryptReader{r: r, c: c}), nil
+}
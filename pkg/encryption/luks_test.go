@@ -0,0 +1,237 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+// writeTestLUKS returns a LUKS2 device containing data, protected by passphrase.
+func writeTestLUKS(t *testing.T, data, passphrase []byte) []byte {
+	t.Helper()
+
+	w := luksWriter{rand: newTestRandom(), passphrase: passphrase}
+
+	r, err := w.newReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func Test_luksWriter(t *testing.T) {
+	b := writeTestLUKS(t, []byte("data"), []byte("passphrase"))
+
+	if got, want := int64(len(b)), (&luksWriter{}).dataOffset()+luksSectorSize; got != want {
+		t.Errorf("got %v bytes, want %v", got, want)
+	}
+
+	for _, magic := range [][6]byte{luksMagicPrimary, luksMagicSecondary} {
+		if _, err := readLUKSHeaderAt(bytes.NewReader(b), int64(len(b)), magic); err == nil {
+			t.Errorf("unexpected header at end of device")
+		}
+	}
+
+	md, err := readLUKSHeaderAt(bytes.NewReader(b), luksWriteHeaderSize, luksMagicSecondary)
+	if err != nil {
+		t.Fatalf("failed to read secondary header: %v", err)
+	}
+
+	j, err := json.MarshalIndent(md, "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := goldie.New(t, goldie.WithTestNameForDir(true))
+	g.Assert(t, "metadata", j)
+}
+
+func Test_newLUKSReader(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	passphrase := []byte("passphrase")
+
+	tests := []struct {
+		name       string
+		corrupt    []int64 // Offsets of bytes to corrupt.
+		passphrase []byte
+		wantErr    error
+	}{
+		{
+			name:       "OK",
+			passphrase: passphrase,
+		},
+		{
+			name:       "CorruptPrimary",
+			corrupt:    []int64{luksBinaryHeaderSize},
+			passphrase: passphrase,
+		},
+		{
+			name:       "CorruptBoth",
+			corrupt:    []int64{luksBinaryHeaderSize, luksWriteHeaderSize + luksBinaryHeaderSize},
+			passphrase: passphrase,
+			wantErr:    errInvalidChecksum,
+		},
+		{
+			name:       "CorruptMagic",
+			corrupt:    []int64{0, luksWriteHeaderSize},
+			passphrase: passphrase,
+			wantErr:    errInvalidMagic,
+		},
+		{
+			name:       "IncorrectPassphrase",
+			passphrase: []byte("incorrect"),
+			wantErr:    errIncorrectPassphrase,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := writeTestLUKS(t, data, passphrase)
+
+			for _, off := range tt.corrupt {
+				b[off] ^= 0xff
+			}
+
+			r, err := newLUKSReader(bytes.NewReader(b), int64(len(b)), tt.passphrase)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				got, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got, want := int64(len(got)), (int64(len(data))+511)/512*512; got != want {
+					t.Fatalf("got %v bytes, want %v", got, want)
+				}
+
+				if !bytes.Equal(got[:len(data)], data) {
+					t.Errorf("decrypted data does not match")
+				}
+			}
+		})
+	}
+}
+
+func Test_luksKDF_deriveKey(t *testing.T) {
+	salt := bytes.Repeat([]byte{0xfa}, luksSaltSize)
+
+	tests := []struct {
+		name    string
+		kdf     luksKDF
+		wantErr error
+	}{
+		{
+			name: "PBKDF2",
+			kdf:  luksKDF{Type: "pbkdf2", Hash: "sha256", Iterations: 1000, Salt: salt},
+		},
+		{
+			name: "Argon2i",
+			kdf:  luksKDF{Type: "argon2i", Time: 1, Memory: 32, CPUs: 1, Salt: salt},
+		},
+		{
+			name: "Argon2id",
+			kdf:  luksKDF{Type: "argon2id", Time: 1, Memory: 32, CPUs: 1, Salt: salt},
+		},
+		{
+			name:    "Argon2TimeZero",
+			kdf:     luksKDF{Type: "argon2id", Time: 0, Memory: 32, CPUs: 1, Salt: salt},
+			wantErr: errInvalidKDFParams,
+		},
+		{
+			name:    "Argon2TimeTooLarge",
+			kdf:     luksKDF{Type: "argon2id", Time: luksMaxArgon2Time + 1, Memory: 32, CPUs: 1, Salt: salt},
+			wantErr: errInvalidKDFParams,
+		},
+		{
+			name:    "Argon2MemoryTooLarge",
+			kdf:     luksKDF{Type: "argon2i", Time: 1, Memory: luksMaxArgon2Memory + 1, CPUs: 1, Salt: salt},
+			wantErr: errInvalidKDFParams,
+		},
+		{
+			name:    "Argon2CPUsZero",
+			kdf:     luksKDF{Type: "argon2id", Time: 1, Memory: 32, CPUs: 0, Salt: salt},
+			wantErr: errInvalidKDFParams,
+		},
+		{
+			name:    "Argon2CPUsTooLarge",
+			kdf:     luksKDF{Type: "argon2id", Time: 1, Memory: 32, CPUs: luksMaxArgon2CPUs + 1, Salt: salt},
+			wantErr: errInvalidKDFParams,
+		},
+		{
+			name:    "UnsupportedKDF",
+			kdf:     luksKDF{Type: "scrypt", Salt: salt},
+			wantErr: errUnsupportedKDF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := tt.kdf.deriveKey([]byte("passphrase"), luksKeySize)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil && len(key) != luksKeySize {
+				t.Errorf("got %v byte key, want %v", len(key), luksKeySize)
+			}
+		})
+	}
+}
+
+func Test_decryptReader(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1024)
+
+	for _, sectorSize := range []int64{512, 4096} {
+		c, err := newSectorCipher(cipherAESXTSPlain64, bytes.Repeat([]byte{1}, luksKeySize), sectorSize, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := io.ReadAll(&encryptReader{r: bytes.NewReader(data), c: c})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := &decryptReader{r: bytes.NewReader(append(make([]byte, 100), b...)), off: 100, c: c}
+
+		for _, tt := range []struct{ off, n int64 }{
+			{0, 16},
+			{1, 511},
+			{510, 1024},
+			{4095, 4098},
+			{int64(len(data)) - 10, 10},
+		} {
+			p := make([]byte, tt.n)
+
+			if _, err := r.ReadAt(p, tt.off); err != nil {
+				t.Fatalf("sector size %v: read %v bytes at %v: %v", sectorSize, tt.n, tt.off, err)
+			}
+
+			if want := data[tt.off : tt.off+tt.n]; !bytes.Equal(p, want) {
+				t.Errorf("sector size %v: read %v bytes at %v: got %q, want %q", sectorSize, tt.n, tt.off, p, want)
+			}
+		}
+
+		if n, err := r.ReadAt(make([]byte, 20), int64(len(data))-10); n != 10 || !errors.Is(err, io.EOF) {
+			t.Errorf("sector size %v: got %v, %v, want 10, EOF", sectorSize, n, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"crypto/rsa"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// readTestKey returns the contents of the PEM file with the specified name.
+func readTestKey(t *testing.T, name string) []byte {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("..", "..", "test", "keys", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// getTestPrivateKey returns the RSA private key read from the PEM file with the specified name.
+func getTestPrivateKey(t *testing.T, name string) *rsa.PrivateKey {
+	t.Helper()
+
+	pk, err := cryptoutils.UnmarshalPEMToPrivateKey(readTestKey(t, name), cryptoutils.SkipPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, ok := pk.(*rsa.PrivateKey)
+	if !ok {
+		t.Fatalf("unexpected key type %T", pk)
+	}
+
+	return key
+}
+
+// getTestPublicKey returns the RSA public key read from the PEM file with the specified name.
+func getTestPublicKey(t *testing.T, name string) *rsa.PublicKey {
+	t.Helper()
+
+	pk, err := cryptoutils.UnmarshalPEMToPublicKey(readTestKey(t, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, ok := pk.(*rsa.PublicKey)
+	if !ok {
+		t.Fatalf("unexpected key type %T", pk)
+	}
+
+	return pub
+}
+
+// newTestRandom returns a deterministic source of random data.
+func newTestRandom() io.Reader {
+	return rand.NewChaCha8([32]byte{})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// messagePEMType is the PEM block type of a crypto message.
+const messagePEMType = "MESSAGE"
+
+var (
+	errInvalidMessage    = errors.New("invalid crypto message")
+	errTrailingMessage   = errors.New("unexpected data following crypto message")
+	errUnexpectedPEMType = errors.New("unexpected PEM block type")
+)
+
+// rsaOAEPMessage is the ASN.1 structure contained in a crypto message.
+type rsaOAEPMessage struct {
+	Ciphertext []byte
+}
+
+// wrapPassphrase encrypts passphrase with pub using RSA-OAEP with SHA-256, and returns the
+// resulting PEM-encoded crypto message.
+func wrapPassphrase(rand io.Reader, pub *rsa.PublicKey, passphrase []byte) ([]byte, error) {
+	ct, err := rsa.EncryptOAEP(sha256.New(), rand, pub, passphrase, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt passphrase: %w", err)
+	}
+
+	b, err := asn1.Marshal(rsaOAEPMessage{Ciphertext: ct})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: messagePEMType, Bytes: b}), nil
+}
+
+// unwrapPassphrase decrypts the PEM-encoded crypto message b with key, and returns the resulting
+// passphrase.
+func unwrapPassphrase(key *rsa.PrivateKey, b []byte) ([]byte, error) {
+	p, _ := pem.Decode(b)
+	if p == nil {
+		return nil, fmt.Errorf("%w: no PEM data found", errInvalidMessage)
+	}
+
+	if p.Type != messagePEMType {
+		return nil, fmt.Errorf("%w: %v", errUnexpectedPEMType, p.Type)
+	}
+
+	var m rsaOAEPMessage
+
+	rest, err := asn1.Unmarshal(p.Bytes, &m)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidMessage, err)
+	}
+
+	if len(rest) > 0 {
+		return nil, errTrailingMessage
+	}
+
+	passphrase, err := rsa.DecryptOAEP(sha256.New(), nil, key, m.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt passphrase: %w", err)
+	}
+
+	return passphrase, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+func Test_unwrapPassphrase(t *testing.T) {
+	pub := getTestPublicKey(t, "rsa-public.pem")
+	key := getTestPrivateKey(t, "rsa-private.pem")
+	passphrase := []byte("passphrase")
+
+	msg, err := wrapPassphrase(newTestRandom(), pub, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := rsa.GenerateKey(newTestRandom(), 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		key     *rsa.PrivateKey
+		msg     []byte
+		wantErr error
+	}{
+		{
+			name: "OK",
+			key:  key,
+			msg:  msg,
+		},
+		{
+			name:    "WrongKey",
+			key:     otherKey,
+			msg:     msg,
+			wantErr: rsa.ErrDecryption,
+		},
+		{
+			name:    "NotPEM",
+			key:     key,
+			msg:     []byte("message"),
+			wantErr: errInvalidMessage,
+		},
+		{
+			name:    "UnexpectedType",
+			key:     key,
+			msg:     pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY"}),
+			wantErr: errUnexpectedPEMType,
+		},
+		{
+			name:    "NotASN1",
+			key:     key,
+			msg:     pem.EncodeToMemory(&pem.Block{Type: messagePEMType, Bytes: []byte("message")}),
+			wantErr: errInvalidMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unwrapPassphrase(tt.key, tt.msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && !bytes.Equal(got, passphrase) {
+				t.Errorf("got passphrase %q, want %q", got, passphrase)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package encryption
+
+import (
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/xts"
+)
+
+// cipherAESXTSPlain64 is the name of the only cipher specification supported, which is the
+// default used by cryptsetup.
+const cipherAESXTSPlain64 = "aes-xts-plain64"
+
+var errUnsupportedCipher = errors.New("unsupported cipher")
+
+// sectorCipher encrypts and decrypts data in fixed-size sectors using AES-XTS, with the plain64
+// IV scheme.
+type sectorCipher struct {
+	c          *xts.Cipher
+	sectorSize int64
+	ivTweak    uint64
+}
+
+// newSectorCipher returns a sectorCipher that implements the cipher specification spec using
+// key. Sectors are sectorSize bytes in length, and IVs are offset by ivTweak.
+func newSectorCipher(spec string, key []byte, sectorSize int64, ivTweak uint64) (*sectorCipher, error) {
+	if spec != cipherAESXTSPlain64 {
+		return nil, fmt.Errorf("%w: %v", errUnsupportedCipher, spec)
+	}
+
+	c, err := xts.NewCipher(aes.NewCipher, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sectorCipher{c: c, sectorSize: sectorSize, ivTweak: ivTweak}, nil
+}
+
+// iv returns the IV of sector i. As with dm-crypt, plain64 IVs are expressed in 512-byte units
+// regardless of sector size.
+func (c *sectorCipher) iv(i int64) uint64 {
+	return uint64(i*(c.sectorSize/512)) + c.ivTweak //nolint:gosec // Sector numbers are non-negative.
+}
+
+// encrypt encrypts b in place. The length of b must be a multiple of the sector size, and the
+// first sector of b is sector i.
+func (c *sectorCipher) encrypt(b []byte, i int64) {
+	for ; len(b) > 0; i++ {
+		c.c.Encrypt(b[:c.sectorSize], b[:c.sectorSize], c.iv(i))
+		b = b[c.sectorSize:]
+	}
+}
+
+// decrypt decrypts b in place. The length of b must be a multiple of the sector size, and the
+// first sector of b is sector i.
+func (c *sectorCipher) decrypt(b []byte, i int64) {
+	for ; len(b) > 0; i++ {
+		c.c.Decrypt(b[:c.sectorSize], b[:c.sectorSize], c.iv(i))
+		b = b[c.sectorSize:]
+	}
+}
+
+// decryptReader decrypts sectors read from an underlying io.ReaderAt.
+type decryptReader struct {
+	r   io.ReaderAt
+	off int64 // Offset of first sector in r.
+	c   *sectorCipher
+}
+
+// ReadAt reads len(p) decrypted bytes starting at offset off.
+func (r *decryptReader) ReadAt(p []byte, off int64) (int, error) {
+	ss := r.c.sectorSize
+
+	first := off / ss
+	last := (off + int64(len(p)) + ss - 1) / ss
+	start := off - first*ss
+
+	b := make([]byte, (last-first)*ss)
+
+	n, err := r.r.ReadAt(b, r.off+first*ss)
+	n -= n % int(ss)
+
+	if int64(n) <= start {
+		return 0, err
+	}
+
+	r.c.decrypt(b[:n], first)
+
+	if c := copy(p, b[start:n]); c < len(p) {
+		return c, err
+	}
+	return len(p), nil
+}
+
+// encryptReader encrypts data read from an underlying io.Reader. If the length of the data is
+// not a multiple of the sector size, the final sector is padded with zeroes.
+type encryptReader struct {
+	r      io.Reader
+	c      *sectorCipher
+	sector int64  // Index of the next sector to be encrypted.
+	buf    []byte // Encrypted data not yet returned.
+	err    error  // Error to return once buf is empty.
+}
+
+// encryptBufferSectors is the number of sectors encrypted at a time by encryptReader.
+const encryptBufferSectors = 64
+
+// fill reads and encrypts the next sectors from the underlying reader.
+func (r *encryptReader) fill() {
+	ss := r.c.sectorSize
+
+	b := make([]byte, encryptBufferSectors*ss)
+
+	n, err := io.ReadFull(r.r, b)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	r.err = err
+
+	b = b[:(int64(n)+ss-1)/ss*ss]
+
+	r.c.encrypt(b, r.sector)
+	r.sector += int64(len(b)) / ss
+	r.buf = b
+}
+
+// Read reads encrypted data into p.
+func (r *encryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.fill()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
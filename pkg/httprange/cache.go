@@ -0,0 +1,83 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the LICENSE.md file
+// distributed with the sources of this project regarding your rights to use or distribute this
+// software.
+
+package httprange
+
+import (
+	"container/list"
+	"sync"
+)
+
+// block is a cached block of data.
+type block struct {
+	index int64
+	data  []byte
+}
+
+// blockCache is a fixed capacity cache of blocks, with least recently used eviction.
+type blockCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List              // Blocks, in order of most recent use.
+	elems    map[int64]*list.Element // Elements of ll, indexed by block index.
+}
+
+// newBlockCache returns a cache that holds up to capacity blocks.
+func newBlockCache(capacity int) *blockCache {
+	return &blockCache{
+		capacity: capacity,
+		ll:       list.New(),
+		elems:    make(map[int64]*list.Element),
+	}
+}
+
+// get returns the data of the block with index i, if present.
+func (c *blockCache) get(i int64) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.elems[i]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(e)
+
+	return e.Value.(*block).data, true //nolint:forcetypeassert // Only blocks are stored.
+}
+
+// contains reports whether the block with index i is present, without updating its recency.
+func (c *blockCache) contains(i int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.elems[i]
+	return ok
+}
+
+// add adds data as the block with index i, evicting the least recently used block if the cache is
+// full.
+func (c *blockCache) add(i int64, data []byte) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.elems[i]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*block).data = data //nolint:forcetypeassert // Only blocks are stored.
+		return
+	}
+
+	c.elems[i] = c.ll.PushFront(&block{i, data})
+
+	if c.ll.Len() > c.capacity {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		delete(c.elems, e.Value.(*block).index) //nolint:forcetypeassert // Only blocks are stored.
+	}
+}
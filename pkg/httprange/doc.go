@@ -0,0 +1,29 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the LICENSE.md file
+// distributed with the sources of this project regarding your rights to use or distribute this
+// software.
+
+/*
+Package httprange implements an io.ReaderAt that reads a remote resource using HTTP Range
+requests, caching the blocks that are read.
+
+# Inspect
+
+To inspect a remote SIF image, create a ReaderAt, and load the image using the read-only
+sif.LoadContainerReader:
+
+	r, err := httprange.NewReaderAt("https://example.com/image.sif")
+
+	f, err := sif.LoadContainerReader(r, r.Size())
+
+Only the blocks of the image that are read are fetched. For example, listing the descriptors of an
+image requires only the header and descriptors to be fetched, regardless of the size of the data
+objects it contains. Likewise, verifying the integrity of an object group using the integrity
+package requires only the objects in that group to be fetched.
+
+By default, blocks are 64KiB in size, and up to 256 blocks are cached. To override this behavior,
+supply additional options. For example, to use 1MiB blocks:
+
+	r, err := httprange.NewReaderAt(url, httprange.OptReaderAtBlockSize(1<<20))
+*/
+package httprange
@@ -0,0 +1,298 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the LICENSE.md file
+// distributed with the sources of this project regarding your rights to use or distribute this
+// software.
+
+package httprange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrRangeNotSupported is the error returned when a server does not support range requests.
+var ErrRangeNotSupported = errors.New("server does not support range requests")
+
+var (
+	errInvalidBlockSize    = errors.New("block size must be greater than zero")
+	errInvalidCacheSize    = errors.New("cache size must not be negative")
+	errInvalidContentRange = errors.New("invalid Content-Range")
+	errNegativeOffset      = errors.New("negative offset")
+	errSizeChanged         = errors.New("resource size changed")
+	errUnexpectedStatus    = errors.New("unexpected status")
+)
+
+const (
+	defaultBlockSize = 64 << 10
+	defaultCacheSize = 256
+)
+
+// readerAtOpts accumulates ReaderAt options.
+type readerAtOpts struct {
+	ctx       context.Context //nolint:containedctx
+	client    *http.Client
+	header    http.Header
+	blockSize int64
+	cacheSize int
+}
+
+// ReaderAtOpt are used to specify ReaderAt options.
+type ReaderAtOpt func(*readerAtOpts) error
+
+// OptReaderAtWithContext specifies that the given context should be used for all requests.
+func OptReaderAtWithContext(ctx context.Context) ReaderAtOpt {
+	return func(ro *readerAtOpts) error {
+		ro.ctx = ctx
+		return nil
+	}
+}
+
+// OptReaderAtWithClient specifies that the given client should be used for all requests.
+func OptReaderAtWithClient(c *http.Client) ReaderAtOpt {
+	return func(ro *readerAtOpts) error {
+		ro.client = c
+		return nil
+	}
+}
+
+// OptReaderAtWithHeader adds the header key with the specified value to all requests. This may be
+// called multiple times to add more than one header, or more than one value for a header.
+func OptReaderAtWithHeader(key, value string) ReaderAtOpt {
+	return func(ro *readerAtOpts) error {
+		ro.header.Add(key, value)
+		return nil
+	}
+}
+
+// OptReaderAtBlockSize sets the size of the blocks that are fetched and cached to n bytes.
+func OptReaderAtBlockSize(n int64) ReaderAtOpt {
+	return func(ro *readerAtOpts) error {
+		if n <= 0 {
+			return errInvalidBlockSize
+		}
+		ro.blockSize = n
+		return nil
+	}
+}
+
+// OptReaderAtCacheSize sets the maximum number of blocks to cache to n. If n is zero, no blocks
+// are cached.
+func OptReaderAtCacheSize(n int) ReaderAtOpt {
+	return func(ro *readerAtOpts) error {
+		if n < 0 {
+			return errInvalidCacheSize
+		}
+		ro.cacheSize = n
+		return nil
+	}
+}
+
+// ReaderAt reads a remote resource using HTTP Range requests.
+type ReaderAt struct {
+	ctx       context.Context //nolint:containedctx
+	client    *http.Client
+	url       string
+	header    http.Header
+	blockSize int64
+	size      int64
+	cache     *blockCache
+}
+
+// NewReaderAt returns a ReaderAt that reads the resource at url.
+//
+// The first block of the resource is fetched in order to determine the size of the resource. If
+// the server does not support range requests, ErrRangeNotSupported is returned.
+//
+// By default, requests are made using http.DefaultClient. To use a different client, consider
+// using OptReaderAtWithClient. To add headers to each request, such as authorization, consider
+// using OptReaderAtWithHeader. To specify a context for requests, consider using
+// OptReaderAtWithContext.
+//
+// By default, the resource is fetched in blocks of 64KiB, and up to 256 blocks are cached. To
+// override this behavior, consider using OptReaderAtBlockSize and OptReaderAtCacheSize.
+func NewReaderAt(url string, opts ...ReaderAtOpt) (*ReaderAt, error) {
+	ro := readerAtOpts{
+		ctx:       context.Background(),
+		client:    http.DefaultClient,
+		header:    make(http.Header),
+		blockSize: defaultBlockSize,
+		cacheSize: defaultCacheSize,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&ro); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	r := &ReaderAt{
+		ctx:       ro.ctx,
+		client:    ro.client,
+		url:       url,
+		header:    ro.header,
+		blockSize: ro.blockSize,
+		cache:     newBlockCache(ro.cacheSize),
+	}
+
+	b, size, err := r.getRange(0, r.blockSize-1)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	r.size = size
+
+	if len(b) > 0 {
+		r.cache.add(0, b)
+	}
+
+	return r, nil
+}
+
+// Size returns the size of the resource, in bytes.
+func (r *ReaderAt) Size() int64 { return r.size }
+
+// getRange requests bytes start through end (inclusive) of the resource. On success, the data
+// returned by the server and the total size of the resource are returned. Fewer bytes than
+// requested are returned if end lies beyond the end of the resource.
+func (r *ReaderAt) getRange(start, end int64) ([]byte, int64, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header = r.header.Clone()
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// Servers may respond to range requests against an empty resource with the entire
+		// (empty) resource.
+		if res.ContentLength == 0 {
+			return nil, 0, nil
+		}
+		return nil, 0, ErrRangeNotSupported
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Range requests against an empty resource cannot be satisfied.
+		var size int64
+		if _, err := fmt.Sscanf(res.Header.Get("Content-Range"), "bytes */%d", &size); err == nil && size == 0 {
+			return nil, 0, nil
+		}
+		fallthrough
+	default:
+		return nil, 0, fmt.Errorf("%w: %v", errUnexpectedStatus, res.Status)
+	}
+
+	var first, last, size int64
+
+	cr := res.Header.Get("Content-Range")
+	if _, err := fmt.Sscanf(cr, "bytes %d-%d/%d", &first, &last, &size); err != nil {
+		return nil, 0, fmt.Errorf("%w: %q", errInvalidContentRange, cr)
+	}
+
+	if first != start || last < first || last > end || last >= size {
+		return nil, 0, fmt.Errorf("%w: %q", errInvalidContentRange, cr)
+	}
+
+	b := make([]byte, last-first+1)
+	if _, err := io.ReadFull(res.Body, b); err != nil {
+		return nil, 0, err
+	}
+
+	return b, size, nil
+}
+
+// getBlocks fetches blocks first through last (inclusive) using a single request, and adds them to
+// the cache.
+func (r *ReaderAt) getBlocks(first, last int64) ([][]byte, error) {
+	start := first * r.blockSize
+	end := min((last+1)*r.blockSize, r.size) - 1
+
+	b, size, err := r.getRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	if size != r.size {
+		return nil, fmt.Errorf("%w: got %v bytes, want %v", errSizeChanged, size, r.size)
+	}
+
+	if got, want := int64(len(b)), end-start+1; got != want {
+		return nil, fmt.Errorf("%w: got %v bytes, want %v", errInvalidContentRange, got, want)
+	}
+
+	blocks := make([][]byte, 0, last-first+1)
+
+	for i := first; i <= last; i++ {
+		n := min(int64(len(b)), r.blockSize)
+		blocks = append(blocks, b[:n:n])
+		r.cache.add(i, b[:n:n])
+		b = b[n:]
+	}
+
+	return blocks, nil
+}
+
+// ReadAt reads len(p) bytes from the resource starting at byte offset off. Blocks not present in
+// the cache are fetched, with each run of contiguous missing blocks fetched using a single
+// request.
+//
+// ReadAt is safe for concurrent use.
+func (r *ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errNegativeOffset
+	}
+
+	if off >= r.size {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+
+	end := min(off+int64(len(p)), r.size)
+	first := off / r.blockSize
+	last := (end - 1) / r.blockSize
+
+	var n int
+
+	for i := first; i <= last; {
+		blocks := make([][]byte, 0, 1)
+
+		if b, ok := r.cache.get(i); ok {
+			blocks = append(blocks, b)
+		} else {
+			// Fetch the run of missing blocks starting at i.
+			j := i
+			for j < last && !r.cache.contains(j+1) {
+				j++
+			}
+
+			b, err := r.getBlocks(i, j)
+			if err != nil {
+				return n, fmt.Errorf("%w", err)
+			}
+			blocks = b
+		}
+
+		for _, b := range blocks {
+			start := max(off-i*r.blockSize, 0)
+			n += copy(p[n:], b[start:])
+			i++
+		}
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
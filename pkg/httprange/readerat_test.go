@@ -0,0 +1,349 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the LICENSE.md file
+// distributed with the sources of this project regarding your rights to use or distribute this
+// software.
+
+package httprange
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/sylabs/sif/v2/pkg/integrity"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+var corpus = filepath.Join("..", "..", "test", "images")
+
+// testServer is a server that serves a fixed resource, and counts the requests made and bytes
+// served.
+type testServer struct {
+	*httptest.Server
+	requests atomic.Int64
+	bytes    atomic.Int64
+}
+
+// countingWriter counts the bytes written to the wrapped http.ResponseWriter.
+type countingWriter struct {
+	http.ResponseWriter
+	n *atomic.Int64
+}
+
+func (w countingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.n.Add(int64(n))
+	return n, err
+}
+
+// newTestServer returns a server that serves b using handler h. If h is nil, b is served using
+// http.ServeContent.
+func newTestServer(t *testing.T, b []byte, h http.HandlerFunc) *testServer {
+	t.Helper()
+
+	if h == nil {
+		h = func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(b))
+		}
+	}
+
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		h(countingWriter{w, &s.bytes}, r)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// getTestImage returns the contents of the named image from the test corpus.
+func getTestImage(t *testing.T, name string) []byte {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join(corpus, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestNewReaderAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        []byte
+		h        http.HandlerFunc
+		opts     []ReaderAtOpt
+		wantErr  error
+		wantSize int64
+	}{
+		{
+			name:    "InvalidBlockSize",
+			opts:    []ReaderAtOpt{OptReaderAtBlockSize(0)},
+			wantErr: errInvalidBlockSize,
+		},
+		{
+			name:    "InvalidCacheSize",
+			opts:    []ReaderAtOpt{OptReaderAtCacheSize(-1)},
+			wantErr: errInvalidCacheSize,
+		},
+		{
+			name: "RangeNotSupported",
+			h: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte{0xfa, 0xce})
+			},
+			wantErr: ErrRangeNotSupported,
+		},
+		{
+			name:    "NotFound",
+			h:       http.NotFound,
+			wantErr: errUnexpectedStatus,
+		},
+		{
+			name: "InvalidContentRange",
+			h: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Range", "bytes 1-1/2")
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write([]byte{0xce})
+			},
+			wantErr: errInvalidContentRange,
+		},
+		{
+			name: "Header",
+			h: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader([]byte{0xfa, 0xce}))
+			},
+			opts:     []ReaderAtOpt{OptReaderAtWithHeader("Authorization", "Bearer token")},
+			wantSize: 2,
+		},
+		{
+			name:     "Empty",
+			b:        []byte{},
+			wantSize: 0,
+		},
+		{
+			name:     "Image",
+			b:        getTestImage(t, "one-group-signed-pgp.sif"),
+			wantSize: 42008,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, tt.b, tt.h)
+
+			r, err := NewReaderAt(s.URL, append(tt.opts, OptReaderAtWithClient(s.Client()))...)
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				if got, want := r.Size(), tt.wantSize; got != want {
+					t.Errorf("got size %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReaderAt_ReadAt(t *testing.T) {
+	b := make([]byte, 1000)
+	for i := range b {
+		b[i] = byte(i)
+	}
+
+	tests := []struct {
+		name         string
+		off          int64
+		n            int
+		wantN        int
+		wantErr      error
+		wantRequests int64
+	}{
+		{name: "NegativeOffset", off: -1, n: 1, wantErr: errNegativeOffset},
+		{name: "Zero", off: 0, n: 0},
+		{name: "FirstBlock", off: 10, n: 50, wantN: 50},
+		{name: "SecondBlock", off: 100, n: 100, wantN: 100, wantRequests: 1},
+		{name: "MultipleBlocks", off: 50, n: 500, wantN: 500, wantRequests: 1},
+		{name: "End", off: 900, n: 100, wantN: 100, wantRequests: 1},
+		{name: "PastEnd", off: 950, n: 100, wantN: 50, wantErr: io.EOF, wantRequests: 1},
+		{name: "EOF", off: 1000, n: 1, wantErr: io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, b, nil)
+
+			r, err := NewReaderAt(s.URL,
+				OptReaderAtWithClient(s.Client()),
+				OptReaderAtBlockSize(100),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Discount the initial request made to determine size.
+			s.requests.Store(0)
+
+			p := make([]byte, tt.n)
+
+			n, err := r.ReadAt(p, tt.off)
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := n, tt.wantN; got != want {
+				t.Fatalf("got %v bytes, want %v", got, want)
+			}
+
+			if got, want := p[:n], b[max(tt.off, 0):max(tt.off, 0)+int64(n)]; !bytes.Equal(got, want) {
+				t.Errorf("got data %v, want %v", got, want)
+			}
+
+			if got, want := s.requests.Load(), tt.wantRequests; got != want {
+				t.Errorf("got %v requests, want %v", got, want)
+			}
+
+			// Subsequent reads of the same range should be served from the cache.
+			if _, err := r.ReadAt(p, tt.off); !errors.Is(err, tt.wantErr) {
+				t.Fatal(err)
+			}
+
+			if got, want := s.requests.Load(), tt.wantRequests; got != want {
+				t.Errorf("got %v requests after cached read, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestReaderAt_CacheSize(t *testing.T) {
+	b := make([]byte, 1000)
+
+	s := newTestServer(t, b, nil)
+
+	r, err := NewReaderAt(s.URL,
+		OptReaderAtWithClient(s.Client()),
+		OptReaderAtBlockSize(100),
+		OptReaderAtCacheSize(2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Block 0 is cached by NewReaderAt. Read block 1, then block 2, evicting block 0.
+	for _, off := range []int64{100, 200} {
+		if _, err := r.ReadAt(make([]byte, 100), off); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Block 1 is cached, but block 0 must be fetched again.
+	for _, off := range []int64{100, 0} {
+		if _, err := r.ReadAt(make([]byte, 100), off); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, want := s.requests.Load(), int64(4); got != want {
+		t.Errorf("got %v requests, want %v", got, want)
+	}
+}
+
+func TestReaderAt_SizeChanged(t *testing.T) {
+	b := make([]byte, 1000)
+
+	s := newTestServer(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(b))
+	})
+
+	r, err := NewReaderAt(s.URL,
+		OptReaderAtWithClient(s.Client()),
+		OptReaderAtBlockSize(100),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b = b[:500]
+
+	if _, err := r.ReadAt(make([]byte, 100), 100); !errors.Is(err, errSizeChanged) {
+		t.Errorf("got error %v, want %v", err, errSizeChanged)
+	}
+}
+
+// getTestKeyRing returns a keyring containing a fixed test PGP entity.
+func getTestKeyRing(t *testing.T) openpgp.KeyRing { //nolint:ireturn
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("..", "..", "test", "keys", "private.asc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	el, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return el
+}
+
+func TestReaderAt_Image(t *testing.T) {
+	b := getTestImage(t, "two-groups-signed-pgp.sif")
+
+	s := newTestServer(t, b, nil)
+
+	r, err := NewReaderAt(s.URL,
+		OptReaderAtWithClient(s.Client()),
+		OptReaderAtBlockSize(4096),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := sif.LoadContainerReader(r, r.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.UnloadContainer()
+
+	if got, want := f.DescriptorsTotal(), int64(48); got != want {
+		t.Errorf("got %v descriptors, want %v", got, want)
+	}
+
+	// Listing descriptors should only require the header and descriptors to be fetched.
+	if got, limit := s.bytes.Load(), f.DataOffset()+4096; got > limit {
+		t.Errorf("fetched %v bytes to list descriptors, want at most %v", got, limit)
+	}
+
+	v, err := integrity.NewVerifier(f,
+		integrity.OptVerifyGroup(1),
+		integrity.OptVerifyWithKeyRing(getTestKeyRing(t)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Verifying the first group should not require the second group to be fetched.
+	if got, limit := s.bytes.Load(), int64(len(b)); got >= limit {
+		t.Errorf("fetched %v bytes to verify group, want less than %v", got, limit)
+	}
+}
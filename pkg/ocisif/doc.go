@@ -0,0 +1,43 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+/*
+Package ocisif implements functions to convert between OCI images stored in a SIF image, and other
+OCI image representations.
+
+# Export
+
+A SIF image may contain an OCI image layout, consisting of an OCI root index object, and one or
+more OCI blob objects. To export the image layout to a directory:
+
+	err := ocisif.ExportLayout(f, dir)
+
+Alternatively, to write the image layout as a tar archive:
+
+	err := ocisif.WriteLayoutTar(f, w)
+
+# Import
+
+To write a new SIF image containing an OCI image index, such as one obtained using the
+go-containerregistry layout or remote packages:
+
+	err := ocisif.WriteImageIndex(w, ii)
+
+Alternatively, to write a new SIF image containing a single OCI image:
+
+	err := ocisif.WriteImage(w, img)
+
+# Read
+
+To use an OCI image index stored in a SIF image anywhere a go-containerregistry v1.ImageIndex is
+accepted:
+
+	ii, err := ocisif.ImageIndexFromFileImage(f)
+
+Likewise, to obtain the v1.Image with manifest digest h:
+
+	img, err := ocisif.ImageFromFileImage(f, h)
+*/
+package ocisif
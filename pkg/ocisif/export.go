@@ -0,0 +1,274 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package ocisif
+
+import (
+	"archive/tar"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+var errDigestMismatch = errors.New("digest mismatch")
+
+const (
+	layoutFile    = "oci-layout"
+	indexFile     = "index.json"
+	blobsDir      = "blobs"
+	layoutVersion = `{"imageLayoutVersion":"1.0.0"}`
+)
+
+// layoutWriter writes the contents of an OCI image layout.
+type layoutWriter interface {
+	// writeDir creates the directory with the specified name.
+	writeDir(name string, t time.Time) error
+
+	// writeFile creates the file with the specified name and size, containing the data read
+	// from r. If verify returns an error once the data has been read, the file must not be
+	// retained.
+	writeFile(name string, size int64, t time.Time, r io.Reader, verify func() error) error
+}
+
+// digestReader verifies the digest of the data read from the underlying reader.
+type digestReader struct {
+	io.Reader
+	h      v1.Hash
+	hasher hash.Hash
+}
+
+// newDigestReader returns a reader that reads from r, accumulating a hash of the data using the
+// algorithm of h.
+func newDigestReader(r io.Reader, h v1.Hash) (*digestReader, error) {
+	hasher, err := v1.Hasher(h.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &digestReader{
+		Reader: io.TeeReader(r, hasher),
+		h:      h,
+		hasher: hasher,
+	}, nil
+}
+
+// verify checks that the digest of the data read matches the expected value.
+func (dr *digestReader) verify() error {
+	if got, want := hex.EncodeToString(dr.hasher.Sum(nil)), dr.h.Hex; got != want {
+		return fmt.Errorf("%w: got %v:%v, want %v", errDigestMismatch, dr.h.Algorithm, got, dr.h)
+	}
+	return nil
+}
+
+// writeObject writes the data object described by d to lw using the specified name, verifying
+// the digest of the object as it is written.
+func writeObject(lw layoutWriter, name string, d sif.Descriptor) error {
+	h, err := d.OCIBlobDigest()
+	if err != nil {
+		return err
+	}
+
+	dr, err := newDigestReader(d.GetReader(), h)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = path.Join(blobsDir, h.Algorithm, h.Hex)
+	}
+
+	if err := lw.writeFile(name, d.Size(), d.ModifiedAt(), dr, dr.verify); err != nil {
+		return fmt.Errorf("object %v: %w", d.ID(), err)
+	}
+
+	return nil
+}
+
+// exportLayout writes the OCI image layout contained in f to lw.
+func exportLayout(f *sif.FileImage, lw layoutWriter) error {
+	index, err := f.GetDescriptor(sif.WithDataType(sif.DataOCIRootIndex))
+	if err != nil {
+		return err
+	}
+
+	blobs, err := f.GetDescriptors(sif.WithDataType(sif.DataOCIBlob))
+	if err != nil {
+		return err
+	}
+
+	t := f.ModifiedAt()
+
+	if err := lw.writeFile(layoutFile, int64(len(layoutVersion)), t, strings.NewReader(layoutVersion), nil); err != nil {
+		return err
+	}
+
+	written := make(map[v1.Hash]bool)
+
+	for _, name := range []string{blobsDir, path.Join(blobsDir, "sha256")} {
+		if err := lw.writeDir(name, t); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range blobs {
+		h, err := d.OCIBlobDigest()
+		if err != nil {
+			return err
+		}
+
+		// Blobs are content addressable, so there is no need to write duplicates.
+		if written[h] {
+			continue
+		}
+
+		if err := writeObject(lw, "", d); err != nil {
+			return err
+		}
+
+		written[h] = true
+	}
+
+	// Write index.json last, so that it is not present in a layout that is only partially
+	// written.
+	return writeObject(lw, indexFile, index)
+}
+
+// dirWriter writes an OCI image layout to a directory.
+type dirWriter struct {
+	dir string
+}
+
+// writeDir creates the directory with the specified name.
+func (w dirWriter) writeDir(name string, _ time.Time) error {
+	return os.MkdirAll(filepath.Join(w.dir, filepath.FromSlash(name)), 0o755)
+}
+
+// writeFile creates the file with the specified name, containing the data read from r. The data
+// is written to a temporary file, which is renamed once verify succeeds.
+func (w dirWriter) writeFile(name string, _ int64, _ time.Time, r io.Reader, verify func() error) error {
+	dst := filepath.Join(w.dir, filepath.FromSlash(name))
+
+	tf, err := os.CreateTemp(filepath.Dir(dst), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tf.Name())
+	defer tf.Close()
+
+	if _, err := io.Copy(tf, r); err != nil {
+		return err
+	}
+
+	if verify != nil {
+		if err := verify(); err != nil {
+			return err
+		}
+	}
+
+	if err := tf.Chmod(0o644); err != nil {
+		return err
+	}
+
+	if err := tf.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tf.Name(), dst)
+}
+
+// ExportLayout writes the OCI image layout contained in f to the directory dir, creating it if
+// necessary. The image layout is read from the OCI root index object in f, and the OCI blob
+// objects in f.
+//
+// The oci-layout file is written, followed by one file in the blobs directory per unique blob.
+// Finally, index.json is written using the contents of the OCI root index object. The digest of
+// each object is verified as it is written. If the digest of an object does not match, an error
+// is returned, and the corresponding file is not retained.
+//
+// If f does not contain an OCI root index object, an error wrapping sif.ErrNoObjects or
+// sif.ErrObjectNotFound is returned. If f contains more than one OCI root index object, an error
+// wrapping sif.ErrMultipleObjectsFound is returned.
+func ExportLayout(f *sif.FileImage, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := exportLayout(f, dirWriter{dir}); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// tarWriter writes an OCI image layout to a tar archive.
+type tarWriter struct {
+	tw *tar.Writer
+}
+
+// writeDir adds a directory entry with the specified name.
+func (w tarWriter) writeDir(name string, t time.Time) error {
+	return w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     0o755,
+		ModTime:  t,
+		Format:   tar.FormatPAX,
+	})
+}
+
+// writeFile adds a file entry with the specified name and size, containing the data read from r.
+// Since the entry is written as it is read, an entry that fails verification is retained in the
+// archive, and the archive must be discarded.
+func (w tarWriter) writeFile(name string, size int64, t time.Time, r io.Reader, verify func() error) error {
+	if err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0o644,
+		ModTime:  t,
+		Format:   tar.FormatPAX,
+	}); err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w.tw, r); err != nil {
+		return err
+	}
+
+	if verify != nil {
+		return verify()
+	}
+	return nil
+}
+
+// WriteLayoutTar writes the OCI image layout contained in f to w, as a tar archive. The contents
+// of the archive are as described for ExportLayout.
+//
+// The digest of each object is verified as it is written. Since w is written sequentially, if the
+// digest of an object does not match, an error is returned, and the data written to w should be
+// discarded.
+func WriteLayoutTar(f *sif.FileImage, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	if err := exportLayout(f, tarWriter{tw}); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
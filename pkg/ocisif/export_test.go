@@ -0,0 +1,272 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package ocisif
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sebdah/goldie/v2"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// testImage is the content of an OCI image used for testing.
+type testImage struct {
+	index    []byte
+	manifest []byte
+	config   []byte
+	layer    []byte
+}
+
+// getDescriptor returns an OCI descriptor of b, with media type mt.
+func getDescriptor(t *testing.T, b []byte, mt types.MediaType) v1.Descriptor {
+	t.Helper()
+
+	h, n, err := v1.SHA256(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v1.Descriptor{MediaType: mt, Size: n, Digest: h}
+}
+
+// getJSON returns the JSON encoding of v.
+func getJSON(t *testing.T, v any) []byte {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// getTestImage returns the content of a fixed OCI image.
+func getTestImage(t *testing.T) testImage {
+	t.Helper()
+
+	var ti testImage
+
+	ti.layer = []byte{0xfa, 0xce, 0xfe, 0xed}
+
+	ti.config = getJSON(t, v1.ConfigFile{
+		Architecture: "amd64",
+		OS:           "linux",
+		RootFS: v1.RootFS{
+			Type:    "layers",
+			DiffIDs: []v1.Hash{getDescriptor(t, ti.layer, "").Digest},
+		},
+	})
+
+	ti.manifest = getJSON(t, v1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		Config:        getDescriptor(t, ti.config, types.OCIConfigJSON),
+		Layers:        []v1.Descriptor{getDescriptor(t, ti.layer, types.OCIUncompressedLayer)},
+	})
+
+	ti.index = getJSON(t, v1.IndexManifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIImageIndex,
+		Manifests:     []v1.Descriptor{getDescriptor(t, ti.manifest, types.OCIManifestSchema1)},
+	})
+
+	return ti
+}
+
+// getTestSIF returns a SIF image containing the OCI image ti. If ti.index is nil, no OCI root
+// index object is added. The layer blob is added twice. If corrupt is true, the first copy of the
+// layer blob is modified after its digest is recorded.
+func getTestSIF(t *testing.T, ti testImage, corrupt bool) *sif.FileImage {
+	t.Helper()
+
+	var dis []sif.DescriptorInput
+
+	if ti.index != nil {
+		di, err := sif.NewDescriptorInput(sif.DataOCIRootIndex, bytes.NewReader(ti.index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dis = append(dis, di)
+	}
+
+	for _, b := range [][]byte{ti.manifest, ti.config, ti.layer, ti.layer} {
+		di, err := sif.NewDescriptorInput(sif.DataOCIBlob, bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		dis = append(dis, di)
+	}
+
+	var buf sif.Buffer
+
+	f, err := sif.CreateContainer(&buf,
+		sif.OptCreateDeterministic(),
+		sif.OptCreateWithDescriptors(dis...),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.UnloadContainer() })
+
+	if corrupt {
+		ds, err := f.GetDescriptors(sif.WithOCIBlobDigest(getDescriptor(t, ti.layer, "").Digest))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf.Bytes()[ds[0].Offset()] ^= 0xff
+	}
+
+	return f
+}
+
+func TestExportLayout(t *testing.T) {
+	ti := getTestImage(t)
+
+	tests := []struct {
+		name    string
+		f       *sif.FileImage
+		wantErr error
+	}{
+		{
+			name: "NoRootIndex",
+			f: getTestSIF(t, testImage{
+				manifest: ti.manifest,
+				config:   ti.config,
+				layer:    ti.layer,
+			}, false),
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name:    "DigestMismatch",
+			f:       getTestSIF(t, ti, true),
+			wantErr: errDigestMismatch,
+		},
+		{
+			name: "OK",
+			f:    getTestSIF(t, ti, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "layout")
+
+			err := ExportLayout(tt.f, dir)
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err != nil {
+				// A layout that fails verification must not contain the corrupt blob or index.
+				for _, name := range []string{
+					indexFile,
+					filepath.Join(blobsDir, "sha256", getDescriptor(t, ti.layer, "").Digest.Hex),
+				} {
+					if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+						t.Errorf("%v: got error %v, want not exist", name, err)
+					}
+				}
+				return
+			}
+
+			b, err := os.ReadFile(filepath.Join(dir, indexFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := b, ti.index; !bytes.Equal(got, want) {
+				t.Errorf("got index %s, want %s", got, want)
+			}
+
+			// The resulting layout must be readable, and contain the expected image.
+			p, err := layout.FromPath(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ii, err := p.ImageIndex()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			im, err := ii.Image(getDescriptor(t, ti.manifest, "").Digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ls, err := im.Layers()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := len(ls), 1; got != want {
+				t.Fatalf("got %v layers, want %v", got, want)
+			}
+
+			rc, err := ls[0].Compressed()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+
+			var lb bytes.Buffer
+			if _, err := lb.ReadFrom(rc); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := lb.Bytes(), ti.layer; !bytes.Equal(got, want) {
+				t.Errorf("got layer %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteLayoutTar(t *testing.T) {
+	ti := getTestImage(t)
+
+	tests := []struct {
+		name    string
+		f       *sif.FileImage
+		wantErr error
+	}{
+		{
+			name:    "DigestMismatch",
+			f:       getTestSIF(t, ti, true),
+			wantErr: errDigestMismatch,
+		},
+		{
+			name: "OK",
+			f:    getTestSIF(t, ti, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			err := WriteLayoutTar(tt.f, &b)
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				g := goldie.New(t, goldie.WithTestNameForDir(true))
+				g.Assert(t, tt.name, b.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package ocisif
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+var (
+	errDescriptorNotFound   = errors.New("descriptor not found in index")
+	errMultipleManifests    = errors.New("index must contain exactly one manifest")
+	errUnexpectedMediaType  = errors.New("unexpected media type")
+	errUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// verifyReader reads from a digestReader, returning an error in place of io.EOF if the digest of
+// the data read does not match.
+type verifyReader struct {
+	*digestReader
+}
+
+func (r verifyReader) Read(b []byte) (int, error) {
+	n, err := r.digestReader.Read(b)
+	if errors.Is(err, io.EOF) {
+		if err := r.verify(); err != nil {
+			return n, err
+		}
+	}
+	return n, err
+}
+
+// openObject returns a reader for the data object described by d. The digest of the object is
+// verified as it is read.
+func openObject(d sif.Descriptor) (io.Reader, error) {
+	h, err := d.OCIBlobDigest()
+	if err != nil {
+		return nil, err
+	}
+
+	dr, err := newDigestReader(d.GetReader(), h)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyReader{dr}, nil
+}
+
+// readObject returns the data object described by d, verifying its digest.
+func readObject(d sif.Descriptor) ([]byte, error) {
+	r, err := openObject(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+// getBlob returns the descriptor of the OCI blob object in f with digest h.
+func getBlob(f *sif.FileImage, h v1.Hash) (sif.Descriptor, error) {
+	ds, err := f.GetDescriptors(
+		sif.WithDataType(sif.DataOCIBlob),
+		sif.WithOCIBlobDigest(h),
+	)
+	if err != nil {
+		return sif.Descriptor{}, err
+	}
+
+	// Blobs are content addressable, so any matching object will do.
+	if len(ds) == 0 {
+		return sif.Descriptor{}, fmt.Errorf("blob %v: %w", h, sif.ErrObjectNotFound)
+	}
+	return ds[0], nil
+}
+
+// readBlob returns the contents of the OCI blob object in f with digest h, verifying its digest.
+func readBlob(f *sif.FileImage, h v1.Hash) ([]byte, error) {
+	d, err := getBlob(f, h)
+	if err != nil {
+		return nil, err
+	}
+
+	return readObject(d)
+}
+
+// fileImageIndex implements v1.ImageIndex using an index stored in a FileImage.
+type fileImageIndex struct {
+	f         *sif.FileImage
+	mediaType types.MediaType
+	rawIndex  []byte
+}
+
+var _ v1.ImageIndex = (*fileImageIndex)(nil)
+
+// ImageIndexFromFileImage returns a v1.ImageIndex corresponding to the OCI root index object in f.
+// The images and indexes referenced by the returned index are read from the OCI blob objects in
+// f. The digest of each object is verified as it is read.
+//
+// If f does not contain an OCI root index object, an error wrapping sif.ErrNoObjects or
+// sif.ErrObjectNotFound is returned. If f contains more than one OCI root index object, an error
+// wrapping sif.ErrMultipleObjectsFound is returned.
+func ImageIndexFromFileImage(f *sif.FileImage) (v1.ImageIndex, error) { //nolint:ireturn
+	d, err := f.GetDescriptor(sif.WithDataType(sif.DataOCIRootIndex))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	b, err := readObject(d)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return &fileImageIndex{
+		f:         f,
+		mediaType: types.OCIImageIndex,
+		rawIndex:  b,
+	}, nil
+}
+
+// ImageFromFileImage returns a v1.Image corresponding to the image with manifest digest h,
+// referenced by the OCI root index object in f. If h is the zero value, the OCI root index must
+// reference exactly one manifest, which is used.
+//
+// If f does not contain an OCI root index object, an error wrapping sif.ErrNoObjects or
+// sif.ErrObjectNotFound is returned. If f contains more than one OCI root index object, an error
+// wrapping sif.ErrMultipleObjectsFound is returned.
+func ImageFromFileImage(f *sif.FileImage, h v1.Hash) (v1.Image, error) { //nolint:ireturn
+	ii, err := ImageIndexFromFileImage(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	img, err := ii.Image(h)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return img, nil
+}
+
+// MediaType returns the media type of the index.
+func (i *fileImageIndex) MediaType() (types.MediaType, error) {
+	return i.mediaType, nil
+}
+
+// Digest returns the digest of the index manifest.
+func (i *fileImageIndex) Digest() (v1.Hash, error) {
+	return partial.Digest(i)
+}
+
+// Size returns the size of the index manifest.
+func (i *fileImageIndex) Size() (int64, error) {
+	return partial.Size(i)
+}
+
+// IndexManifest returns the parsed index manifest.
+func (i *fileImageIndex) IndexManifest() (*v1.IndexManifest, error) {
+	var index v1.IndexManifest
+	err := json.Unmarshal(i.rawIndex, &index)
+	return &index, err
+}
+
+// RawManifest returns the serialized bytes of the index manifest.
+func (i *fileImageIndex) RawManifest() ([]byte, error) {
+	return i.rawIndex, nil
+}
+
+// Image returns the v1.Image referenced by the index with manifest digest h.
+func (i *fileImageIndex) Image(h v1.Hash) (v1.Image, error) { //nolint:ireturn
+	desc, err := i.findDescriptor(h)
+	if err != nil {
+		return nil, err
+	}
+
+	if !desc.MediaType.IsImage() {
+		return nil, fmt.Errorf("%w for %v: %v", errUnexpectedMediaType, desc.Digest, desc.MediaType)
+	}
+
+	return partial.CompressedToImage(&fileImage{f: i.f, desc: *desc})
+}
+
+// ImageIndex returns the v1.ImageIndex referenced by the index with manifest digest h.
+func (i *fileImageIndex) ImageIndex(h v1.Hash) (v1.ImageIndex, error) { //nolint:ireturn
+	desc, err := i.findDescriptor(h)
+	if err != nil {
+		return nil, err
+	}
+
+	if !desc.MediaType.IsIndex() {
+		return nil, fmt.Errorf("%w for %v: %v", errUnexpectedMediaType, desc.Digest, desc.MediaType)
+	}
+
+	b, err := readBlob(i.f, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileImageIndex{
+		f:         i.f,
+		mediaType: desc.MediaType,
+		rawIndex:  b,
+	}, nil
+}
+
+// findDescriptor returns the descriptor in the index with digest h. If h is the zero value, the
+// index must contain exactly one descriptor, which is returned.
+func (i *fileImageIndex) findDescriptor(h v1.Hash) (*v1.Descriptor, error) {
+	im, err := i.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	if h == (v1.Hash{}) {
+		if len(im.Manifests) != 1 {
+			return nil, fmt.Errorf("%w: found %v", errMultipleManifests, len(im.Manifests))
+		}
+		return &im.Manifests[0], nil
+	}
+
+	for _, desc := range im.Manifests {
+		if desc.Digest == h {
+			return &desc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v", errDescriptorNotFound, h)
+}
+
+// fileImage implements partial.CompressedImageCore using an image stored in a FileImage.
+type fileImage struct {
+	f    *sif.FileImage
+	desc v1.Descriptor
+
+	manifestLock sync.Mutex // Protects rawManifest.
+	rawManifest  []byte
+}
+
+var _ partial.CompressedImageCore = (*fileImage)(nil)
+
+// MediaType returns the media type of the image.
+func (im *fileImage) MediaType() (types.MediaType, error) {
+	return im.desc.MediaType, nil
+}
+
+// Manifest returns the parsed image manifest.
+func (im *fileImage) Manifest() (*v1.Manifest, error) {
+	return partial.Manifest(im)
+}
+
+// RawManifest returns the serialized bytes of the image manifest.
+func (im *fileImage) RawManifest() ([]byte, error) {
+	im.manifestLock.Lock()
+	defer im.manifestLock.Unlock()
+
+	if im.rawManifest != nil {
+		return im.rawManifest, nil
+	}
+
+	b, err := readBlob(im.f, im.desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	im.rawManifest = b
+	return im.rawManifest, nil
+}
+
+// RawConfigFile returns the serialized bytes of the image config.
+func (im *fileImage) RawConfigFile() ([]byte, error) {
+	m, err := im.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	return readBlob(im.f, m.Config.Digest)
+}
+
+// LayerByDigest returns the layer (or config) referenced by the image with digest h.
+func (im *fileImage) LayerByDigest(h v1.Hash) (partial.CompressedLayer, error) { //nolint:ireturn
+	m, err := im.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	if h == m.Config.Digest {
+		return &compressedBlob{f: im.f, desc: m.Config}, nil
+	}
+
+	for _, desc := range m.Layers {
+		if h == desc.Digest {
+			return &compressedBlob{f: im.f, desc: desc}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %v", errDescriptorNotFound, h)
+}
+
+// compressedBlob implements partial.CompressedLayer using a blob stored in a FileImage.
+type compressedBlob struct {
+	f    *sif.FileImage
+	desc v1.Descriptor
+}
+
+// Digest returns the digest of the blob.
+func (b *compressedBlob) Digest() (v1.Hash, error) {
+	return b.desc.Digest, nil
+}
+
+// Compressed returns a reader for the blob. The digest of the blob is verified as it is read.
+func (b *compressedBlob) Compressed() (io.ReadCloser, error) {
+	d, err := getBlob(b.f, b.desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := openObject(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(r), nil
+}
+
+// Size returns the size of the blob.
+func (b *compressedBlob) Size() (int64, error) {
+	return b.desc.Size, nil
+}
+
+// MediaType returns the media type of the blob.
+func (b *compressedBlob) MediaType() (types.MediaType, error) {
+	return b.desc.MediaType, nil
+}
+
+// Descriptor returns the descriptor of the blob.
+func (b *compressedBlob) Descriptor() (*v1.Descriptor, error) {
+	return &b.desc, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package ocisif
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+func TestImageIndexFromFileImage(t *testing.T) {
+	ti := getTestImage(t)
+
+	tests := []struct {
+		name       string
+		f          *sif.FileImage
+		wantErr    error
+		wantDigest v1.Hash
+	}{
+		{
+			name: "NoRootIndex",
+			f: getTestSIF(t, testImage{
+				manifest: ti.manifest,
+				config:   ti.config,
+				layer:    ti.layer,
+			}, false),
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name:       "OK",
+			f:          getTestSIF(t, ti, false),
+			wantDigest: getDescriptor(t, ti.index, "").Digest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ii, err := ImageIndexFromFileImage(tt.f)
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				h, err := ii.Digest()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got, want := h, tt.wantDigest; got != want {
+					t.Errorf("got digest %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestImageFromFileImage(t *testing.T) {
+	ti := getTestImage(t)
+
+	tests := []struct {
+		name         string
+		f            *sif.FileImage
+		h            v1.Hash
+		wantErr      error
+		wantLayerErr error
+	}{
+		{
+			name:    "DescriptorNotFound",
+			f:       getTestSIF(t, ti, false),
+			h:       getDescriptor(t, ti.config, "").Digest,
+			wantErr: errDescriptorNotFound,
+		},
+		{
+			name:         "DigestMismatch",
+			f:            getTestSIF(t, ti, true),
+			wantLayerErr: errDigestMismatch,
+		},
+		{
+			name: "Digest",
+			f:    getTestSIF(t, ti, false),
+			h:    getDescriptor(t, ti.manifest, "").Digest,
+		},
+		{
+			name: "ZeroDigest",
+			f:    getTestSIF(t, ti, false),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := ImageFromFileImage(tt.f, tt.h)
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err != nil {
+				return
+			}
+
+			b, err := img.RawManifest()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := b, ti.manifest; !bytes.Equal(got, want) {
+				t.Errorf("got manifest %s, want %s", got, want)
+			}
+
+			cf, err := img.ConfigFile()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := cf.Architecture, "amd64"; got != want {
+				t.Errorf("got architecture %v, want %v", got, want)
+			}
+
+			l, err := img.LayerByDigest(getDescriptor(t, ti.layer, "").Digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rc, err := l.Compressed()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+
+			b, err = io.ReadAll(rc)
+
+			if got, want := err, tt.wantLayerErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				if got, want := b, ti.layer; !bytes.Equal(got, want) {
+					t.Errorf("got layer %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package ocisif
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// spareDescriptorCapacity is the number of unused descriptors reserved by default when writing an
+// image, so that objects such as signatures can be added later.
+const spareDescriptorCapacity = 48
+
+// lazyReader opens the underlying reader on first read, and closes it once it returns an error
+// (including io.EOF).
+type lazyReader struct {
+	open func() (io.ReadCloser, error)
+	rc   io.ReadCloser
+	done bool
+}
+
+func (r *lazyReader) Read(b []byte) (int, error) {
+	if r.done {
+		return 0, io.EOF
+	}
+
+	if r.rc == nil {
+		rc, err := r.open()
+		if err != nil {
+			r.done = true
+			return 0, err
+		}
+		r.rc = rc
+	}
+
+	n, err := r.rc.Read(b)
+	if err != nil {
+		r.done = true
+		if cerr := r.rc.Close(); cerr != nil && errors.Is(err, io.EOF) {
+			err = cerr
+		}
+	}
+	return n, err
+}
+
+// Close closes the underlying reader, if it is open.
+func (r *lazyReader) Close() error {
+	if r.rc == nil || r.done {
+		return nil
+	}
+	r.done = true
+	return r.rc.Close()
+}
+
+// indexWriter accumulates the data objects required to store an image index in a SIF.
+type indexWriter struct {
+	dis     []sif.DescriptorInput
+	readers []*lazyReader
+	written map[v1.Hash]bool
+}
+
+// addObject adds a data object of type t with digest h and size n, with data read from the
+// reader returned by open.
+func (w *indexWriter) addObject(t sif.DataType, h v1.Hash, n int64, open func() (io.ReadCloser, error)) error {
+	r := &lazyReader{open: open}
+
+	di, err := sif.NewDescriptorInput(t, r,
+		sif.OptObjectSize(n),
+		sif.OptOCIBlobDigest(h),
+	)
+	if err != nil {
+		return err
+	}
+
+	w.dis = append(w.dis, di)
+	w.readers = append(w.readers, r)
+
+	return nil
+}
+
+// addBlob adds an OCI blob object with digest h and size n, with data read from the reader
+// returned by open. If an object with digest h has already been added, it is not added again.
+func (w *indexWriter) addBlob(h v1.Hash, n int64, open func() (io.ReadCloser, error)) error {
+	if w.written[h] {
+		return nil
+	}
+
+	if err := w.addObject(sif.DataOCIBlob, h, n, open); err != nil {
+		return err
+	}
+
+	w.written[h] = true
+
+	return nil
+}
+
+// addBytes adds an OCI blob object containing b, which must have digest h.
+func (w *indexWriter) addBytes(h v1.Hash, b []byte) error {
+	return w.addBlob(h, int64(len(b)), func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	})
+}
+
+// addManifest adds an OCI blob object containing the manifest of m.
+func (w *indexWriter) addManifest(m partial.WithRawManifest) error {
+	b, err := m.RawManifest()
+	if err != nil {
+		return err
+	}
+
+	h, _, err := v1.SHA256(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	return w.addBytes(h, b)
+}
+
+// addImage adds OCI blob objects containing the manifest, config and layers of img.
+func (w *indexWriter) addImage(img v1.Image) error {
+	if err := w.addManifest(img); err != nil {
+		return err
+	}
+
+	h, err := img.ConfigName()
+	if err != nil {
+		return err
+	}
+
+	b, err := img.RawConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if err := w.addBytes(h, b); err != nil {
+		return err
+	}
+
+	ls, err := img.Layers()
+	if err != nil {
+		return err
+	}
+
+	for _, l := range ls {
+		h, err := l.Digest()
+		if err != nil {
+			return err
+		}
+
+		n, err := l.Size()
+		if err != nil {
+			return err
+		}
+
+		if err := w.addBlob(h, n, l.Compressed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addIndex adds OCI blob objects containing the images and indexes referenced by ii. The
+// manifest of ii itself is not added.
+func (w *indexWriter) addIndex(ii v1.ImageIndex) error {
+	im, err := ii.IndexManifest()
+	if err != nil {
+		return err
+	}
+
+	for _, desc := range im.Manifests {
+		switch {
+		case desc.MediaType.IsIndex():
+			child, err := ii.ImageIndex(desc.Digest)
+			if err != nil {
+				return err
+			}
+
+			if err := w.addManifest(child); err != nil {
+				return err
+			}
+
+			if err := w.addIndex(child); err != nil {
+				return err
+			}
+
+		case desc.MediaType.IsImage():
+			img, err := ii.Image(desc.Digest)
+			if err != nil {
+				return err
+			}
+
+			if err := w.addImage(img); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("%w for %v: %v", errUnsupportedMediaType, desc.Digest, desc.MediaType)
+		}
+	}
+
+	return nil
+}
+
+// WriteImageIndex writes a new SIF container to w, containing ii, according to opts.
+//
+// The manifest of ii is stored in an OCI root index object. The manifests, configs and layers of
+// the images and indexes referenced by ii are stored in OCI blob objects. Blobs are deduplicated,
+// so each unique blob is stored once, regardless of how many times it is referenced. Layers are
+// read one at a time, as they are written to w. The digest of each blob is verified as it is
+// written. If the digest of a blob does not match, an error is returned, and the data written to
+// w should be discarded.
+//
+// The SIF container is written using sif.WriteContainer, so w need not support seeking. Options
+// that modify the image header, such as sif.OptCreateDeterministic, may be supplied in opts. By
+// default, the descriptor capacity of the image is the number of objects written plus 48, leaving
+// room for objects such as signatures to be added later. To override this, use
+// sif.OptCreateWithDescriptorCapacity.
+func WriteImageIndex(w io.Writer, ii v1.ImageIndex, opts ...sif.CreateOpt) error {
+	iw := indexWriter{written: make(map[v1.Hash]bool)}
+	defer func() {
+		for _, r := range iw.readers {
+			_ = r.Close()
+		}
+	}()
+
+	h, err := ii.Digest()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	b, err := ii.RawManifest()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	err = iw.addObject(sif.DataOCIRootIndex, h, int64(len(b)), func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := iw.addIndex(ii); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	// Size the descriptor table to fit the blobs, with spare capacity. This precedes opts, so that
+	// the capacity may be overridden by the caller.
+	n := int64(len(iw.dis)) + spareDescriptorCapacity
+	opts = append([]sif.CreateOpt{sif.OptCreateWithDescriptorCapacity(n)}, opts...)
+	opts = append(opts, sif.OptCreateWithDescriptors(iw.dis...))
+
+	if err := sif.WriteContainer(w, opts...); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// WriteImage writes a new SIF container to w, containing img, according to opts.
+//
+// The manifest of img is referenced by an OCI image index, which is stored in an OCI root index
+// object. If the platform of img is specified in its config, it is recorded in the image index.
+// The image is otherwise written as described for WriteImageIndex.
+func WriteImage(w io.Writer, img v1.Image, opts ...sif.CreateOpt) error {
+	cf, err := img.ConfigFile()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	ii := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{
+		Add: img,
+		Descriptor: v1.Descriptor{
+			Platform: cf.Platform(),
+		},
+	})
+
+	return WriteImageIndex(w, ii, opts...)
+}
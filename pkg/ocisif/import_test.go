@@ -0,0 +1,250 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package ocisif
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// getRandomImage returns a random image with the specified number of layers.
+func getRandomImage(t *testing.T, layers int64) v1.Image { //nolint:ireturn
+	t.Helper()
+
+	img, err := random.Image(64, layers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+// loadContainer loads the SIF image contained in b.
+func loadContainer(t *testing.T, b []byte) *sif.FileImage {
+	t.Helper()
+
+	f, err := sif.LoadContainerReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.UnloadContainer() })
+
+	return f
+}
+
+// checkDigest checks that the digests of got and want match.
+func checkDigest(t *testing.T, got, want interface{ Digest() (v1.Hash, error) }) {
+	t.Helper()
+
+	g, err := got.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := want.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g != w {
+		t.Errorf("got digest %v, want %v", g, w)
+	}
+}
+
+// checkImage checks that got and want contain identical manifests, configs and layers.
+func checkImage(t *testing.T, got, want v1.Image) {
+	t.Helper()
+
+	checkDigest(t, got, want)
+
+	gc, err := got.RawConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wc, err := want.RawConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(gc, wc) {
+		t.Errorf("got config %s, want %s", gc, wc)
+	}
+
+	wls, err := want.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, wl := range wls {
+		h, err := wl.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gl, err := got.LayerByDigest(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkDigest(t, gl, wl)
+
+		rc, err := gl.Compressed()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Reading the layer verifies its digest.
+		if _, err := io.Copy(io.Discard, rc); err != nil {
+			t.Fatal(err)
+		}
+		rc.Close()
+	}
+}
+
+func TestWriteImage(t *testing.T) {
+	img := getRandomImage(t, 2)
+
+	var b bytes.Buffer
+
+	if err := WriteImage(&b, img, sif.OptCreateDeterministic()); err != nil {
+		t.Fatal(err)
+	}
+
+	f := loadContainer(t, b.Bytes())
+
+	// Root index, manifest, config and two layers.
+	if got, want := f.DescriptorsTotal()-f.DescriptorsFree(), int64(5); got != want {
+		t.Errorf("got %v objects, want %v", got, want)
+	}
+
+	got, err := ImageFromFileImage(f, v1.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkImage(t, got, img)
+}
+
+func TestWriteImage_DescriptorCapacity(t *testing.T) {
+	// More blobs than the default descriptor capacity.
+	img := getRandomImage(t, 50)
+
+	tests := []struct {
+		name      string
+		opts      []sif.CreateOpt
+		wantTotal int64
+	}{
+		{
+			name:      "Default",
+			opts:      []sif.CreateOpt{sif.OptCreateDeterministic()},
+			wantTotal: 101,
+		},
+		{
+			name: "Capacity",
+			opts: []sif.CreateOpt{
+				sif.OptCreateDeterministic(),
+				sif.OptCreateWithDescriptorCapacity(64),
+			},
+			wantTotal: 64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			if err := WriteImage(&b, img, tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			f := loadContainer(t, b.Bytes())
+
+			if got, want := f.DescriptorsTotal(), tt.wantTotal; got != want {
+				t.Errorf("got %v descriptors, want %v", got, want)
+			}
+
+			// Root index, manifest, config and fifty layers.
+			if got, want := f.DescriptorsTotal()-f.DescriptorsFree(), int64(53); got != want {
+				t.Errorf("got %v objects, want %v", got, want)
+			}
+
+			got, err := ImageFromFileImage(f, v1.Hash{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkImage(t, got, img)
+		})
+	}
+}
+
+func TestWriteImageIndex(t *testing.T) {
+	img1 := getRandomImage(t, 1)
+	img2 := getRandomImage(t, 2)
+
+	// A nested index referencing the first image, so that its blobs are referenced twice.
+	child := mutate.AppendManifests(empty.Index, mutate.IndexAddendum{Add: img1})
+
+	ii := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: img1},
+		mutate.IndexAddendum{Add: img2},
+		mutate.IndexAddendum{Add: child},
+	)
+
+	var b bytes.Buffer
+
+	if err := WriteImageIndex(&b, ii, sif.OptCreateDeterministic()); err != nil {
+		t.Fatal(err)
+	}
+
+	f := loadContainer(t, b.Bytes())
+
+	// Root index, child index, and the manifest, config and layers of each image. The blobs of the
+	// first image are stored once.
+	if got, want := f.DescriptorsTotal()-f.DescriptorsFree(), int64(1+1+3+4); got != want {
+		t.Errorf("got %v objects, want %v", got, want)
+	}
+
+	got, err := ImageIndexFromFileImage(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkDigest(t, got, ii)
+
+	for _, want := range []v1.Image{img1, img2} {
+		h, err := want.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := got.Image(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkImage(t, img, want)
+	}
+
+	h, err := child.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotChild, err := got.ImageIndex(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkDigest(t, gotChild, child)
+}
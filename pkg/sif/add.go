@@ -14,7 +14,8 @@ import (
 
 // addOpts accumulates object add options.
 type addOpts struct {
-	t time.Time
+	t    time.Time
+	grow int64
 }
 
 // AddOpt are used to specify object add options.
@@ -37,11 +38,28 @@ func OptAddWithTime(t time.Time) AddOpt {
 	}
 }
 
+// OptAddGrowDescriptors specifies that if no unused descriptor is available, the descriptor
+// capacity of the image should be increased by n descriptors prior to adding the object. See
+// GrowDescriptors for details.
+func OptAddGrowDescriptors(n int64) AddOpt {
+	return func(ao *addOpts) error {
+		ao.grow = n
+		return nil
+	}
+}
+
 // AddObject adds a new data object and its descriptor into the specified SIF file.
 //
 // By default, the image modification time is set to the current time for non-deterministic images,
 // and unset otherwise. To override this, consider using OptAddDeterministic or OptAddWithTime.
+//
+// By default, an error is returned if the image has no unused descriptor. To increase the
+// descriptor capacity of the image as required, consider using OptAddGrowDescriptors.
 func (f *FileImage) AddObject(di DescriptorInput, opts ...AddOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
 	ao := addOpts{}
 
 	if !f.isDeterministic() {
@@ -63,6 +81,12 @@ func (f *FileImage) AddObject(di DescriptorInput, opts ...AddOpt) error {
 		i++
 	}
 
+	if i == len(f.rds) && ao.grow > 0 {
+		if err := f.GrowDescriptors(ao.grow, OptGrowWithTime(ao.t)); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
 	if err := f.writeDataObject(i, di, ao.t); err != nil {
 		return fmt.Errorf("%w", err)
 	}
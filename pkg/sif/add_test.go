@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2023, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -30,6 +30,20 @@ func TestAddObject(t *testing.T) {
 			di:      getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
 			wantErr: errInsufficientCapacity,
 		},
+		{
+			name: "GrowDescriptors",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(1),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			di: getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+			opts: []AddOpt{
+				OptAddGrowDescriptors(2),
+			},
+		},
 		{
 			name: "ErrPrimaryPartition",
 			createOpts: []CreateOpt{
@@ -41,10 +55,53 @@ func TestAddObject(t *testing.T) {
 				),
 			},
 			di: getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
-				OptPartitionMetadata(FsSquash, PartPrimSys, "amd64"),
+				OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
 			),
 			wantErr: errPrimaryPartition,
 		},
+		{
+			name: "ErrPrimaryPartitionUnknownArch",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptMetadata(partition{Fstype: FsSquash, Parttype: PartPrimSys, Arch: testArchUnknown}),
+					),
+				),
+			},
+			di: getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+				OptMetadata(partition{Fstype: FsSquash, Parttype: PartPrimSys, Arch: testArchUnknown}),
+			),
+			wantErr: errPrimaryPartition,
+		},
+		{
+			name: "PrimaryPartitionUnknownArch",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+				),
+			},
+			di: getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+				OptMetadata(partition{Fstype: FsSquash, Parttype: PartPrimSys, Arch: testArchUnknown}),
+			),
+		},
+		{
+			name: "PrimaryPartitionSecondArch",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+				),
+			},
+			di: getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+				OptPartitionMetadata(FsSquash, PartPrimSys, "amd64"),
+			),
+		},
 		{
 			name: "Deterministic",
 			createOpts: []CreateOpt{
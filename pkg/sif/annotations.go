@@ -0,0 +1,145 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AnnotationsObjectName is the name of the DataGenericJSON objects that record the annotations of
+// the data object they are linked to.
+const AnnotationsObjectName = "sif.annotations"
+
+var errAnnotateAnnotations = errors.New("cannot annotate annotations object")
+
+// isAnnotations returns true if d is an annotations object.
+func isAnnotations(d Descriptor) bool {
+	_, isGroup := d.LinkedID()
+	return d.DataType() == DataGenericJSON && d.Name() == AnnotationsObjectName && !isGroup
+}
+
+// withAnnotationsOf returns a selector func that selects the annotations object linked to the data
+// object with id.
+func withAnnotationsOf(id uint32) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		if !isAnnotations(d) {
+			return false, nil
+		}
+
+		linkedID, _ := d.LinkedID()
+		return linkedID == id, nil
+	}
+}
+
+// getAnnotations returns the annotations of the data object with id.
+func (f *FileImage) getAnnotations(id uint32) (map[string]string, error) {
+	rd, err := f.getDescriptor(withAnnotationsOf(id))
+	if errors.Is(err, ErrObjectNotFound) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	b, err := f.descriptorFromRaw(rd).GetData()
+	if err != nil {
+		return nil, err
+	}
+
+	annotations := make(map[string]string)
+	if err := json.Unmarshal(b, &annotations); err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+// SetAnnotations sets the annotations of the data object with id to annotations, according to
+// opts. Any existing annotations of the data object are replaced. If annotations is empty, any
+// existing annotations are removed.
+//
+// Annotations are recorded as JSON in a DataGenericJSON object named AnnotationsObjectName that
+// is linked to the annotated data object. The annotations object is not part of an object group,
+// so setting annotations does not invalidate the signatures of object groups.
+//
+// When existing annotations are replaced, the new annotations are written to the end of the image
+// and the space previously occupied by the annotations object is left unused. Likewise, removing
+// annotations leaves the space occupied by the annotations object unused. To reclaim this space,
+// consider using Compact.
+//
+// By default, the image/object modification times are set to the current time for
+// non-deterministic images, and unset otherwise. To override this, consider using
+// OptSetDeterministic or OptSetWithTime.
+func (f *FileImage) SetAnnotations(id uint32, annotations map[string]string, opts ...SetOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
+	so := setOpts{}
+
+	if !f.isDeterministic() {
+		so.t = time.Now()
+	}
+
+	for _, opt := range opts {
+		if err := opt(&so); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	rd, err := f.getDescriptor(WithID(id))
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if isAnnotations(f.descriptorFromRaw(rd)) {
+		return fmt.Errorf("%w", errAnnotateAnnotations)
+	}
+
+	ad, err := f.getDescriptor(withAnnotationsOf(id))
+	if err != nil && !errors.Is(err, ErrObjectNotFound) {
+		return fmt.Errorf("%w", err)
+	}
+
+	if len(annotations) == 0 {
+		if ad == nil {
+			return nil
+		}
+		if err := f.DeleteObject(ad.ID, OptDeleteWithTime(so.t)); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		return nil
+	}
+
+	b, err := json.Marshal(annotations)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if ad != nil {
+		if _, err := f.ReplaceObject(ad.ID, bytes.NewReader(b), OptReplaceWithTime(so.t)); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		return nil
+	}
+
+	di, err := NewDescriptorInput(DataGenericJSON, bytes.NewReader(b),
+		OptNoGroup(),
+		OptLinkedID(id),
+		OptObjectName(AnnotationsObjectName),
+	)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := f.AddObject(di, OptAddWithTime(so.t)); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestSetAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		createOpts  []CreateOpt
+		initial     map[string]string
+		id          uint32
+		annotations map[string]string
+		opts        []SetOpt
+		wantErr     error
+	}{
+		{
+			name: "Deterministic",
+			createOpts: []CreateOpt{
+				OptCreateWithID("de170c43-36ab-44a8-bca9-1ea1a070a274"),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+				OptCreateWithTime(time.Unix(946702800, 0)),
+			},
+			id:          1,
+			annotations: map[string]string{"org.example.source": "git+https://example.com/a.git"},
+			opts: []SetOpt{
+				OptSetDeterministic(),
+			},
+		},
+		{
+			name: "WithTime",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			id:          1,
+			annotations: map[string]string{"org.example.source": "git+https://example.com/a.git"},
+			opts: []SetOpt{
+				OptSetWithTime(time.Unix(946702800, 0)),
+			},
+		},
+		{
+			name: "Add",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			id: 2,
+			annotations: map[string]string{
+				"org.example.source":   "git+https://example.com/a.git",
+				"org.example.revision": "0123456789abcdef",
+			},
+		},
+		{
+			name: "Replace",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			initial:     map[string]string{"org.example.source": "git+https://example.com/a.git"},
+			id:          1,
+			annotations: map[string]string{"org.example.source": "git+https://example.com/b.git"},
+		},
+		{
+			name: "Remove",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			initial: map[string]string{"org.example.source": "git+https://example.com/a.git"},
+			id:      1,
+		},
+		{
+			name: "RemoveNone",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			id: 1,
+		},
+		{
+			name: "ObjectNotFound",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			id:          2,
+			annotations: map[string]string{"org.example.source": "git+https://example.com/a.git"},
+			wantErr:     ErrObjectNotFound,
+		},
+		{
+			name: "AnnotateAnnotations",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			initial:     map[string]string{"org.example.source": "git+https://example.com/a.git"},
+			id:          2,
+			annotations: map[string]string{"org.example.source": "git+https://example.com/a.git"},
+			wantErr:     errAnnotateAnnotations,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b, tt.createOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.initial != nil {
+				if err := f.SetAnnotations(1, tt.initial); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err = f.SetAnnotations(tt.id, tt.annotations, tt.opts...)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+
+			if err := f.UnloadContainer(); err != nil {
+				t.Error(err)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
+
+func TestDescriptor_Annotations(t *testing.T) {
+	f, err := CreateContainer(&Buffer{},
+		OptCreateDeterministic(),
+		OptCreateWithDescriptors(
+			getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations := map[string]string{"org.example.source": "git+https://example.com/a.git"}
+
+	if err := f.SetAnnotations(1, annotations); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		d               func(t *testing.T) Descriptor
+		wantAnnotations map[string]string
+	}{
+		{
+			name: "Annotated",
+			d: func(t *testing.T) Descriptor {
+				t.Helper()
+
+				d, err := f.GetDescriptor(WithID(1))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return d
+			},
+			wantAnnotations: annotations,
+		},
+		{
+			name: "NotAnnotated",
+			d: func(t *testing.T) Descriptor {
+				t.Helper()
+
+				d, err := f.GetDescriptor(WithID(2))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return d
+			},
+			wantAnnotations: map[string]string{},
+		},
+		{
+			name: "NoImage",
+			d: func(*testing.T) Descriptor {
+				return Descriptor{raw: rawDescriptor{ID: 1}}
+			},
+			wantAnnotations: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.d(t).Annotations()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := tt.wantAnnotations; !reflect.DeepEqual(got, want) {
+				t.Errorf("got annotations %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWithAnnotation(t *testing.T) {
+	f, err := CreateContainer(&Buffer{},
+		OptCreateDeterministic(),
+		OptCreateWithDescriptors(
+			getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+			getDescriptorInput(t, DataGeneric, []byte{0xba, 0xbe}),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetAnnotations(1, map[string]string{"k": "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetAnnotations(3, map[string]string{"k": "a", "l": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		k       string
+		v       string
+		wantIDs []uint32
+	}{
+		{"Match", "k", "a", []uint32{1, 3}},
+		{"MatchOne", "l", "b", []uint32{3}},
+		{"ValueMismatch", "k", "b", nil},
+		{"KeyNotFound", "m", "a", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, err := f.GetDescriptors(WithAnnotation(tt.k, tt.v))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []uint32
+			for _, d := range ds {
+				ids = append(ids, d.ID())
+			}
+
+			if got, want := ids, tt.wantIDs; !reflect.DeepEqual(got, want) {
+				t.Errorf("got IDs %v, want %v", got, want)
+			}
+		})
+	}
+}
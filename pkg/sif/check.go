@@ -0,0 +1,426 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// Severity indicates the severity of a Finding.
+type Severity int
+
+// List of finding severities.
+const (
+	SeverityWarning Severity = iota + 1 // image is usable, but may not behave as expected
+	SeverityError                       // image is corrupt
+)
+
+// String returns a human-readable representation of s.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// FindingType identifies the structural invariant that a Finding concerns.
+type FindingType int
+
+// List of finding types.
+const (
+	FindingDescriptorSection FindingType = iota + 1 // descriptor section out of bounds
+	FindingDataSection                              // data section inconsistent with objects or image
+	FindingDescriptorsFree                          // free descriptor count inconsistent
+	FindingObjectID                                 // object ID invalid or not unique
+	FindingObjectBounds                             // object outside data section or image
+	FindingObjectOverlap                            // objects overlap
+	FindingPrimaryPartition                         // multiple primary partitions for an architecture
+	FindingPrimaryArch                              // header architecture inconsistent
+	FindingDanglingLink                             // linked object or group does not exist
+	FindingOCIBlobDigest                            // OCI blob digest invalid or mismatched
+	FindingInvalidContent                           // object content cannot be parsed
+)
+
+// String returns a human-readable representation of t.
+func (t FindingType) String() string {
+	switch t {
+	case FindingDescriptorSection:
+		return "descriptor-section"
+	case FindingDataSection:
+		return "data-section"
+	case FindingDescriptorsFree:
+		return "descriptors-free"
+	case FindingObjectID:
+		return "object-id"
+	case FindingObjectBounds:
+		return "object-bounds"
+	case FindingObjectOverlap:
+		return "object-overlap"
+	case FindingPrimaryPartition:
+		return "primary-partition"
+	case FindingPrimaryArch:
+		return "primary-arch"
+	case FindingDanglingLink:
+		return "dangling-link"
+	case FindingOCIBlobDigest:
+		return "oci-blob-digest"
+	case FindingInvalidContent:
+		return "invalid-content"
+	}
+	return "unknown"
+}
+
+// Finding describes a violation of a structural invariant of an image.
+type Finding struct {
+	Type     FindingType // Invariant violated.
+	Severity Severity    // Severity of violation.
+	ID       uint32      // ID of the data object concerned, or zero if not specific to an object.
+	Message  string      // Human-readable description.
+}
+
+// String returns a human-readable representation of f.
+func (f Finding) String() string {
+	if f.ID == 0 {
+		return fmt.Sprintf("%v: %v", f.Severity, f.Message)
+	}
+	return fmt.Sprintf("%v: object %d: %v", f.Severity, f.ID, f.Message)
+}
+
+// checker accumulates findings while checking an image.
+type checker struct {
+	f        *FileImage
+	size     int64     // Size of image.
+	findings []Finding // Findings accumulated.
+}
+
+// addf records a finding of type t and severity s, concerning the object with the specified ID.
+func (c *checker) addf(t FindingType, s Severity, id uint32, format string, a ...any) {
+	c.findings = append(c.findings, Finding{
+		Type:     t,
+		Severity: s,
+		ID:       id,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// checkHeader checks that the descriptor and data sections described by the global header are
+// within the bounds of the image, and consistent with the descriptors and objects.
+func (c *checker) checkHeader() {
+	h := c.f.h
+
+	if hdrSize := int64(binary.Size(h)); h.DescriptorsOffset < hdrSize {
+		c.addf(FindingDescriptorSection, SeverityError, 0,
+			"descriptor section (offset %d) overlaps global header (size %d)", h.DescriptorsOffset, hdrSize)
+	}
+
+	if end := h.DescriptorsOffset + h.DescriptorsSize; end > h.DataOffset {
+		c.addf(FindingDescriptorSection, SeverityError, 0,
+			"descriptor section (end %d) overlaps data section (offset %d)", end, h.DataOffset)
+	}
+
+	if end := h.DescriptorsOffset + h.DescriptorsSize; end > c.size {
+		c.addf(FindingDescriptorSection, SeverityError, 0,
+			"descriptor section (end %d) extends beyond end of image (size %d)", end, c.size)
+	}
+
+	if want := h.DescriptorsTotal * int64(binary.Size(rawDescriptor{})); h.DescriptorsSize != want {
+		c.addf(FindingDescriptorSection, SeverityError, 0,
+			"descriptor section size %d is inconsistent with descriptor count %d (want size %d)",
+			h.DescriptorsSize, h.DescriptorsTotal, want)
+	}
+
+	if end := h.DataOffset + h.DataSize; end > c.size {
+		c.addf(FindingDataSection, SeverityError, 0,
+			"data section (end %d) extends beyond end of image (size %d)", end, c.size)
+	} else if end := max(end, h.DescriptorsOffset+h.DescriptorsSize); end < c.size {
+		c.addf(FindingDataSection, SeverityWarning, 0,
+			"%d bytes of trailing data follow data section (end %d)", c.size-end, end)
+	}
+
+	if want := c.f.calculatedDataSize(); h.DataSize < want {
+		c.addf(FindingDataSection, SeverityError, 0,
+			"data section size %d is smaller than required by objects (%d)", h.DataSize, want)
+	}
+
+	var free int64
+	for _, rd := range c.f.rds {
+		if !rd.Used {
+			free++
+		}
+	}
+
+	if h.DescriptorsFree != free {
+		c.addf(FindingDescriptorsFree, SeverityError, 0,
+			"header indicates %d free descriptors, found %d", h.DescriptorsFree, free)
+	}
+}
+
+// checkObjects checks that object IDs are unique, and that objects are within the bounds of the
+// data section and image, and do not overlap.
+func (c *checker) checkObjects(ds []Descriptor) {
+	ids := make(map[uint32]bool)
+
+	for _, d := range ds {
+		switch id := d.ID(); {
+		case id == 0:
+			c.addf(FindingObjectID, SeverityError, 0, "object with invalid ID 0")
+		case ids[id]:
+			c.addf(FindingObjectID, SeverityError, id, "ID is not unique")
+		default:
+			ids[id] = true
+		}
+
+		if d.Offset() < c.f.h.DataOffset || d.Size() < 0 {
+			c.addf(FindingObjectBounds, SeverityError, d.ID(),
+				"object (offset %d, size %d) is outside data section (offset %d)",
+				d.Offset(), d.Size(), c.f.h.DataOffset)
+		} else if end := d.Offset() + d.Size(); end > c.size {
+			c.addf(FindingObjectBounds, SeverityError, d.ID(),
+				"object (end %d) extends beyond end of image (size %d)", end, c.size)
+		}
+	}
+
+	sorted := slices.SortedStableFunc(slices.Values(ds), func(a, b Descriptor) int {
+		return cmp.Compare(a.Offset(), b.Offset())
+	})
+
+	var prev Descriptor
+	for _, d := range sorted {
+		if d.Size() <= 0 {
+			continue
+		}
+
+		if prev.Size() > 0 && d.Offset() < prev.Offset()+prev.Size() {
+			c.addf(FindingObjectOverlap, SeverityError, d.ID(),
+				"object (offset %d) overlaps object %d (offset %d, size %d)",
+				d.Offset(), prev.ID(), prev.Offset(), prev.Size())
+		}
+
+		if d.Offset()+d.Size() > prev.Offset()+prev.Size() {
+			prev = d
+		}
+	}
+}
+
+// checkPrimaryPartition checks that there is at most one primary partition per architecture, and
+// that the architecture in the global header is consistent with the first primary partition.
+func (c *checker) checkPrimaryPartition(ds []Descriptor) {
+	var arches []archType
+	first := make(map[archType]uint32)
+
+	for _, d := range ds {
+		var p partition
+		if !d.raw.isPartitionOfType(PartPrimSys) || d.raw.getExtra(binaryUnmarshaler{&p}) != nil {
+			continue
+		}
+
+		if id, ok := first[p.Arch]; ok {
+			c.addf(FindingPrimaryPartition, SeverityError, d.ID(),
+				"additional primary partition for architecture %v (first is object %d)", p.Arch.GoArch(), id)
+			continue
+		}
+
+		first[p.Arch] = d.ID()
+		arches = append(arches, p.Arch)
+	}
+
+	if len(arches) == 0 {
+		if c.f.h.Arch != hdrArchUnknown {
+			c.addf(FindingPrimaryArch, SeverityWarning, 0,
+				"header architecture is %v, but image contains no primary partition", c.f.h.Arch.GoArch())
+		}
+		return
+	}
+
+	if want := arches[0]; c.f.h.Arch != want {
+		c.addf(FindingPrimaryArch, SeverityError, first[want],
+			"header architecture %v does not match primary partition architecture %v",
+			c.f.h.Arch.GoArch(), want.GoArch())
+	}
+}
+
+// checkLinks checks that each linked object or object group exists.
+func (c *checker) checkLinks(ds []Descriptor) {
+	ids := make(map[uint32]bool)
+	groupIDs := make(map[uint32]bool)
+
+	for _, d := range ds {
+		ids[d.ID()] = true
+
+		if id := d.GroupID(); id != 0 {
+			groupIDs[id] = true
+		}
+	}
+
+	for _, d := range ds {
+		switch id, isGroup := d.LinkedID(); {
+		case id == 0:
+		case isGroup && !groupIDs[id]:
+			c.addf(FindingDanglingLink, SeverityError, d.ID(), "linked object group %d does not exist", id)
+		case !isGroup && !ids[id]:
+			c.addf(FindingDanglingLink, SeverityError, d.ID(), "linked object %d does not exist", id)
+		}
+	}
+}
+
+var errTrailingData = errors.New("unexpected data following value")
+
+// validateJSON returns an error if r does not contain a single valid JSON value.
+func validateJSON(r io.Reader) error {
+	dec := json.NewDecoder(r)
+
+	var v json.RawMessage
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errTrailingData
+	}
+
+	return nil
+}
+
+// validateXML returns an error if r does not contain well-formed XML.
+func validateXML(r io.Reader) error {
+	dec := xml.NewDecoder(r)
+
+	for {
+		if _, err := dec.Token(); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// checkContent checks that the content of d is consistent with its data type and metadata.
+func (c *checker) checkContent(d Descriptor) error {
+	var validate func(io.Reader) error
+
+	switch d.DataType() {
+	case DataLabels, DataGenericJSON:
+		validate = validateJSON
+
+	case DataSBOM:
+		f, err := d.SBOMMetadata()
+		if err != nil {
+			c.addf(FindingInvalidContent, SeverityError, d.ID(), "invalid SBOM metadata: %v", err)
+			return nil
+		}
+
+		switch f {
+		case SBOMFormatCycloneDXJSON, SBOMFormatGitHubJSON, SBOMFormatSPDXJSON, SBOMFormatSyftJSON:
+			validate = validateJSON
+		case SBOMFormatCycloneDXXML, SBOMFormatSPDXRDF:
+			validate = validateXML
+		}
+
+	case DataOCIRootIndex, DataOCIBlob:
+		return c.checkOCIBlob(d)
+	}
+
+	if validate != nil {
+		if err := validate(d.GetReader()); err != nil {
+			c.addf(FindingInvalidContent, SeverityError, d.ID(), "failed to parse %v content: %v", d.DataType(), err)
+		}
+	}
+
+	return nil
+}
+
+// checkOCIBlob checks that the digest of the OCI blob d matches its content. If d is a root index,
+// the content must also be valid JSON.
+func (c *checker) checkOCIBlob(d Descriptor) error {
+	want, err := d.OCIBlobDigest()
+	if err != nil {
+		c.addf(FindingOCIBlobDigest, SeverityError, d.ID(), "invalid digest: %v", err)
+		return nil
+	}
+
+	if want.Algorithm != "sha256" {
+		c.addf(FindingOCIBlobDigest, SeverityWarning, d.ID(),
+			"digest algorithm %v not supported, content not verified", want.Algorithm)
+		return nil
+	}
+
+	got, _, err := v1.SHA256(d.GetReader())
+	if err != nil {
+		return err
+	}
+
+	if got != want {
+		c.addf(FindingOCIBlobDigest, SeverityError, d.ID(), "digest mismatch: got %v, want %v", got, want)
+	}
+
+	if d.DataType() == DataOCIRootIndex {
+		if err := validateJSON(d.GetReader()); err != nil {
+			c.addf(FindingInvalidContent, SeverityError, d.ID(), "failed to parse %v content: %v", d.DataType(), err)
+		}
+	}
+
+	return nil
+}
+
+// Check checks f for violations of the structural invariants of the SIF format, which are not
+// verified when an image is loaded. The following are checked:
+//
+//   - The descriptor and data sections are within the bounds of the image, and the header
+//     fields describing them are consistent with the descriptors.
+//   - Object IDs are unique.
+//   - Objects are within the bounds of the data section and the image, and do not overlap.
+//   - There is at most one primary partition per architecture, and the architecture in the global
+//     header matches that of the first primary partition.
+//   - Linked objects and object groups exist.
+//   - The digests of OCI blobs match their content.
+//   - The content of JSON objects, and of SBOMs stored in JSON or XML formats, can be parsed.
+//
+// Each violation found is returned as a Finding. Content is only checked for objects that are
+// within the bounds of the image. If an error occurs while reading the image, the error is
+// returned.
+func Check(f *FileImage) ([]Finding, error) {
+	size, err := f.rw.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	c := checker{f: f, size: size}
+
+	var ds []Descriptor
+	f.WithDescriptors(func(d Descriptor) bool {
+		ds = append(ds, d)
+		return false
+	})
+
+	c.checkHeader()
+	c.checkObjects(ds)
+	c.checkPrimaryPartition(ds)
+	c.checkLinks(ds)
+
+	for _, d := range ds {
+		if d.Offset() < 0 || d.Size() < 0 || d.Offset()+d.Size() > size {
+			continue
+		}
+
+		if err := c.checkContent(d); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	return c.findings, nil
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestCheck_Corpus(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(corpus, "*.sif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := LoadContainerFromPath(path, OptLoadWithFlag(os.O_RDONLY))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { f.UnloadContainer() })
+
+			findings, err := Check(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(findings) > 0 {
+				t.Errorf("unexpected findings: %v", findings)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		dis     []DescriptorInput
+		corrupt func(*FileImage, *Buffer)
+	}{
+		{
+			name: "OK",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+				getDescriptorInput(t, DataGenericJSON, []byte(`{"a":1}`)),
+			},
+		},
+		{
+			name: "DescriptorSectionOverlapsHeader",
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.DescriptorsOffset = 16
+			},
+		},
+		{
+			name: "DescriptorSectionOverlapsData",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.DescriptorsOffset++
+			},
+		},
+		{
+			name: "DescriptorSectionBeyondImage",
+			corrupt: func(f *FileImage, b *Buffer) {
+				if err := b.Truncate(f.h.DescriptorsOffset + f.h.DescriptorsSize - 1); err != nil {
+					panic(err)
+				}
+			},
+		},
+		{
+			name: "DescriptorSectionSizeMismatch",
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.DescriptorsTotal++
+			},
+		},
+		{
+			name: "Truncated",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+			},
+			corrupt: func(_ *FileImage, b *Buffer) {
+				if err := b.Truncate(b.Len() - 1); err != nil {
+					panic(err)
+				}
+			},
+		},
+		{
+			name: "DataSizeTooSmall",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.DataSize--
+			},
+		},
+		{
+			name: "DescriptorsFree",
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.DescriptorsFree--
+			},
+		},
+		{
+			name: "ObjectIDZero",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.rds[0].ID = 0
+			},
+		},
+		{
+			name: "ObjectIDDuplicate",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.rds[1].ID = 1
+			},
+		},
+		{
+			name: "ObjectBeforeDataSection",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.rds[0].Offset = 0
+			},
+		},
+		{
+			name: "ObjectBeyondImage",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.rds[0].Size = 4096
+				f.h.DataSize = 4096
+			},
+		},
+		{
+			name: "ObjectOverlap",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				getDescriptorInput(t, DataGeneric, []byte{0xbe, 0xef}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.rds[0].Size = f.rds[2].Offset - f.rds[0].Offset + 1
+			},
+		},
+		{
+			name: "MultiplePrimaryPartitions",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+				getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+					OptPartitionMetadata(FsSquash, PartSystem, "386"),
+				),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				if err := f.rds[1].setExtra(partition{Fstype: FsSquash, Parttype: PartPrimSys, Arch: hdrArch386}); err != nil {
+					panic(err)
+				}
+			},
+		},
+		{
+			name: "MultiplePrimaryPartitionsMultiArch",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+				getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "amd64"),
+				),
+			},
+		},
+		{
+			name: "PrimaryArchMismatch",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.Arch = hdrArchAMD64
+			},
+		},
+		{
+			name: "PrimaryArchWithoutPartition",
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.Arch = hdrArchAMD64
+			},
+		},
+		{
+			name: "DanglingLink",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataSignature, []byte{0xfe, 0xed},
+					OptLinkedID(1),
+					OptSignatureMetadata(0, nil),
+				),
+				getDescriptorInput(t, DataSignature, []byte{0xbe, 0xef},
+					OptLinkedGroupID(1),
+					OptSignatureMetadata(0, nil),
+				),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.rds[1].LinkedID = 4
+				f.rds[2].LinkedID = 2 | descrGroupMask
+			},
+		},
+		{
+			name: "OCIBlobDigestMismatch",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataOCIBlob, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(f *FileImage, b *Buffer) {
+				b.Bytes()[f.rds[0].Offset] = 0xff
+			},
+		},
+		{
+			name: "InvalidJSON",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataLabels, []byte(`{"a":`)),
+				getDescriptorInput(t, DataGenericJSON, []byte(`{"a":1} {}`)),
+				getDescriptorInput(t, DataOCIRootIndex, []byte(`[`)),
+			},
+		},
+		{
+			name: "InvalidSBOM",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataSBOM, []byte(`{`),
+					OptSBOMMetadata(SBOMFormatCycloneDXJSON),
+				),
+				getDescriptorInput(t, DataSBOM, []byte(`<bom>`),
+					OptSBOMMetadata(SBOMFormatCycloneDXXML),
+				),
+				getDescriptorInput(t, DataSBOM, []byte(`not validated`),
+					OptSBOMMetadata(SBOMFormatSPDXTagValue),
+				),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b,
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(4),
+				OptCreateWithDescriptors(tt.dis...),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.corrupt != nil {
+				tt.corrupt(f, &b)
+			}
+
+			findings, err := Check(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			for _, f := range findings {
+				fmt.Fprintf(&out, "%v: %v\n", f.Type, f)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, out.Bytes())
+		})
+	}
+}
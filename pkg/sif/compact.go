@@ -0,0 +1,256 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// maxInferredAlignment is the maximum alignment inferred for a data object when it is relocated.
+const maxInferredAlignment = 1 << 16
+
+// inferredAlignment returns the alignment to honor when relocating the data object described by
+// d. The alignment requested when the object was added to the image is not recorded, so it is
+// inferred from the offset of the object: the largest power of two that divides the offset,
+// limited to maxInferredAlignment. This is only a guess; it may be larger than the alignment
+// originally requested, or smaller than it if the requested alignment exceeded
+// maxInferredAlignment.
+func (d rawDescriptor) inferredAlignment() int {
+	if d.Offset <= 0 {
+		return 0
+	}
+	return int(min(d.Offset&-d.Offset, maxInferredAlignment))
+}
+
+// syncer is implemented by backing storage that supports committing written data to stable
+// storage, such as *os.File.
+type syncer interface {
+	Sync() error
+}
+
+// sync commits the contents of the backing storage of f to stable storage, if supported.
+func (f *FileImage) sync() error {
+	if s, ok := f.rw.(syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// copyData copies n bytes at offset src to offset dst within the backing storage of f. The source
+// and destination regions must not overlap.
+func (f *FileImage) copyData(dst, src, n int64) error {
+	if _, err := f.rw.Seek(dst, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err := io.CopyN(f.rw, io.NewSectionReader(f.rw, src, n), n)
+	return err
+}
+
+// commitDescriptors writes the descriptors in f to backing storage, ensuring that any data
+// previously written is committed to stable storage beforehand, and the descriptors are
+// committed to stable storage afterwards.
+func (f *FileImage) commitDescriptors() error {
+	if err := f.sync(); err != nil {
+		return err
+	}
+
+	if err := f.writeDescriptors(); err != nil {
+		return err
+	}
+
+	return f.sync()
+}
+
+// commitHeader writes the header of f to backing storage, ensuring that any data previously
+// written is committed to stable storage beforehand, and the header is committed to stable storage
+// afterwards.
+func (f *FileImage) commitHeader() error {
+	if err := f.sync(); err != nil {
+		return err
+	}
+
+	if err := f.writeHeader(); err != nil {
+		return err
+	}
+
+	return f.sync()
+}
+
+// extendDataSection extends the data section of f, if necessary, so that it ends no earlier than
+// offset end. If the data section is extended, the header is committed to stable storage.
+func (f *FileImage) extendDataSection(end int64) error {
+	if size := end - f.h.DataOffset; size > f.h.DataSize {
+		f.h.DataSize = size
+
+		return f.commitHeader()
+	}
+	return nil
+}
+
+var errOverlappingObjects = errors.New("data objects overlap")
+
+// relocate moves the data object described by d to offset dst.
+//
+// The descriptor is only updated to reference data that has been completely written, so that an
+// interruption leaves an image in which all descriptors reference valid data. If the source and
+// destination regions overlap, the object is first copied to scratch space following both the end
+// of the data section and the destination region. The data section is extended as necessary before
+// the descriptor is updated, so that all objects remain within the data section.
+func (f *FileImage) relocate(d *rawDescriptor, dst int64) error {
+	if dst < d.Offset+d.Size && d.Offset < dst+d.Size {
+		end := max(f.h.DataOffset+f.calculatedDataSize(), dst+d.Size)
+
+		scratch, err := nextAligned(end, d.inferredAlignment())
+		if err != nil {
+			return err
+		}
+
+		if err := f.copyData(scratch, d.Offset, d.Size); err != nil {
+			return err
+		}
+
+		if err := f.extendDataSection(scratch + d.Size); err != nil {
+			return err
+		}
+
+		d.Offset = scratch
+
+		if err := f.commitDescriptors(); err != nil {
+			return err
+		}
+	}
+
+	if err := f.copyData(dst, d.Offset, d.Size); err != nil {
+		return err
+	}
+
+	if err := f.extendDataSection(dst + d.Size); err != nil {
+		return err
+	}
+
+	d.Offset = dst
+
+	return f.commitDescriptors()
+}
+
+// compactOpts accumulates image compaction options.
+type compactOpts struct {
+	t time.Time
+}
+
+// CompactOpt are used to specify image compaction options.
+type CompactOpt func(*compactOpts) error
+
+// OptCompactDeterministic sets header/descriptor fields to values that support deterministic
+// modification of images.
+func OptCompactDeterministic() CompactOpt {
+	return func(co *compactOpts) error {
+		co.t = time.Time{}
+		return nil
+	}
+}
+
+// OptCompactWithTime specifies t as the image modification time.
+func OptCompactWithTime(t time.Time) CompactOpt {
+	return func(co *compactOpts) error {
+		co.t = t
+		return nil
+	}
+}
+
+// Compact relocates the data objects in f to eliminate unused space in the data section, such as
+// that left behind by deleted objects, and truncates the image accordingly.
+//
+// Data objects retain their relative order. The alignment requested when each object was added is
+// not recorded in the image, so it is inferred from the offset of the object as the largest power
+// of two that divides it, up to a maximum of 64KiB. This is only a guess: an object may be aligned
+// more strictly than originally requested, or less strictly if the requested alignment exceeded
+// 64KiB. Object IDs and all integrity-protected descriptor fields are preserved, so existing
+// signatures remain valid.
+//
+// Compaction is crash-safe: data is always copied to unused space before the corresponding
+// descriptor is updated, and the data section is extended in the header before any descriptor
+// references data beyond its end, so an interrupted compaction leaves a valid image, albeit one
+// that may not be fully compacted. If the backing storage implements a Sync method (such as
+// *os.File), it is used to commit data before descriptors and the header are updated.
+//
+// By default, the image modification time is set to the current time for non-deterministic images,
+// and unset otherwise. To override this, consider using OptCompactDeterministic or
+// OptCompactWithTime.
+func (f *FileImage) Compact(opts ...CompactOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
+	co := compactOpts{}
+
+	if !f.isDeterministic() {
+		co.t = time.Now()
+	}
+
+	for _, opt := range opts {
+		if err := opt(&co); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	// Relocate objects in the order they appear in the data section.
+	var rds []*rawDescriptor
+	for i := range f.rds {
+		if f.rds[i].Used {
+			rds = append(rds, &f.rds[i])
+		}
+	}
+
+	slices.SortStableFunc(rds, func(a, b *rawDescriptor) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+
+	next := f.h.DataOffset
+
+	for _, d := range rds {
+		dst, err := nextAligned(next, d.inferredAlignment())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if dst > d.Offset {
+			return fmt.Errorf("%w", errOverlappingObjects)
+		}
+
+		if dst < d.Offset {
+			if err := f.relocate(d, dst); err != nil {
+				return fmt.Errorf("failed to relocate object %v: %w", d.ID, err)
+			}
+		}
+
+		d.SizeWithPadding = dst - next + d.Size
+		next = dst + d.Size
+	}
+
+	f.h.DataSize = next - f.h.DataOffset
+	f.h.ModifiedAt = co.t.Unix()
+
+	if err := f.commitDescriptors(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := f.writeHeader(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := f.rw.Truncate(next); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+// objectSnapshot contains the data and integrity-protected metadata of an object.
+type objectSnapshot struct {
+	data      []byte
+	integrity []byte
+}
+
+// getObjectSnapshot returns a snapshot of the objects in f, indexed by object ID.
+func getObjectSnapshot(t *testing.T, f *FileImage) map[uint32]objectSnapshot {
+	t.Helper()
+
+	m := make(map[uint32]objectSnapshot)
+
+	f.WithDescriptors(func(d Descriptor) bool {
+		b, err := d.GetData()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ib, err := io.ReadAll(d.GetIntegrityReader())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m[d.ID()] = objectSnapshot{b, ib}
+		return false
+	})
+
+	return m
+}
+
+func TestFileImage_Compact(t *testing.T) {
+	tests := []struct {
+		name       string
+		createOpts []CreateOpt
+		deleteIDs  []uint32
+		opts       []CompactOpt
+	}{
+		{
+			name: "Empty",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+			},
+		},
+		{
+			name: "NoGaps",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+		},
+		{
+			name: "DeleteFirst",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+					getDescriptorInput(t, DataGeneric, []byte{0xde, 0xad}),
+				),
+			},
+			deleteIDs: []uint32{1},
+		},
+		{
+			name: "DeleteMiddle",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+					getDescriptorInput(t, DataGeneric, []byte{0xde, 0xad}),
+				),
+			},
+			deleteIDs: []uint32{2},
+		},
+		{
+			name: "DeleteLast",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			deleteIDs: []uint32{2},
+		},
+		{
+			name: "Overlapping",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+					getDescriptorInput(t, DataGeneric, []byte{0xca, 0xfe, 0xf0, 0x0d, 0xfe, 0xed, 0xfa, 0xce}),
+				),
+			},
+			deleteIDs: []uint32{2},
+		},
+		{
+			name: "Aligned",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+					getDescriptorInput(t, DataGeneric, []byte{0xde, 0xad},
+						OptObjectAlignment(8),
+					),
+					getDescriptorInput(t, DataPartition, []byte{0xbe, 0xef},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+				),
+			},
+			deleteIDs: []uint32{1},
+		},
+		{
+			name: "Deterministic",
+			createOpts: []CreateOpt{
+				OptCreateWithID("de170c43-36ab-44a8-bca9-1ea1a070a274"),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+				OptCreateWithTime(time.Unix(946702800, 0)),
+			},
+			deleteIDs: []uint32{1},
+			opts: []CompactOpt{
+				OptCompactDeterministic(),
+			},
+		},
+		{
+			name: "WithTime",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			deleteIDs: []uint32{1},
+			opts: []CompactOpt{
+				OptCompactWithTime(time.Unix(946702800, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b, tt.createOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, id := range tt.deleteIDs {
+				if err := f.DeleteObject(id, OptDeleteDeterministic()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want := getObjectSnapshot(t, f)
+
+			if err := f.Compact(tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := getObjectSnapshot(t, f); !reflect.DeepEqual(got, want) {
+				t.Errorf("got objects %v, want %v", got, want)
+			}
+
+			if got, want := f.DataOffset()+f.DataSize(), int64(b.Len()); got != want {
+				t.Errorf("got image size %v, want %v", got, want)
+			}
+
+			if err := f.UnloadContainer(); err != nil {
+				t.Error(err)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
+
+func TestFileImage_Compact_Interrupted(t *testing.T) {
+	// load returns a FileImage loaded from a copy of the contents of b.
+	load := func(t *testing.T, b *failingBuffer) *FileImage {
+		t.Helper()
+
+		f, err := LoadContainer(NewBuffer(bytes.Clone(b.Bytes())))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	for failWrite := 0; ; failWrite++ {
+		b := &failingBuffer{Buffer: &Buffer{}, failWrite: -1}
+
+		f, err := CreateContainer(b,
+			OptCreateDeterministic(),
+			OptCreateWithDescriptors(
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataGeneric, []byte{0xca, 0xfe, 0xf0, 0x0d, 0xfe, 0xed, 0xfa, 0xce}),
+			),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.DeleteObject(1, OptDeleteDeterministic()); err != nil {
+			t.Fatal(err)
+		}
+
+		want := getObjectSnapshot(t, load(t, b))
+
+		b.failWrite = b.writes + failWrite
+
+		err = f.Compact()
+		if err == nil {
+			if failWrite == 0 {
+				t.Fatal("compaction performed no writes")
+			}
+			return
+		}
+
+		if !errors.Is(err, errTestWrite) {
+			t.Fatalf("write %v: got error %v, want %v", failWrite, err, errTestWrite)
+		}
+
+		// The image left behind by the interrupted compaction must be valid.
+		g := load(t, b)
+
+		findings, err := Check(g)
+		if err != nil {
+			t.Fatalf("write %v: %v", failWrite, err)
+		}
+
+		for _, finding := range findings {
+			if finding.Severity == SeverityError {
+				t.Errorf("write %v: got finding %v", failWrite, finding)
+			}
+		}
+
+		if got := getObjectSnapshot(t, g); !reflect.DeepEqual(got, want) {
+			t.Errorf("write %v: got objects %v, want %v", failWrite, got, want)
+		}
+	}
+}
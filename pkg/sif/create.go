@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
@@ -56,6 +56,10 @@ func writeDataObjectAt(ws io.WriteSeeker, offsetUnaligned int64, di DescriptorIn
 		return err
 	}
 
+	if err := di.verify(); err != nil {
+		return err
+	}
+
 	if err := di.fillDescriptor(t, d); err != nil {
 		return err
 	}
@@ -68,9 +72,17 @@ func writeDataObjectAt(ws io.WriteSeeker, offsetUnaligned int64, di DescriptorIn
 }
 
 // calculatedDataSize calculates the size of the data section based on the in-use descriptors.
+//
+// If f is staged within a transaction, the data section is considered to extend to at least the
+// end of the image when the transaction began, so that data objects added within the transaction
+// do not overwrite data referenced by the image prior to commit.
 func (f *FileImage) calculatedDataSize() int64 {
 	dataEnd := f.DataOffset()
 
+	if f.tx != nil {
+		dataEnd = max(dataEnd, f.tx.end)
+	}
+
 	f.WithDescriptors(func(d Descriptor) bool {
 		if objectEnd := d.Offset() + d.Size(); dataEnd < objectEnd {
 			dataEnd = objectEnd
@@ -81,32 +93,48 @@ func (f *FileImage) calculatedDataSize() int64 {
 	return dataEnd - f.DataOffset()
 }
 
+// calculatedArch returns the architecture of the first primary system partition in f, or
+// hdrArchUnknown if f contains no primary system partition.
+func (f *FileImage) calculatedArch() archType {
+	arch := hdrArchUnknown
+
+	f.WithDescriptors(func(d Descriptor) bool {
+		var p partition
+		if d.raw.isPartitionOfType(PartPrimSys) && d.raw.getExtra(binaryUnmarshaler{&p}) == nil {
+			arch = p.Arch
+			return true
+		}
+		return false
+	})
+
+	return arch
+}
+
 var (
 	errInsufficientCapacity = errors.New("insufficient descriptor capacity to add data object(s) to image")
-	errPrimaryPartition     = errors.New("image already contains a primary partition")
+	errPrimaryPartition     = errors.New("image already contains a primary partition for architecture")
 	errObjectIDOverflow     = errors.New("object ID would overflow")
 )
 
-// writeDataObject writes the data object described by di to f, using time t, recording details in
-// the descriptor at index i.
-func (f *FileImage) writeDataObject(i int, di DescriptorInput, t time.Time) error {
+// allocateDataObject prepares the descriptor at index i to describe the data object described by
+// di. On success, the descriptor is returned, along with the (unaligned) offset at which the data
+// object should be written.
+func (f *FileImage) allocateDataObject(i int, di DescriptorInput) (*rawDescriptor, int64, error) {
 	if i >= len(f.rds) {
-		return errInsufficientCapacity
+		return nil, 0, errInsufficientCapacity
 	}
 
 	// We derive the ID from i, so make sure the ID will not overflow.
 	if int64(i) >= math.MaxUint32 {
-		return errObjectIDOverflow
+		return nil, 0, errObjectIDOverflow
 	}
 
-	// If this is a primary partition, verify there isn't another primary partition, and update the
-	// architecture in the global header.
+	// If this is a primary partition, verify there isn't another primary partition for the same
+	// architecture.
 	if p, ok := di.opts.md.(partition); ok && p.Parttype == PartPrimSys {
-		if ds, err := f.GetDescriptors(WithPartitionType(PartPrimSys)); err == nil && len(ds) > 0 {
-			return errPrimaryPartition
+		if _, err := f.getDescriptor(withPrimaryPartitionForSIFArch(p.Arch)); !errors.Is(err, ErrObjectNotFound) {
+			return nil, 0, fmt.Errorf("%w %v", errPrimaryPartition, p.Arch.GoArch())
 		}
-
-		f.h.Arch = p.Arch
 	}
 
 	d := &f.rds[i]
@@ -114,10 +142,11 @@ func (f *FileImage) writeDataObject(i int, di DescriptorInput, t time.Time) erro
 
 	f.h.DataSize = f.calculatedDataSize()
 
-	if err := writeDataObjectAt(f.rw, f.h.DataOffset+f.h.DataSize, di, t, d); err != nil {
-		return err
-	}
+	return d, f.h.DataOffset + f.h.DataSize, nil
+}
 
+// recordDataObject updates f to account for the newly written data object described by d.
+func (f *FileImage) recordDataObject(d *rawDescriptor) {
 	// Update minimum object ID map.
 	if minID, ok := f.minIDs[d.GroupID]; !ok || d.ID < minID {
 		f.minIDs[d.GroupID] = d.ID
@@ -126,11 +155,36 @@ func (f *FileImage) writeDataObject(i int, di DescriptorInput, t time.Time) erro
 	f.h.DescriptorsFree--
 	f.h.DataSize += d.SizeWithPadding
 
+	// If this is a primary partition, update the architecture in the global header.
+	if d.isPartitionOfType(PartPrimSys) {
+		f.h.Arch = f.calculatedArch()
+	}
+}
+
+// writeDataObject writes the data object described by di to f, using time t, recording details in
+// the descriptor at index i.
+func (f *FileImage) writeDataObject(i int, di DescriptorInput, t time.Time) error {
+	d, offset, err := f.allocateDataObject(i, di)
+	if err != nil {
+		return err
+	}
+
+	if err := writeDataObjectAt(f.rw, offset, di, t, d); err != nil {
+		return err
+	}
+
+	f.recordDataObject(d)
+
 	return nil
 }
 
-// writeDescriptors writes the descriptors in f to backing storage.
+// writeDescriptors writes the descriptors in f to backing storage. If f is staged within a
+// transaction, this is deferred until the transaction is committed.
 func (f *FileImage) writeDescriptors() error {
+	if f.tx != nil {
+		return nil
+	}
+
 	if _, err := f.rw.Seek(f.h.DescriptorsOffset, io.SeekStart); err != nil {
 		return err
 	}
@@ -138,8 +192,13 @@ func (f *FileImage) writeDescriptors() error {
 	return binary.Write(f.rw, binary.LittleEndian, f.rds)
 }
 
-// writeHeader writes the global header in f to backing storage.
+// writeHeader writes the global header in f to backing storage. If f is staged within a
+// transaction, this is deferred until the transaction is committed.
 func (f *FileImage) writeHeader() error {
+	if f.tx != nil {
+		return nil
+	}
+
 	if _, err := f.rw.Seek(0, io.SeekStart); err != nil {
 		return err
 	}
@@ -233,8 +292,9 @@ func OptCreateWithCloseOnUnload(b bool) CreateOpt {
 
 var errDescriptorCapacityNotSupported = errors.New("descriptor capacity not supported")
 
-// createContainer creates a new SIF container file in rw, according to opts.
-func createContainer(rw ReadWriter, co createOpts) (*FileImage, error) {
+// newContainer returns a FileImage representing a new SIF container with no data objects, backed by
+// rw, according to co.
+func newContainer(rw ReadWriter, co createOpts) (*FileImage, error) {
 	// The supported number of descriptors is limited by the unsigned 32-bit ID field in each
 	// rawDescriptor.
 	if co.descriptorCapacity >= math.MaxUint32 {
@@ -266,6 +326,16 @@ func createContainer(rw ReadWriter, co createOpts) (*FileImage, error) {
 		minIDs: make(map[uint32]uint32),
 	}
 
+	return f, nil
+}
+
+// createContainer creates a new SIF container file in rw, according to opts.
+func createContainer(rw ReadWriter, co createOpts) (*FileImage, error) {
+	f, err := newContainer(rw, co)
+	if err != nil {
+		return nil, err
+	}
+
 	for i, di := range co.dis {
 		if err := f.writeDataObject(i, di, co.t); err != nil {
 			return nil, err
@@ -283,6 +353,30 @@ func createContainer(rw ReadWriter, co createOpts) (*FileImage, error) {
 	return f, nil
 }
 
+// getCreateOpts returns container creation options, configured according to opts.
+func getCreateOpts(opts ...CreateOpt) (createOpts, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return createOpts{}, err
+	}
+
+	co := createOpts{
+		id:                 id,
+		descriptorsOffset:  4096,
+		descriptorCapacity: 48,
+		t:                  time.Now(),
+		closeOnUnload:      true,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&co); err != nil {
+			return createOpts{}, err
+		}
+	}
+
+	return co, nil
+}
+
 // CreateContainer creates a new SIF container in rw, according to opts. One or more data objects
 // can optionally be specified using OptCreateWithDescriptors.
 //
@@ -301,23 +395,9 @@ func createContainer(rw ReadWriter, co createOpts) (*FileImage, error) {
 //
 // A launch script can optionally be set using OptCreateWithLaunchScript.
 func CreateContainer(rw ReadWriter, opts ...CreateOpt) (*FileImage, error) {
-	id, err := uuid.NewRandom()
+	co, err := getCreateOpts(opts...)
 	if err != nil {
-		return nil, err
-	}
-
-	co := createOpts{
-		id:                 id,
-		descriptorsOffset:  4096,
-		descriptorCapacity: 48,
-		t:                  time.Now(),
-		closeOnUnload:      true,
-	}
-
-	for _, opt := range opts {
-		if err := opt(&co); err != nil {
-			return nil, fmt.Errorf("%w", err)
-		}
+		return nil, fmt.Errorf("%w", err)
 	}
 
 	f, err := createContainer(rw, co)
@@ -65,6 +65,18 @@ func TestCreateContainer(t *testing.T) {
 			},
 			wantErr: errInsufficientCapacity,
 		},
+		{
+			name: "ErrOCIBlobDigestMismatch",
+			opts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataOCIBlob, []byte("{}"),
+						OptOCIBlobDigest(getOCIBlobDigest(t, []byte("[]"))),
+					),
+				),
+			},
+			wantErr: errOCIBlobDigestMismatch,
+		},
 		{
 			name: "Empty",
 			opts: []CreateOpt{
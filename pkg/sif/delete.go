@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
@@ -21,16 +21,21 @@ func (zeroReader) Read(b []byte) (int, error) {
 	return len(b), nil
 }
 
-// zero overwrites the data object described by d with a stream of zero bytes.
-func (f *FileImage) zero(d *rawDescriptor) error {
-	if _, err := f.rw.Seek(d.Offset, io.SeekStart); err != nil {
+// zeroRange overwrites the region of f between offsets start and end with zero bytes.
+func (f *FileImage) zeroRange(start, end int64) error {
+	if _, err := f.rw.Seek(start, io.SeekStart); err != nil {
 		return err
 	}
 
-	_, err := io.CopyN(f.rw, zeroReader{}, d.Size)
+	_, err := io.CopyN(f.rw, zeroReader{}, end-start)
 	return err
 }
 
+// zero overwrites the data object described by d with a stream of zero bytes.
+func (f *FileImage) zero(d *rawDescriptor) error {
+	return f.zeroRange(d.Offset, d.Offset+d.Size)
+}
+
 // deleteOpts accumulates object deletion options.
 type deleteOpts struct {
 	zero    bool
@@ -75,7 +80,8 @@ func OptDeleteWithTime(t time.Time) DeleteOpt {
 }
 
 // DeleteObject deletes the data object with id, according to opts. If no matching descriptor is
-// found, an error wrapping ErrObjectNotFound is returned.
+// found, an error wrapping ErrObjectNotFound is returned. Any annotations of the deleted object are
+// also deleted.
 //
 // To zero the data region of the deleted object, use OptDeleteZero. To remove unused space at the
 // end of the FileImage following object deletion, use OptDeleteCompact.
@@ -88,7 +94,8 @@ func (f *FileImage) DeleteObject(id uint32, opts ...DeleteOpt) error {
 }
 
 // DeleteObjects deletes the data objects selected by fn, according to opts. If no descriptors are
-// selected by fns, an error wrapping ErrObjectNotFound is returned.
+// selected by fn, an error wrapping ErrObjectNotFound is returned. Any annotations of the deleted
+// objects are also deleted.
 //
 // To zero the data region of the deleted object, use OptDeleteZero. To remove unused space at the
 // end of the FileImage following object deletion, use OptDeleteCompact.
@@ -97,6 +104,10 @@ func (f *FileImage) DeleteObject(id uint32, opts ...DeleteOpt) error {
 // and unset otherwise. To override this, consider using OptDeleteDeterministic or
 // OptDeleteWithTime.
 func (f *FileImage) DeleteObjects(fn DescriptorSelectorFunc, opts ...DeleteOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
 	do := deleteOpts{}
 
 	if !f.isDeterministic() {
@@ -109,41 +120,63 @@ func (f *FileImage) DeleteObjects(fn DescriptorSelectorFunc, opts ...DeleteOpt)
 		}
 	}
 
-	var selected bool
-
-	if err := f.withDescriptors(fn, func(d *rawDescriptor) error {
-		selected = true
-
+	remove := func(d *rawDescriptor) error {
 		if do.zero {
-			if err := f.zero(d); err != nil {
+			if f.tx != nil {
+				f.tx.zero = append(f.tx.zero, *d)
+			} else if err := f.zero(d); err != nil {
 				return fmt.Errorf("%w", err)
 			}
 		}
 
 		f.h.DescriptorsFree++
 
-		// If we remove the primary partition, set the global header Arch field to HdrArchUnknown
-		// to indicate that the SIF file doesn't include a primary partition and no dependency
-		// on any architecture exists.
-		if d.isPartitionOfType(PartPrimSys) {
-			f.h.Arch = hdrArchUnknown
-		}
+		isPrimary := d.isPartitionOfType(PartPrimSys)
 
 		// Reset rawDescripter with empty struct
 		*d = rawDescriptor{}
 
+		// If we remove a primary partition, set the global header Arch field to that of the
+		// remaining primary partition(s), or to HdrArchUnknown to indicate that the SIF file
+		// doesn't include a primary partition and no dependency on any architecture exists.
+		if isPrimary {
+			f.h.Arch = f.calculatedArch()
+		}
+
 		return nil
+	}
+
+	deleted := make(map[uint32]bool)
+
+	if err := f.withDescriptors(fn, func(d *rawDescriptor) error {
+		deleted[d.ID] = true
+		return remove(d)
 	}); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
-	if !selected {
+	if len(deleted) == 0 {
 		return fmt.Errorf("%w", ErrObjectNotFound)
 	}
 
+	// Object IDs are reused, so delete the annotations of deleted objects to prevent them being
+	// attached to objects added later.
+	if err := f.withDescriptors(func(d Descriptor) (bool, error) {
+		if !isAnnotations(d) {
+			return false, nil
+		}
+
+		id, _ := d.LinkedID()
+		return deleted[id], nil
+	}, remove); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
 	f.h.ModifiedAt = do.t.Unix()
 
-	if do.compact {
+	if do.compact && f.tx != nil {
+		f.tx.compact = true
+	} else if do.compact {
 		f.h.DataSize = f.calculatedDataSize()
 
 		if err := f.rw.Truncate(f.h.DataOffset + f.h.DataSize); err != nil {
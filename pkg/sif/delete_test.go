@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -168,6 +168,21 @@ func TestDeleteObject(t *testing.T) {
 			},
 			ids: []uint32{1},
 		},
+		{
+			name: "PrimaryPartitionMultiArch",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+					getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "amd64"),
+					),
+				),
+			},
+			ids: []uint32{1},
+		},
 	}
 
 	for _, tt := range tests {
@@ -350,3 +365,79 @@ func TestDeleteObjectAndAddObject(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteObjectAndAddObject_Annotations(t *testing.T) {
+	tests := []struct {
+		name   string
+		delete func(*FileImage, uint32) error
+	}{
+		{
+			name: "FileImage",
+			delete: func(f *FileImage, id uint32) error {
+				return f.DeleteObject(id)
+			},
+		},
+		{
+			name: "Tx",
+			delete: func(f *FileImage, id uint32) error {
+				tx, err := f.Begin()
+				if err != nil {
+					return err
+				}
+
+				if err := tx.DeleteObject(id); err != nil {
+					return errors.Join(err, tx.Rollback())
+				}
+
+				return tx.Commit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b,
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte("abc")),
+					getDescriptorInput(t, DataGeneric, []byte("def")),
+				),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := f.SetAnnotations(2, map[string]string{"k": "stale"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.delete(f, 2); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := f.DescriptorsFree(), f.DescriptorsTotal(); got != want-1 {
+				t.Errorf("got %v free descriptors, want %v", got, want-1)
+			}
+
+			if err := f.AddObject(getDescriptorInput(t, DataGeneric, []byte("ghi"))); err != nil {
+				t.Fatal(err)
+			}
+
+			d, err := f.GetDescriptor(WithID(2))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			annotations, err := d.Annotations()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := annotations; len(got) != 0 {
+				t.Errorf("got annotations %v, want none", got)
+			}
+		})
+	}
+}
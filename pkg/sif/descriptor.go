@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
@@ -48,6 +48,8 @@ type partition struct {
 	Fstype   FSType
 	Parttype PartType
 	Arch     archType
+	Variant  [partVariantLen]byte
+	OS       [partOSLen]byte
 }
 
 // MarshalBinary encodes p into binary format.
@@ -90,6 +92,20 @@ func newOCIBlobDigest() *ociBlob {
 	}
 }
 
+var errOCIBlobDigestMismatch = errors.New("OCI blob digest mismatch")
+
+// verify checks that the digest of ob, if set, matches the accumulated hash.
+func (ob *ociBlob) verify() error {
+	if ob.hasher == nil || ob.digest.Hex == "" {
+		return nil
+	}
+
+	if got, want := hex.EncodeToString(ob.hasher.Sum(nil)), ob.digest.Hex; got != want {
+		return fmt.Errorf("%w: got sha256:%v, want sha256:%v", errOCIBlobDigestMismatch, got, want)
+	}
+	return nil
+}
+
 // MarshalBinary encodes ob into binary format.
 func (ob *ociBlob) MarshalBinary() ([]byte, error) {
 	if ob.digest.Hex == "" {
@@ -172,6 +188,25 @@ func (d *rawDescriptor) getExtra(md encoding.BinaryUnmarshaler) error {
 	return md.UnmarshalBinary(d.Extra[:])
 }
 
+// getPartitionPlatform gets the platform of a partition data object.
+func (d rawDescriptor) getPartitionPlatform() (v1.Platform, error) {
+	if got, want := d.DataType, DataPartition; got != want {
+		return v1.Platform{}, &unexpectedDataTypeError{got, []DataType{want}}
+	}
+
+	var p partition
+
+	if err := d.getExtra(binaryUnmarshaler{&p}); err != nil {
+		return v1.Platform{}, err
+	}
+
+	return v1.Platform{
+		Architecture: p.Arch.GoArch(),
+		OS:           string(bytes.TrimRight(p.OS[:], "\x00")),
+		Variant:      string(bytes.TrimRight(p.Variant[:], "\x00")),
+	}, nil
+}
+
 // getPartitionMetadata gets metadata for a partition data object.
 func (d rawDescriptor) getPartitionMetadata() (FSType, PartType, string, error) {
 	if got, want := d.DataType, DataPartition; got != want {
@@ -200,6 +235,8 @@ func (d rawDescriptor) isPartitionOfType(pt PartType) bool {
 type Descriptor struct {
 	r io.ReaderAt // Backing storage.
 
+	f *FileImage // Image containing the data object.
+
 	raw rawDescriptor // Raw descriptor from image.
 
 	relativeID uint32 // ID relative to minimum ID of object group.
@@ -258,6 +295,17 @@ func (d Descriptor) PartitionMetadata() (fs FSType, pt PartType, arch string, er
 	return fs, pt, arch, err
 }
 
+// PartitionPlatform gets the platform of a partition data object. The architecture is represented
+// as by the Go runtime. The OS and variant are empty if they were not recorded when the data object
+// was added.
+func (d Descriptor) PartitionPlatform() (v1.Platform, error) {
+	p, err := d.raw.getPartitionPlatform()
+	if err != nil {
+		return v1.Platform{}, fmt.Errorf("%w", err)
+	}
+	return p, nil
+}
+
 var errHashUnsupported = errors.New("hash algorithm unsupported")
 
 // getHashType converts ht into a crypto.Hash.
@@ -351,6 +399,20 @@ func (d Descriptor) OCIBlobDigest() (v1.Hash, error) {
 	return o.digest, nil
 }
 
+// Annotations returns the annotations of the data object. If the data object has no annotations,
+// an empty map is returned. See FileImage.SetAnnotations for details.
+func (d Descriptor) Annotations() (map[string]string, error) {
+	if d.f == nil {
+		return map[string]string{}, nil
+	}
+
+	annotations, err := d.f.getAnnotations(d.raw.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return annotations, nil
+}
+
 // GetData returns the data object associated with descriptor d.
 func (d Descriptor) GetData() ([]byte, error) {
 	b := make([]byte, d.raw.Size)
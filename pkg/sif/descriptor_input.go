@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -12,6 +12,8 @@ import (
 	"fmt"
 	"io"
 	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 )
 
 // descriptorOpts accumulates data object options.
@@ -22,6 +24,7 @@ type descriptorOpts struct {
 	name      string
 	md        encoding.BinaryMarshaler
 	t         time.Time
+	size      int64
 }
 
 // DescriptorInputOpt are used to specify data object options.
@@ -93,6 +96,22 @@ func OptObjectTime(t time.Time) DescriptorInputOpt {
 	}
 }
 
+var errInvalidObjectSize = errors.New("invalid object size")
+
+// OptObjectSize specifies n as the size of the data object. The size of a data object must be
+// known in advance when writing an image using WriteContainer. If this option is not specified,
+// the size is determined from the data object reader where possible.
+func OptObjectSize(n int64) DescriptorInputOpt {
+	return func(_ DataType, opts *descriptorOpts) error {
+		if n < 0 {
+			return errInvalidObjectSize
+		}
+
+		opts.size = n
+		return nil
+	}
+}
+
 // OptMetadata marshals metadata from md into the "extra" field of d.
 func OptMetadata(md encoding.BinaryMarshaler) DescriptorInputOpt {
 	return func(_ DataType, opts *descriptorOpts) error {
@@ -197,6 +216,72 @@ func OptPartitionMetadata(fs FSType, pt PartType, arch string) DescriptorInputOp
 	}
 }
 
+var errPlatformValueTooLong = errors.New("platform value too long")
+
+// OptPartitionPlatformMetadata sets metadata for a partition data object. The filesystem type is
+// set to fs, the partition type is set to pt, and the CPU architecture, architecture variant and
+// OS are set according to p. The architecture should be represented as by the Go runtime, and the
+// variant and OS should follow the conventions of the OCI image specification (for example,
+// "linux/amd64/v3" or "linux/arm/v7"). Other fields of p are ignored.
+//
+// If this option is applied to a data object with an incompatible type, an error is returned.
+func OptPartitionPlatformMetadata(fs FSType, pt PartType, p v1.Platform) DescriptorInputOpt {
+	return func(t DataType, opts *descriptorOpts) error {
+		if got, want := t, DataPartition; got != want {
+			return &unexpectedDataTypeError{got, []DataType{want}}
+		}
+
+		sifarch := getSIFArch(p.Architecture)
+		if sifarch == hdrArchUnknown {
+			return fmt.Errorf("%w: %v", errUnknownArchitcture, p.Architecture)
+		}
+
+		part := partition{
+			Fstype:   fs,
+			Parttype: pt,
+			Arch:     sifarch,
+		}
+
+		if len(p.Variant) > len(part.Variant) {
+			return fmt.Errorf("%w: variant %q", errPlatformValueTooLong, p.Variant)
+		}
+		copy(part.Variant[:], p.Variant)
+
+		if len(p.OS) > len(part.OS) {
+			return fmt.Errorf("%w: OS %q", errPlatformValueTooLong, p.OS)
+		}
+		copy(part.OS[:], p.OS)
+
+		opts.md = part
+		return nil
+	}
+}
+
+var errUnsupportedDigestAlgorithm = errors.New("unsupported digest algorithm")
+
+// OptOCIBlobDigest specifies h as the digest of an OCI blob data object. The digest of an OCI
+// blob must be known in advance when writing an image using WriteContainer. If this option is not
+// specified, the digest is calculated as the data object is written. If the digest does not match
+// the data object, an error is returned when the data object is written.
+//
+// If this option is applied to a data object with an incompatible type, an error is returned.
+func OptOCIBlobDigest(h v1.Hash) DescriptorInputOpt {
+	return func(t DataType, opts *descriptorOpts) error {
+		if t != DataOCIRootIndex && t != DataOCIBlob {
+			return &unexpectedDataTypeError{t, []DataType{DataOCIRootIndex, DataOCIBlob}}
+		}
+
+		if h.Algorithm != "sha256" {
+			return fmt.Errorf("%w: %v", errUnsupportedDigestAlgorithm, h.Algorithm)
+		}
+
+		if ob, ok := opts.md.(*ociBlob); ok {
+			ob.digest = h
+		}
+		return nil
+	}
+}
+
 // sifHashType converts h into a HashType.
 func sifHashType(h crypto.Hash) hashType {
 	switch h {
@@ -256,6 +341,7 @@ func OptSBOMMetadata(f SBOMFormat) DescriptorInputOpt {
 type DescriptorInput struct {
 	dt   DataType
 	r    io.Reader
+	src  io.Reader // Data object reader, as supplied to NewDescriptorInput.
 	opts descriptorOpts
 }
 
@@ -286,8 +372,11 @@ const DefaultObjectGroup = 1
 func NewDescriptorInput(t DataType, r io.Reader, opts ...DescriptorInputOpt) (DescriptorInput, error) {
 	dopts := descriptorOpts{
 		groupID: DefaultObjectGroup,
+		size:    -1,
 	}
 
+	src := r
+
 	if t == DataPartition {
 		dopts.alignment = 4096
 	}
@@ -310,6 +399,7 @@ func NewDescriptorInput(t DataType, r io.Reader, opts ...DescriptorInputOpt) (De
 	di := DescriptorInput{
 		dt:   t,
 		r:    r,
+		src:  src,
 		opts: dopts,
 	}
 
@@ -337,3 +427,46 @@ func (di DescriptorInput) fillDescriptor(t time.Time, d *rawDescriptor) error {
 
 	return d.setExtra(di.opts.md)
 }
+
+var errObjectSizeUnknown = errors.New("object size unknown")
+
+// size returns the size of the data object described by di. If the size was not specified using
+// OptObjectSize, it is determined from the data object reader if it implements a Len method (such
+// as *bytes.Reader), or io.Seeker (such as *os.File).
+func (di DescriptorInput) size() (int64, error) {
+	if di.opts.size >= 0 {
+		return di.opts.size, nil
+	}
+
+	switch r := di.src.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len()), nil
+
+	case io.Seeker:
+		cur, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errObjectSizeUnknown, err)
+		}
+
+		end, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", errObjectSizeUnknown, err)
+		}
+
+		if _, err := r.Seek(cur, io.SeekStart); err != nil {
+			return 0, err
+		}
+
+		return end - cur, nil
+	}
+
+	return 0, errObjectSizeUnknown
+}
+
+// verify checks the data object described by di, once it has been completely read.
+func (di DescriptorInput) verify() error {
+	if ob, ok := di.opts.md.(*ociBlob); ok {
+		return ob.verify()
+	}
+	return nil
+}
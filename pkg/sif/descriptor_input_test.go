@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -10,9 +10,11 @@ import (
 	"crypto"
 	"encoding/binary"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/sebdah/goldie/v2"
 )
 
@@ -155,6 +157,55 @@ func TestNewDescriptorInput(t *testing.T) {
 				OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
 			},
 		},
+		{
+			name: "OptPartitionPlatformMetadataUnexpectedDataType",
+			t:    DataGeneric,
+			opts: []DescriptorInputOpt{
+				OptPartitionPlatformMetadata(FsSquash, PartPrimSys, v1.Platform{Architecture: "amd64"}),
+			},
+			wantErr: &unexpectedDataTypeError{DataGeneric, []DataType{DataPartition}},
+		},
+		{
+			name: "OptPartitionPlatformMetadataUnknownArchitecture",
+			t:    DataPartition,
+			opts: []DescriptorInputOpt{
+				OptPartitionPlatformMetadata(FsSquash, PartPrimSys, v1.Platform{Architecture: "cray"}),
+			},
+			wantErr: errUnknownArchitcture,
+		},
+		{
+			name: "OptPartitionPlatformMetadataVariantTooLong",
+			t:    DataPartition,
+			opts: []DescriptorInputOpt{
+				OptPartitionPlatformMetadata(FsSquash, PartPrimSys, v1.Platform{
+					Architecture: "amd64",
+					Variant:      strings.Repeat("v", 33),
+				}),
+			},
+			wantErr: errPlatformValueTooLong,
+		},
+		{
+			name: "OptPartitionPlatformMetadataOSTooLong",
+			t:    DataPartition,
+			opts: []DescriptorInputOpt{
+				OptPartitionPlatformMetadata(FsSquash, PartPrimSys, v1.Platform{
+					Architecture: "amd64",
+					OS:           strings.Repeat("o", 33),
+				}),
+			},
+			wantErr: errPlatformValueTooLong,
+		},
+		{
+			name: "OptPartitionPlatformMetadata",
+			t:    DataPartition,
+			opts: []DescriptorInputOpt{
+				OptPartitionPlatformMetadata(FsSquash, PartPrimSys, v1.Platform{
+					Architecture: "amd64",
+					OS:           "linux",
+					Variant:      "v3",
+				}),
+			},
+		},
 		{
 			name: "OptSignatureMetadataUnexpectedDataType",
 			t:    DataGeneric,
@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -221,6 +221,70 @@ func TestDescriptor_PartitionMetadata(t *testing.T) {
 	}
 }
 
+func TestDescriptor_PartitionPlatform(t *testing.T) {
+	p := partition{
+		Fstype:   FsSquash,
+		Parttype: PartPrimSys,
+		Arch:     hdrArchAMD64,
+	}
+	copy(p.OS[:], "linux")
+	copy(p.Variant[:], "v3")
+
+	rd := rawDescriptor{
+		DataType: DataPartition,
+	}
+	if err := rd.setExtra(p); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy := rawDescriptor{
+		DataType: DataPartition,
+	}
+	if err := legacy.setExtra(partition{Fstype: FsSquash, Parttype: PartPrimSys, Arch: hdrArch386}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		rd           rawDescriptor
+		wantPlatform v1.Platform
+		wantErr      error
+	}{
+		{
+			name: "UnexpectedDataType",
+			rd: rawDescriptor{
+				DataType: DataGeneric,
+			},
+			wantErr: &unexpectedDataTypeError{DataGeneric, []DataType{DataPartition}},
+		},
+		{
+			name:         "Legacy",
+			rd:           legacy,
+			wantPlatform: v1.Platform{Architecture: "386"},
+		},
+		{
+			name:         "Platform",
+			rd:           rd,
+			wantPlatform: v1.Platform{Architecture: "amd64", OS: "linux", Variant: "v3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Descriptor{raw: tt.rd}
+
+			p, err := d.PartitionPlatform()
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := p, tt.wantPlatform; !reflect.DeepEqual(got, want) {
+				t.Errorf("got platform %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
 func TestDescriptor_SignatureMetadata(t *testing.T) {
 	tests := []struct {
 		name    string
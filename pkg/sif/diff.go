@@ -0,0 +1,350 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// ChangeType describes how a data object differs between two images.
+type ChangeType int
+
+// List of change types.
+const (
+	ChangeAdded    ChangeType = iota + 1 // object present only in second image
+	ChangeRemoved                        // object present only in first image
+	ChangeModified                       // object name, size, metadata or content differs
+	ChangeMoved                          // object ID, group, link or offset differs
+)
+
+// String returns a human-readable representation of t.
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	case ChangeMoved:
+		return "moved"
+	}
+	return "unknown"
+}
+
+// FieldDifference describes a field with differing values in two images.
+type FieldDifference struct {
+	Name string // Name of field.
+	A    string // Value in first image.
+	B    string // Value in second image.
+}
+
+// ObjectDifference describes a data object that differs between two images.
+type ObjectDifference struct {
+	Type   ChangeType        // How the object differs.
+	A      *Descriptor       // Object in first image, or nil if added.
+	B      *Descriptor       // Object in second image, or nil if removed.
+	Fields []FieldDifference // Fields that differ, if modified or moved.
+}
+
+// Differences describes the differences between two images.
+type Differences struct {
+	Header  []FieldDifference  // Global header fields that differ.
+	Objects []ObjectDifference // Data objects that differ.
+}
+
+// describeMetadata returns a human-readable representation of the metadata of d.
+func describeMetadata(d Descriptor) string {
+	switch d.DataType() {
+	case DataPartition:
+		fs, pt, _, err := d.PartitionMetadata()
+		p, perr := d.PartitionPlatform()
+		if err == nil && perr == nil {
+			return fmt.Sprintf("%v/%v/%v", fs, pt, describePlatform(p))
+		}
+
+	case DataSignature:
+		if ht, fp, err := d.SignatureMetadata(); err == nil {
+			return fmt.Sprintf("%v/%X", ht, fp)
+		}
+
+	case DataCryptoMessage:
+		if ft, mt, err := d.CryptoMessageMetadata(); err == nil {
+			return fmt.Sprintf("%v/%v", ft, mt)
+		}
+
+	case DataSBOM:
+		if f, err := d.SBOMMetadata(); err == nil {
+			return f.String()
+		}
+
+	case DataOCIRootIndex, DataOCIBlob:
+		if h, err := d.OCIBlobDigest(); err == nil {
+			return h.String()
+		}
+	}
+
+	return fmt.Sprintf("%x", bytes.TrimRight(d.raw.Extra[:], "\x00"))
+}
+
+// describePlatform returns a human-readable representation of p, in the form
+// "[os/]architecture[/variant]".
+func describePlatform(p v1.Platform) string {
+	s := p.Architecture
+
+	if p.OS != "" {
+		s = p.OS + "/" + s
+	}
+
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+
+	return s
+}
+
+// describeLink returns a human-readable representation of the link of d.
+func describeLink(d Descriptor) string {
+	switch id, isGroup := d.LinkedID(); {
+	case id == 0:
+		return "none"
+	case isGroup:
+		return fmt.Sprintf("group %d", id)
+	default:
+		return fmt.Sprintf("object %d", id)
+	}
+}
+
+// diffObject describes an object, along with the digest of its content.
+type diffObject struct {
+	d       Descriptor
+	digest  v1.Hash
+	matched bool
+}
+
+// getDiffObjects returns the objects in f, along with the digest of their content.
+func getDiffObjects(f *FileImage) ([]*diffObject, error) {
+	var objs []*diffObject
+
+	err := f.withDescriptors(func(Descriptor) (bool, error) { return true, nil }, func(rd *rawDescriptor) error {
+		d := f.descriptorFromRaw(rd)
+
+		h, _, err := v1.SHA256(d.GetReader())
+		if err != nil {
+			return err
+		}
+
+		objs = append(objs, &diffObject{d: d, digest: h})
+		return nil
+	})
+
+	return objs, err
+}
+
+// identity returns a string that identifies the role of d within an image, independent of its
+// content.
+func identity(d Descriptor) string {
+	switch d.DataType() {
+	case DataPartition:
+		if _, pt, arch, err := d.PartitionMetadata(); err == nil {
+			return fmt.Sprintf("%v/%v", pt, arch)
+		}
+
+	case DataCryptoMessage:
+		if ft, mt, err := d.CryptoMessageMetadata(); err == nil {
+			return fmt.Sprintf("%v/%v", ft, mt)
+		}
+
+	case DataSBOM:
+		if f, err := d.SBOMMetadata(); err == nil {
+			return f.String()
+		}
+	}
+	return ""
+}
+
+// matchKey is used to match objects between images. Fields not relevant to a particular match are
+// left unset.
+type matchKey struct {
+	dt       DataType
+	name     string
+	groupID  uint32
+	identity string
+	digest   v1.Hash
+}
+
+// matchKeys are functions that return keys used to match objects between images, in order of
+// preference.
+var matchKeys = []func(o *diffObject) matchKey{
+	func(o *diffObject) matchKey {
+		return matchKey{dt: o.d.DataType(), name: o.d.Name(), groupID: o.d.GroupID(), digest: o.digest}
+	},
+	func(o *diffObject) matchKey {
+		return matchKey{dt: o.d.DataType(), digest: o.digest}
+	},
+	func(o *diffObject) matchKey {
+		return matchKey{dt: o.d.DataType(), name: o.d.Name(), groupID: o.d.GroupID(), identity: identity(o.d)}
+	},
+	func(o *diffObject) matchKey {
+		return matchKey{dt: o.d.DataType(), name: o.d.Name(), groupID: o.d.GroupID()}
+	},
+}
+
+// formatID returns the decimal representation of id.
+func formatID(id uint32) string { return strconv.FormatUint(uint64(id), 10) }
+
+// diffFields returns the fields that differ between objects a and b, and the corresponding change
+// type. If the objects do not differ, the returned change type is zero.
+func diffFields(a, b *diffObject) (ChangeType, []FieldDifference) {
+	var modified, moved []FieldDifference
+
+	if an, bn := a.d.Name(), b.d.Name(); an != bn {
+		modified = append(modified, FieldDifference{"name", an, bn})
+	}
+
+	if as, bs := a.d.Size(), b.d.Size(); as != bs {
+		modified = append(modified, FieldDifference{"size", strconv.FormatInt(as, 10), strconv.FormatInt(bs, 10)})
+	}
+
+	if a.d.raw.Extra != b.d.raw.Extra {
+		modified = append(modified, FieldDifference{"metadata", describeMetadata(a.d), describeMetadata(b.d)})
+	}
+
+	if a.digest != b.digest {
+		modified = append(modified, FieldDifference{"content", a.digest.String(), b.digest.String()})
+	}
+
+	if len(modified) > 0 {
+		return ChangeModified, modified
+	}
+
+	if aid, bid := a.d.ID(), b.d.ID(); aid != bid {
+		moved = append(moved, FieldDifference{"id", formatID(aid), formatID(bid)})
+	}
+
+	if ag, bg := a.d.GroupID(), b.d.GroupID(); ag != bg {
+		moved = append(moved, FieldDifference{"group", formatID(ag), formatID(bg)})
+	}
+
+	if al, bl := describeLink(a.d), describeLink(b.d); al != bl {
+		moved = append(moved, FieldDifference{"link", al, bl})
+	}
+
+	if ao, bo := a.d.Offset(), b.d.Offset(); ao != bo {
+		moved = append(moved, FieldDifference{"offset", strconv.FormatInt(ao, 10), strconv.FormatInt(bo, 10)})
+	}
+
+	if len(moved) > 0 {
+		return ChangeMoved, moved
+	}
+
+	return 0, nil
+}
+
+// diffHeader returns the global header fields that differ between a and b.
+func diffHeader(a, b *FileImage) []FieldDifference {
+	fields := []struct {
+		name string
+		fn   func(*FileImage) string
+	}{
+		{"launch script", func(f *FileImage) string { return f.LaunchScript() }},
+		{"version", func(f *FileImage) string { return f.Version() }},
+		{"primary arch", func(f *FileImage) string { return f.PrimaryArch() }},
+		{"id", func(f *FileImage) string { return f.ID() }},
+		{"created at", func(f *FileImage) string { return f.CreatedAt().UTC().String() }},
+		{"modified at", func(f *FileImage) string { return f.ModifiedAt().UTC().String() }},
+		{"descriptors free", func(f *FileImage) string { return strconv.FormatInt(f.DescriptorsFree(), 10) }},
+		{"descriptors total", func(f *FileImage) string { return strconv.FormatInt(f.DescriptorsTotal(), 10) }},
+		{"descriptors offset", func(f *FileImage) string { return strconv.FormatInt(f.DescriptorsOffset(), 10) }},
+		{"descriptors size", func(f *FileImage) string { return strconv.FormatInt(f.DescriptorsSize(), 10) }},
+		{"data offset", func(f *FileImage) string { return strconv.FormatInt(f.DataOffset(), 10) }},
+		{"data size", func(f *FileImage) string { return strconv.FormatInt(f.DataSize(), 10) }},
+	}
+
+	var diffs []FieldDifference
+	for _, field := range fields {
+		if av, bv := field.fn(a), field.fn(b); av != bv {
+			diffs = append(diffs, FieldDifference{field.name, av, bv})
+		}
+	}
+	return diffs
+}
+
+// Diff returns the differences between images a and b.
+//
+// Each data object in a is matched with at most one data object in b of the same data type. In
+// order of preference, objects are matched by name, group and content digest; by content digest
+// alone; by name, group and role (such as partition type and architecture); and by name and group
+// alone. Matched objects with differing names, sizes, metadata or content are reported as
+// modified. Otherwise, matched objects with differing IDs, groups, links or offsets are reported
+// as moved. Unmatched objects are reported as removed from a, or added to b.
+//
+// Objects that do not differ are not reported. Data object creation and modification times are
+// not compared.
+func Diff(a, b *FileImage) (*Differences, error) {
+	aObjs, err := getDiffObjects(a)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	bObjs, err := getDiffObjects(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	pairs := make(map[*diffObject]*diffObject)
+
+	for _, key := range matchKeys {
+		candidates := make(map[matchKey][]*diffObject)
+		for _, o := range bObjs {
+			if !o.matched {
+				k := key(o)
+				candidates[k] = append(candidates[k], o)
+			}
+		}
+
+		for _, o := range aObjs {
+			if o.matched {
+				continue
+			}
+
+			k := key(o)
+			if cs := candidates[k]; len(cs) > 0 {
+				pairs[o] = cs[0]
+				candidates[k] = cs[1:]
+				o.matched, cs[0].matched = true, true
+			}
+		}
+	}
+
+	diffs := Differences{
+		Header: diffHeader(a, b),
+	}
+
+	for _, o := range aObjs {
+		p, ok := pairs[o]
+		if !ok {
+			diffs.Objects = append(diffs.Objects, ObjectDifference{Type: ChangeRemoved, A: &o.d})
+			continue
+		}
+
+		if t, fields := diffFields(o, p); t != 0 {
+			diffs.Objects = append(diffs.Objects, ObjectDifference{Type: t, A: &o.d, B: &p.d, Fields: fields})
+		}
+	}
+
+	for _, o := range bObjs {
+		if !o.matched {
+			diffs.Objects = append(diffs.Objects, ObjectDifference{Type: ChangeAdded, B: &o.d})
+		}
+	}
+
+	return &diffs, nil
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sebdah/goldie/v2"
+)
+
+// writeDifferences writes a representation of diffs to w.
+func writeDifferences(w *bytes.Buffer, diffs *Differences) {
+	for _, fd := range diffs.Header {
+		fmt.Fprintf(w, "header %v: %q -> %q\n", fd.Name, fd.A, fd.B)
+	}
+
+	for _, od := range diffs.Objects {
+		fmt.Fprintf(w, "%v:", od.Type)
+
+		if od.A != nil {
+			fmt.Fprintf(w, " a=%d", od.A.ID())
+		}
+
+		if od.B != nil {
+			fmt.Fprintf(w, " b=%d", od.B.ID())
+		}
+
+		fmt.Fprintln(w)
+
+		for _, fd := range od.Fields {
+			fmt.Fprintf(w, "  %v: %q -> %q\n", fd.Name, fd.A, fd.B)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		aOpts []CreateOpt
+		bOpts []CreateOpt
+	}{
+		{
+			name: "Empty",
+		},
+		{
+			name: "Header",
+			aOpts: []CreateOpt{
+				OptCreateWithLaunchScript("#!/bin/sh\n"),
+			},
+		},
+		{
+			name: "Identical",
+			aOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataDeffile, []byte("bootstrap: docker")),
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+				),
+			},
+			bOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataDeffile, []byte("bootstrap: docker")),
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+				),
+			},
+		},
+		{
+			name: "AddedRemoved",
+			aOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}, OptObjectName("a")),
+				),
+			},
+			bOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}, OptObjectName("b")),
+				),
+			},
+		},
+		{
+			name: "Modified",
+			aOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataDeffile, []byte("bootstrap: docker")),
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+					getDescriptorInput(t, DataSBOM, []byte("{}"),
+						OptSBOMMetadata(SBOMFormatCycloneDXJSON),
+					),
+				),
+			},
+			bOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataDeffile, []byte("bootstrap: library")),
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce, 0xfe, 0xed},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+					getDescriptorInput(t, DataSBOM, []byte("{}"),
+						OptSBOMMetadata(SBOMFormatSPDXJSON),
+					),
+				),
+			},
+		},
+		{
+			name: "Platform",
+			aOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "amd64"),
+					),
+				),
+			},
+			bOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionPlatformMetadata(FsSquash, PartPrimSys, v1.Platform{
+							Architecture: "amd64",
+							OS:           "linux",
+							Variant:      "v3",
+						}),
+					),
+				),
+			},
+		},
+		{
+			name: "Moved",
+			aOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}, OptObjectName("a")),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}, OptObjectName("b")),
+				),
+			},
+			bOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}, OptObjectName("b")),
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}, OptObjectName("a")),
+				),
+			},
+		},
+		{
+			name: "Renamed",
+			aOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}, OptObjectName("a")),
+				),
+			},
+			bOpts: []CreateOpt{
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}, OptObjectName("b")),
+				),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := CreateContainer(&Buffer{}, append([]CreateOpt{OptCreateDeterministic()}, tt.aOpts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := CreateContainer(&Buffer{}, append([]CreateOpt{OptCreateDeterministic()}, tt.bOpts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			diffs, err := Diff(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			writeDifferences(&out, diffs)
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, out.Bytes())
+		})
+	}
+}
+
+func TestDiff_Corpus(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{
+			name: "OneGroupSigned",
+			a:    "one-group.sif",
+			b:    "one-group-signed-pgp.sif",
+		},
+		{
+			name: "TwoGroups",
+			a:    "one-group.sif",
+			b:    "two-groups.sif",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := LoadContainerFromPath(filepath.Join(corpus, tt.a), OptLoadWithFlag(os.O_RDONLY))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { a.UnloadContainer() })
+
+			b, err := LoadContainerFromPath(filepath.Join(corpus, tt.b), OptLoadWithFlag(os.O_RDONLY))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { b.UnloadContainer() })
+
+			diffs, err := Diff(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			writeDifferences(&out, diffs)
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, out.Bytes())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// growOpts accumulates descriptor growth options.
+type growOpts struct {
+	t time.Time
+}
+
+// GrowOpt are used to specify descriptor growth options.
+type GrowOpt func(*growOpts) error
+
+// OptGrowDeterministic sets header/descriptor fields to values that support deterministic
+// modification of images.
+func OptGrowDeterministic() GrowOpt {
+	return func(gro *growOpts) error {
+		gro.t = time.Time{}
+		return nil
+	}
+}
+
+// OptGrowWithTime specifies t as the image modification time.
+func OptGrowWithTime(t time.Time) GrowOpt {
+	return func(gro *growOpts) error {
+		gro.t = t
+		return nil
+	}
+}
+
+var (
+	errInvalidGrowth = errors.New("descriptor growth must be positive")
+	errGrowTx        = errors.New("descriptor growth requiring data relocation not supported within transaction")
+)
+
+// shiftData moves all data objects in f forward by delta bytes. Objects are moved in reverse
+// order of offset, so that each object is moved into space that is either unused, or was occupied
+// by the object itself.
+func (f *FileImage) shiftData(delta int64) error {
+	var rds []*rawDescriptor
+	for i := range f.rds {
+		if f.rds[i].Used {
+			rds = append(rds, &f.rds[i])
+		}
+	}
+
+	slices.SortStableFunc(rds, func(a, b *rawDescriptor) int {
+		return cmp.Compare(b.Offset, a.Offset)
+	})
+
+	for _, d := range rds {
+		if err := f.relocate(d, d.Offset+delta); err != nil {
+			return fmt.Errorf("failed to relocate object %v: %w", d.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// GrowDescriptors increases the descriptor capacity of f by n descriptors.
+//
+// If there is insufficient space between the descriptor section and the data section to
+// accommodate the additional descriptors, all data objects are moved towards the end of the image
+// to make room. Data objects retain their relative placement, and are moved by a multiple of the
+// largest alignment inferred from their offsets (up to a maximum of 64KiB), so object alignment is
+// preserved. Object IDs and all integrity-protected header and descriptor fields are preserved, so
+// existing signatures remain valid.
+//
+// Growth is crash-safe: data is always copied to unused space before the corresponding descriptor
+// is updated, the data section size is extended in the header before any descriptor references
+// data beyond its end, and the descriptor and data section offsets in the header are updated only
+// once the enlarged descriptor section has been written. If the backing storage implements a Sync
+// method (such as *os.File), it is used to commit data before descriptors and header are updated.
+//
+// By default, the image modification time is set to the current time for non-deterministic images,
+// and unset otherwise. To override this, consider using OptGrowDeterministic or OptGrowWithTime.
+func (f *FileImage) GrowDescriptors(n int64, opts ...GrowOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
+	gro := growOpts{}
+
+	if !f.isDeterministic() {
+		gro.t = time.Now()
+	}
+
+	for _, opt := range opts {
+		if err := opt(&gro); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	if n <= 0 {
+		return fmt.Errorf("%w", errInvalidGrowth)
+	}
+
+	// The supported number of descriptors is limited by the unsigned 32-bit ID field in each
+	// rawDescriptor.
+	if n >= math.MaxUint32-f.h.DescriptorsTotal {
+		return fmt.Errorf("%w", errDescriptorCapacityNotSupported)
+	}
+
+	rdsSize := (f.h.DescriptorsTotal + n) * int64(binary.Size(rawDescriptor{}))
+
+	// If the enlarged descriptor section overlaps the data section, shift the data section by a
+	// multiple of the largest inferred object alignment.
+	if delta := f.h.DescriptorsOffset + rdsSize - f.h.DataOffset; delta > 0 {
+		if f.tx != nil {
+			return fmt.Errorf("%w", errGrowTx)
+		}
+
+		alignment := 0
+		for _, rd := range f.rds {
+			if rd.Used {
+				alignment = max(alignment, rd.inferredAlignment())
+			}
+		}
+
+		delta, err := nextAligned(delta, alignment)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		// Shifting data objects may temporarily extend the data section, so restore its size once
+		// the data section offset has been updated.
+		size := f.h.DataSize
+
+		if err := f.shiftData(delta); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		// Remove any scratch space used while shifting data objects.
+		if err := f.rw.Truncate(f.h.DataOffset + f.calculatedDataSize()); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		f.h.DataOffset += delta
+		f.h.DataSize = size
+
+		// Zero the space between the enlarged descriptor section and the data section.
+		if err := f.zeroRange(f.h.DescriptorsOffset+rdsSize, f.h.DataOffset); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	f.rds = append(f.rds, make([]rawDescriptor, n)...)
+	f.h.DescriptorsFree += n
+	f.h.DescriptorsTotal += n
+	f.h.DescriptorsSize = rdsSize
+	f.h.ModifiedAt = gro.t.Unix()
+
+	if err := f.commitDescriptors(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := f.writeHeader(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := f.sync(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestFileImage_GrowDescriptors(t *testing.T) {
+	tests := []struct {
+		name       string
+		createOpts []CreateOpt
+		n          int64
+		opts       []GrowOpt
+		wantErr    error
+	}{
+		{
+			name: "ErrInvalidGrowth",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+			},
+			n:       0,
+			wantErr: errInvalidGrowth,
+		},
+		{
+			name: "ErrDescriptorCapacityNotSupported",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+			},
+			n:       4294967295,
+			wantErr: errDescriptorCapacityNotSupported,
+		},
+		{
+			name: "Empty",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+			},
+			n: 2,
+		},
+		{
+			name: "InPlace",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(1),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce},
+						OptObjectAlignment(4096),
+					),
+				),
+			},
+			n: 2,
+		},
+		{
+			name: "ShiftData",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(2),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			n: 2,
+		},
+		{
+			name: "ShiftDataOverlapping",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(2),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, make([]byte, 1024)),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			n: 1,
+		},
+		{
+			name: "ShiftDataAligned",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(2),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+				),
+			},
+			n: 2,
+		},
+		{
+			name: "Deterministic",
+			createOpts: []CreateOpt{
+				OptCreateWithID("de170c43-36ab-44a8-bca9-1ea1a070a274"),
+				OptCreateWithDescriptorCapacity(1),
+				OptCreateWithTime(time.Unix(946702800, 0)),
+			},
+			n: 1,
+			opts: []GrowOpt{
+				OptGrowDeterministic(),
+			},
+		},
+		{
+			name: "WithTime",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(1),
+			},
+			n: 1,
+			opts: []GrowOpt{
+				OptGrowWithTime(time.Unix(946702800, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b, tt.createOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := getObjectSnapshot(t, f)
+			wantTotal := f.DescriptorsTotal()
+
+			if got, want := f.GrowDescriptors(tt.n, tt.opts...), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if tt.wantErr == nil {
+				wantTotal += tt.n
+			}
+
+			if got := getObjectSnapshot(t, f); !reflect.DeepEqual(got, want) {
+				t.Errorf("got objects %v, want %v", got, want)
+			}
+
+			if got, want := f.DescriptorsTotal(), wantTotal; got != want {
+				t.Errorf("got descriptors total %v, want %v", got, want)
+			}
+
+			if err := f.UnloadContainer(); err != nil {
+				t.Error(err)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
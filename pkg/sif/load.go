@@ -163,6 +163,35 @@ func LoadContainer(rw ReadWriter, opts ...LoadOpt) (*FileImage, error) {
 	return f, nil
 }
 
+// ErrReadOnly is the error returned when an attempt is made to modify a read-only image.
+var ErrReadOnly = errors.New("image is read-only")
+
+// readOnlyReadWriter adapts an io.SectionReader to the ReadWriter interface. Writes and truncation
+// fail with ErrReadOnly.
+type readOnlyReadWriter struct {
+	*io.SectionReader
+}
+
+// Write returns ErrReadOnly.
+func (readOnlyReadWriter) Write([]byte) (int, error) { return 0, ErrReadOnly }
+
+// Truncate returns ErrReadOnly.
+func (readOnlyReadWriter) Truncate(int64) error { return ErrReadOnly }
+
+// LoadContainerReader loads a new read-only SIF container from the first size bytes of r.
+//
+// Methods that modify the returned FileImage return an error wrapping ErrReadOnly. Since r is not
+// owned by the returned FileImage, UnloadContainer does not close r.
+func LoadContainerReader(r io.ReaderAt, size int64) (*FileImage, error) {
+	f, err := loadContainer(readOnlyReadWriter{io.NewSectionReader(r, 0, size)})
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	f.readOnly = true
+	return f, nil
+}
+
 // UnloadContainer unloads f, releasing associated resources.
 func (f *FileImage) UnloadContainer() error {
 	if c, ok := f.rw.(io.Closer); ok && f.closeOnUnload {
@@ -6,6 +6,8 @@
 package sif
 
 import (
+	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -83,6 +85,102 @@ func TestLoadContainer(t *testing.T) {
 	}
 }
 
+func TestLoadContainerReader(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(corpus, "one-group-signed-pgp.sif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadContainerReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("failed to load container: %v", err)
+	}
+
+	d, err := f.GetDescriptor(WithID(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := d.GetData(); err != nil {
+		t.Fatal(err)
+	} else if want := b[d.Offset() : d.Offset()+d.Size()]; !bytes.Equal(got, want) {
+		t.Errorf("got data %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		fn   func() error
+	}{
+		{
+			name: "AddObject",
+			fn: func() error {
+				return f.AddObject(getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}))
+			},
+		},
+		{
+			name: "DeleteObject",
+			fn: func() error {
+				return f.DeleteObject(1)
+			},
+		},
+		{
+			name: "SetPrimPart",
+			fn: func() error {
+				return f.SetPrimPart(1)
+			},
+		},
+		{
+			name: "SetMetadata",
+			fn: func() error {
+				return f.SetMetadata(1, newOCIBlobDigest())
+			},
+		},
+		{
+			name: "SetOCIBlobDigest",
+			fn: func() error {
+				return f.SetOCIBlobDigest(1, getOCIBlobDigest(t, []byte("{}")))
+			},
+		},
+		{
+			name: "ReplaceObject",
+			fn: func() error {
+				_, err := f.ReplaceObject(1, bytes.NewReader([]byte{0xfa, 0xce}))
+				return err
+			},
+		},
+		{
+			name: "Compact",
+			fn: func() error {
+				return f.Compact()
+			},
+		},
+		{
+			name: "GrowDescriptors",
+			fn: func() error {
+				return f.GrowDescriptors(1)
+			},
+		},
+		{
+			name: "Begin",
+			fn: func() error {
+				_, err := f.Begin()
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := tt.fn(), ErrReadOnly; !errors.Is(got, want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+
+	if err := f.UnloadContainer(); err != nil {
+		t.Errorf("failed to unload container: %v", err)
+	}
+}
+
 func TestLoadContainerFpMock(t *testing.T) {
 	// This test is using mockSifReadWriter to verify that the code
 	// is not making assumptions regading the behavior of the
@@ -0,0 +1,302 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// repairOpts accumulates repair options.
+type repairOpts struct {
+	dryRun bool
+	t      time.Time
+}
+
+// RepairOpt are used to specify repair options.
+type RepairOpt func(*repairOpts) error
+
+// OptRepairDryRun specifies whether repairs should only be reported, rather than made.
+func OptRepairDryRun(b bool) RepairOpt {
+	return func(ro *repairOpts) error {
+		ro.dryRun = b
+		return nil
+	}
+}
+
+// OptRepairDeterministic sets header/descriptor fields to values that support deterministic
+// modification of images.
+func OptRepairDeterministic() RepairOpt {
+	return func(ro *repairOpts) error {
+		ro.t = time.Time{}
+		return nil
+	}
+}
+
+// OptRepairWithTime specifies t as the image/object modification time.
+func OptRepairWithTime(t time.Time) RepairOpt {
+	return func(ro *repairOpts) error {
+		ro.t = t
+		return nil
+	}
+}
+
+// Fix describes a repair made to an image.
+type Fix struct {
+	Type    FindingType // Invariant restored.
+	ID      uint32      // ID of the data object concerned, or zero if not specific to an object.
+	Message string      // Human-readable description.
+}
+
+// String returns a human-readable representation of x.
+func (x Fix) String() string {
+	if x.ID == 0 {
+		return x.Message
+	}
+	return fmt.Sprintf("object %d: %v", x.ID, x.Message)
+}
+
+// repairer accumulates repairs to an image, which are staged in s.
+type repairer struct {
+	s                *FileImage         // Staged image.
+	size             int64              // Size of image.
+	fixes            []Fix              // Repairs made.
+	headerDirty      bool               // Whether the staged header has been modified.
+	descriptorsDirty bool               // Whether the staged descriptors have been modified.
+	digests          map[uint32]v1.Hash // Replacement OCI blob digests, by object ID.
+}
+
+// addf records a repair of type t, concerning the object with the specified ID.
+func (r *repairer) addf(t FindingType, id uint32, format string, a ...any) {
+	r.fixes = append(r.fixes, Fix{
+		Type:    t,
+		ID:      id,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// clearOutOfBounds clears descriptors of objects that extend beyond the end of the image.
+func (r *repairer) clearOutOfBounds() {
+	for i, rd := range r.s.rds {
+		if !rd.Used {
+			continue
+		}
+
+		if rd.Offset >= 0 && rd.Size >= 0 && rd.Offset+rd.Size <= r.size {
+			continue
+		}
+
+		r.addf(FindingObjectBounds, rd.ID,
+			"cleared descriptor of object (offset %d, size %d) extending beyond end of image (size %d)",
+			rd.Offset, rd.Size, r.size)
+
+		r.s.rds[i] = rawDescriptor{}
+		r.descriptorsDirty = true
+	}
+}
+
+// recomputeDescriptorsFree sets the free descriptor count in the header to match the descriptors.
+func (r *repairer) recomputeDescriptorsFree() {
+	var free int64
+	for _, rd := range r.s.rds {
+		if !rd.Used {
+			free++
+		}
+	}
+
+	if h := &r.s.h; h.DescriptorsFree != free {
+		r.addf(FindingDescriptorsFree, 0, "set free descriptor count from %d to %d", h.DescriptorsFree, free)
+		h.DescriptorsFree = free
+		r.headerDirty = true
+	}
+}
+
+// resetArch sets the architecture in the header to that of the first primary partition, or to
+// unknown if there is no primary partition.
+func (r *repairer) resetArch() {
+	if want, h := r.s.calculatedArch(), &r.s.h; h.Arch != want {
+		r.addf(FindingPrimaryArch, 0, "set header architecture from %v to %v", h.Arch.GoArch(), want.GoArch())
+		h.Arch = want
+		r.headerDirty = true
+	}
+}
+
+// fixDataSize sets the data section size in the header so that the data section does not extend
+// beyond the end of the image, and is large enough to contain the objects. Unused space at the end
+// of the data section is otherwise retained.
+func (r *repairer) fixDataSize() {
+	h := &r.s.h
+
+	want := h.DataSize
+	if h.DataOffset+want > r.size {
+		want = r.size - h.DataOffset
+	}
+	want = max(want, r.s.calculatedDataSize())
+
+	if h.DataSize != want {
+		r.addf(FindingDataSection, 0, "set data section size from %d to %d", h.DataSize, want)
+		h.DataSize = want
+		r.headerDirty = true
+	}
+}
+
+// truncateTrailing records whether the image should be truncated to remove data following the data
+// section, and returns the size to truncate the image to, or -1 if no truncation is required. Data
+// referenced by the descriptor section or a descriptor is never truncated.
+func (r *repairer) truncateTrailing() int64 {
+	h := r.s.h
+
+	end := max(h.DataOffset+h.DataSize, h.DescriptorsOffset+h.DescriptorsSize)
+	for _, rd := range r.s.rds {
+		if rd.Used {
+			end = max(end, rd.Offset+rd.Size)
+		}
+	}
+
+	if r.size <= end {
+		return -1
+	}
+
+	r.addf(FindingDataSection, 0, "truncated %d bytes of trailing data", r.size-end)
+	return end
+}
+
+// repopulateDigests computes replacement digests for OCI blobs with digests that are invalid or
+// do not match their content. Only SHA-256 digests are supported.
+func (r *repairer) repopulateDigests() error {
+	return r.s.withDescriptors(func(d Descriptor) (bool, error) {
+		dt := d.DataType()
+		return dt == DataOCIRootIndex || dt == DataOCIBlob, nil
+	}, func(rd *rawDescriptor) error {
+		d := r.s.descriptorFromRaw(rd)
+
+		want, werr := d.OCIBlobDigest()
+		if werr == nil && want.Algorithm != "sha256" {
+			return nil
+		}
+
+		got, _, err := v1.SHA256(d.GetReader())
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case werr != nil:
+			r.addf(FindingOCIBlobDigest, d.ID(), "set invalid digest to %v", got)
+		case got != want:
+			r.addf(FindingOCIBlobDigest, d.ID(), "set digest from %v to %v", want, got)
+		default:
+			return nil
+		}
+
+		r.digests[d.ID()] = got
+		return nil
+	})
+}
+
+// Repair repairs recoverable violations of the structural invariants of the SIF format in f,
+// according to opts. The following repairs are made, in order:
+//
+//   - Descriptors of objects extending beyond the end of the image are cleared.
+//   - The free descriptor count in the global header is recomputed.
+//   - The architecture in the global header is reset to that of the first primary partition, or
+//     to unknown if there is no primary partition.
+//   - The data section size in the global header is reduced, if the data section extends beyond
+//     the end of the image, and increased, if it is smaller than required by the objects.
+//   - Data following the data section that is not referenced by any descriptor is truncated.
+//   - OCI blob digests that are invalid or do not match the content are recomputed, and updated
+//     using SetOCIBlobDigest.
+//
+// Each repair made is returned as a Fix. Violations that cannot be repaired, such as overlapping
+// objects, are left unchanged. Use Check to identify these. Unused space within the data section
+// is not a violation, and is retained; to reclaim it, consider using Compact.
+//
+// To report repairs without making them, use OptRepairDryRun.
+//
+// By default, the image/object modification times are set to the current time for
+// non-deterministic images, and unset otherwise. To override this, consider using
+// OptRepairDeterministic or OptRepairWithTime.
+func Repair(f *FileImage, opts ...RepairOpt) ([]Fix, error) {
+	ro := repairOpts{}
+
+	if !f.isDeterministic() {
+		ro.t = time.Now()
+	}
+
+	for _, opt := range opts {
+		if err := opt(&ro); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	if f.readOnly && !ro.dryRun {
+		return nil, fmt.Errorf("%w", ErrReadOnly)
+	}
+
+	size, err := f.rw.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	r := repairer{
+		s: &FileImage{
+			rw:  f.rw,
+			h:   f.h,
+			rds: slices.Clone(f.rds),
+		},
+		size:    size,
+		digests: make(map[uint32]v1.Hash),
+	}
+
+	r.clearOutOfBounds()
+	r.recomputeDescriptorsFree()
+	r.resetArch()
+	r.fixDataSize()
+	end := r.truncateTrailing()
+
+	if err := r.repopulateDigests(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if ro.dryRun || len(r.fixes) == 0 {
+		return r.fixes, nil
+	}
+
+	if r.headerDirty || r.descriptorsDirty {
+		f.h = r.s.h
+		f.h.ModifiedAt = ro.t.Unix()
+		f.rds = r.s.rds
+		f.populateMinIDs()
+
+		if err := f.writeDescriptors(); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if err := f.writeHeader(); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	if end >= 0 {
+		if err := f.rw.Truncate(end); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	for _, x := range r.fixes {
+		if h, ok := r.digests[x.ID]; ok && x.Type == FindingOCIBlobDigest {
+			if err := f.SetOCIBlobDigest(x.ID, h, OptSetWithTime(ro.t)); err != nil {
+				return nil, fmt.Errorf("%w", err)
+			}
+		}
+	}
+
+	return r.fixes, nil
+}
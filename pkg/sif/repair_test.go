@@ -0,0 +1,246 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestRepair_Corpus(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(corpus, "*.sif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := LoadContainerFromPath(path, OptLoadWithFlag(os.O_RDONLY))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { f.UnloadContainer() })
+
+			fixes, err := Repair(f, OptRepairDryRun(true))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(fixes) > 0 {
+				t.Errorf("unexpected fixes: %v", fixes)
+			}
+		})
+	}
+}
+
+func TestRepair_ReadOnly(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(corpus, "one-group.sif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := LoadContainerReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Repair(r); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("got error %v, want %v", err, ErrReadOnly)
+	}
+
+	if _, err := Repair(r, OptRepairDryRun(true)); err != nil {
+		t.Errorf("got error %v, want nil", err)
+	}
+}
+
+func TestRepair(t *testing.T) {
+	tests := []struct {
+		name    string
+		dis     []DescriptorInput
+		corrupt func(*FileImage, *Buffer)
+		dryRun  bool
+	}{
+		{
+			name: "OK",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+			},
+		},
+		{
+			name: "Truncated",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+			},
+			corrupt: func(_ *FileImage, b *Buffer) {
+				if err := b.Truncate(b.Len() - 1); err != nil {
+					panic(err)
+				}
+			},
+		},
+		{
+			name: "TruncatedDryRun",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+			},
+			corrupt: func(_ *FileImage, b *Buffer) {
+				if err := b.Truncate(b.Len() - 1); err != nil {
+					panic(err)
+				}
+			},
+			dryRun: true,
+		},
+		{
+			name: "UnusedDataSpace",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				if err := f.DeleteObject(2, OptDeleteDeterministic()); err != nil {
+					panic(err)
+				}
+			},
+		},
+		{
+			name: "TrailingData",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(_ *FileImage, b *Buffer) {
+				if _, err := b.Seek(0, io.SeekEnd); err != nil {
+					panic(err)
+				}
+
+				if _, err := b.Write([]byte("garbage")); err != nil {
+					panic(err)
+				}
+			},
+		},
+		{
+			name: "DataSize",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.DataSize--
+			},
+		},
+		{
+			name: "DescriptorsFree",
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.DescriptorsFree--
+			},
+		},
+		{
+			name: "PrimaryArchMismatch",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				),
+			},
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.Arch = hdrArchAMD64
+			},
+		},
+		{
+			name: "PrimaryArchWithoutPartition",
+			corrupt: func(f *FileImage, _ *Buffer) {
+				f.h.Arch = hdrArchAMD64
+			},
+		},
+		{
+			name: "OCIBlobDigest",
+			dis: []DescriptorInput{
+				getDescriptorInput(t, DataOCIBlob, []byte{0xfa, 0xce}),
+				getDescriptorInput(t, DataOCIRootIndex, []byte(`{}`)),
+			},
+			corrupt: func(f *FileImage, b *Buffer) {
+				b.Bytes()[f.rds[0].Offset] = 0xff
+				f.rds[1].Extra = [descrMaxPrivLen]byte{}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b,
+				OptCreateDeterministic(),
+				OptCreateWithDescriptorCapacity(4),
+				OptCreateWithDescriptors(tt.dis...),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.corrupt != nil {
+				tt.corrupt(f, &b)
+			}
+
+			before := bytes.Clone(b.Bytes())
+
+			findings, err := Check(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fixes, err := Repair(f, OptRepairDeterministic(), OptRepairDryRun(tt.dryRun))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// An image that passes Check must not be repaired.
+			if len(findings) == 0 && len(fixes) > 0 {
+				t.Errorf("unexpected fixes to image without findings: %v", fixes)
+			}
+
+			var out bytes.Buffer
+			for _, x := range fixes {
+				fmt.Fprintf(&out, "%v: %v\n", x.Type, x)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, out.Bytes())
+
+			if len(fixes) == 0 || tt.dryRun {
+				if !bytes.Equal(b.Bytes(), before) {
+					t.Error("image modified")
+				}
+				return
+			}
+
+			// Reload image, and check it is consistent.
+			f, err = LoadContainer(&b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			findings, err = Check(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(findings) > 0 {
+				t.Errorf("unexpected findings following repair: %v", findings)
+			}
+		})
+	}
+}
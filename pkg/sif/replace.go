@@ -0,0 +1,158 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// replaceOpts accumulates object replace options.
+type replaceOpts struct {
+	alignment int
+	t         time.Time
+}
+
+// ReplaceOpt are used to specify object replace options.
+type ReplaceOpt func(*replaceOpts) error
+
+// OptReplaceAlignment specifies n as the data alignment requirement of the replacement data.
+func OptReplaceAlignment(n int) ReplaceOpt {
+	return func(ro *replaceOpts) error {
+		ro.alignment = n
+		return nil
+	}
+}
+
+// OptReplaceDeterministic sets header/descriptor fields to values that support deterministic
+// modification of images.
+func OptReplaceDeterministic() ReplaceOpt {
+	return func(ro *replaceOpts) error {
+		ro.t = time.Time{}
+		return nil
+	}
+}
+
+// OptReplaceWithTime specifies t as the image/object modification time.
+func OptReplaceWithTime(t time.Time) ReplaceOpt {
+	return func(ro *replaceOpts) error {
+		ro.t = t
+		return nil
+	}
+}
+
+// withSignatureOf returns a selector func that selects signature descriptors that are linked to
+// the object described by rd, either directly or via its group.
+func withSignatureOf(rd *rawDescriptor) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		if d.DataType() != DataSignature {
+			return false, nil
+		}
+
+		id, isGroup := d.LinkedID()
+		if isGroup {
+			return rd.GroupID != 0 && id == rd.GroupID&^descrGroupMask, nil
+		}
+		return id == rd.ID, nil
+	}
+}
+
+// ReplaceObject replaces the data of the object with id with the contents of r, according to opts.
+// If no matching descriptor is found, an error wrapping ErrObjectNotFound is returned.
+//
+// The object retains its descriptor, including its ID, group, link, name and creation time. The
+// size and modification time of the object are updated, as is the digest of OCI blob objects. The
+// replacement data is written to the end of the image before the descriptor is updated. The space
+// previously occupied by the object is left unused; to reclaim it, consider using Compact.
+//
+// On success, the descriptors of any signatures linked to the object, either directly or via its
+// group, are returned. These signatures are no longer valid.
+//
+// By default, the replacement data of partitions is aligned to a 4096 byte boundary, and no
+// alignment is applied to other data types. To override this, consider using OptReplaceAlignment.
+//
+// By default, the image/object modification times are set to the current time for
+// non-deterministic images, and unset otherwise. To override this, consider using
+// OptReplaceDeterministic or OptReplaceWithTime.
+func (f *FileImage) ReplaceObject(id uint32, r io.Reader, opts ...ReplaceOpt) ([]Descriptor, error) {
+	if f.readOnly {
+		return nil, fmt.Errorf("%w", ErrReadOnly)
+	}
+
+	rd, err := f.getDescriptor(WithID(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	ro := replaceOpts{}
+
+	if rd.DataType == DataPartition {
+		ro.alignment = 4096
+	}
+
+	if !f.isDeterministic() {
+		ro.t = time.Now()
+	}
+
+	for _, opt := range opts {
+		if err := opt(&ro); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	// Accumulate hash for OCI blobs as they are written.
+	var md *ociBlob
+	if rd.DataType == DataOCIRootIndex || rd.DataType == DataOCIBlob {
+		md = newOCIBlobDigest()
+		r = io.TeeReader(r, md.hasher)
+	}
+
+	end := f.h.DataOffset + f.calculatedDataSize()
+
+	offset, err := nextAligned(end, ro.alignment)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if _, err := f.rw.Seek(offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	n, err := io.Copy(f.rw, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if md != nil {
+		if err := rd.setExtra(md); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	rd.Offset = offset
+	rd.Size = n
+	rd.SizeWithPadding = offset - end + n
+	rd.ModifiedAt = ro.t.Unix()
+
+	if err := f.commitDescriptors(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	f.h.DataSize = f.calculatedDataSize()
+	f.h.ModifiedAt = ro.t.Unix()
+
+	if err := f.writeHeader(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	sigs, err := f.GetDescriptors(withSignatureOf(rd))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return sigs, nil
+}
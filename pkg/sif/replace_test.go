@@ -0,0 +1,230 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestFileImage_ReplaceObject(t *testing.T) {
+	tests := []struct {
+		name       string
+		createOpts []CreateOpt
+		id         uint32
+		data       []byte
+		opts       []ReplaceOpt
+		wantErr    error
+	}{
+		{
+			name: "ErrObjectNotFound",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			id:      2,
+			wantErr: ErrObjectNotFound,
+		},
+		{
+			name: "Generic",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce},
+						OptObjectName("name"),
+						OptGroupID(2),
+						OptLinkedID(2),
+					),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			id:   1,
+			data: []byte{0xde, 0xad, 0xbe, 0xef},
+		},
+		{
+			name: "Partition",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			id:   1,
+			data: []byte{0xde, 0xad, 0xbe, 0xef},
+		},
+		{
+			name: "Aligned",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			id:   1,
+			data: []byte{0xfe, 0xed},
+			opts: []ReplaceOpt{
+				OptReplaceAlignment(128),
+			},
+		},
+		{
+			name: "OCIBlob",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataOCIBlob, []byte{0xfa, 0xce}),
+				),
+			},
+			id:   1,
+			data: []byte("{}"),
+		},
+		{
+			name: "Deterministic",
+			createOpts: []CreateOpt{
+				OptCreateWithID("de170c43-36ab-44a8-bca9-1ea1a070a274"),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+				OptCreateWithTime(time.Unix(946702800, 0)),
+			},
+			id:   1,
+			data: []byte{0xfe, 0xed},
+			opts: []ReplaceOpt{
+				OptReplaceDeterministic(),
+			},
+		},
+		{
+			name: "WithTime",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			id:   1,
+			data: []byte{0xfe, 0xed},
+			opts: []ReplaceOpt{
+				OptReplaceWithTime(time.Unix(946702800, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b, tt.createOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = f.ReplaceObject(tt.id, bytes.NewReader(tt.data), tt.opts...)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				d, err := f.GetDescriptor(WithID(tt.id))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got, err := d.GetData(); err != nil {
+					t.Fatal(err)
+				} else if want := tt.data; !bytes.Equal(got, want) {
+					t.Errorf("got data %v, want %v", got, want)
+				}
+
+				if d.DataType() == DataOCIBlob {
+					want, _, err := v1.SHA256(bytes.NewReader(tt.data))
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if got, err := d.OCIBlobDigest(); err != nil {
+						t.Fatal(err)
+					} else if got != want {
+						t.Errorf("got digest %v, want %v", got, want)
+					}
+				}
+			}
+
+			if err := f.UnloadContainer(); err != nil {
+				t.Error(err)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
+
+func TestFileImage_ReplaceObjectSignatures(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		id      uint32
+		wantIDs []uint32
+	}{
+		{
+			name:    "GroupSigned",
+			path:    "two-groups-signed-pgp.sif",
+			id:      3,
+			wantIDs: []uint32{5},
+		},
+		{
+			name:    "ObjectSigned",
+			path:    "one-group-signed-legacy-all.sif",
+			id:      2,
+			wantIDs: []uint32{4},
+		},
+		{
+			name: "NotSigned",
+			path: "two-groups-signed-legacy.sif",
+			id:   3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := os.ReadFile(filepath.Join(corpus, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := LoadContainer(NewBuffer(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sigs, err := f.ReplaceObject(tt.id, bytes.NewReader([]byte{0xfa, 0xce}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []uint32
+			for _, sig := range sigs {
+				ids = append(ids, sig.ID())
+			}
+
+			if got, want := ids, tt.wantIDs; !reflect.DeepEqual(got, want) {
+				t.Errorf("got signature IDs %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -8,6 +8,9 @@ package sif
 import (
 	"errors"
 	"fmt"
+	"iter"
+	"path"
+	"time"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 )
@@ -94,6 +97,53 @@ func WithPartitionType(pt PartType) DescriptorSelectorFunc {
 	}
 }
 
+// WithPrimaryPartitionForArch selects descriptors containing a primary system partition for the
+// CPU architecture goarch, specified using the Go runtime naming convention (for example, "amd64").
+func WithPrimaryPartitionForArch(goarch string) DescriptorSelectorFunc {
+	arch := getSIFArch(goarch)
+	fn := withPrimaryPartitionForSIFArch(arch)
+
+	return func(d Descriptor) (bool, error) {
+		if arch == hdrArchUnknown {
+			return false, fmt.Errorf("%w: %v", errUnknownArchitcture, goarch)
+		}
+		return fn(d)
+	}
+}
+
+// withPrimaryPartitionForSIFArch selects descriptors containing a primary system partition with
+// SIF architecture code arch. Unlike WithPrimaryPartitionForArch, arch need not be known to this
+// package.
+func withPrimaryPartitionForSIFArch(arch archType) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		var p partition
+		if !d.raw.isPartitionOfType(PartPrimSys) || d.raw.getExtra(binaryUnmarshaler{&p}) != nil {
+			return false, nil
+		}
+		return p.Arch == arch, nil
+	}
+}
+
+// WithPlatform selects descriptors containing a partition for platform p. The architecture of p
+// must be specified using the Go runtime naming convention. The OS and variant of p are compared
+// only if they are non-empty. Other fields of p are ignored.
+func WithPlatform(p v1.Platform) DescriptorSelectorFunc {
+	arch := getSIFArch(p.Architecture)
+
+	return func(d Descriptor) (bool, error) {
+		if arch == hdrArchUnknown {
+			return false, fmt.Errorf("%w: %v", errUnknownArchitcture, p.Architecture)
+		}
+
+		if dp, err := d.raw.getPartitionPlatform(); err == nil {
+			return dp.Architecture == p.Architecture &&
+				(p.OS == "" || dp.OS == p.OS) &&
+				(p.Variant == "" || dp.Variant == p.Variant), nil
+		}
+		return false, nil
+	}
+}
+
 // WithOCIBlobDigest selects descriptors that contain a OCI blob with the specified digest.
 func WithOCIBlobDigest(digest v1.Hash) DescriptorSelectorFunc {
 	return func(d Descriptor) (bool, error) {
@@ -104,11 +154,124 @@ func WithOCIBlobDigest(digest v1.Hash) DescriptorSelectorFunc {
 	}
 }
 
+// WithName selects descriptors with a name equal to name.
+func WithName(name string) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		return d.Name() == name, nil
+	}
+}
+
+// WithNameGlob selects descriptors with a name matching the shell pattern, using the syntax of
+// path.Match. If pattern is malformed, an error wrapping path.ErrBadPattern is returned.
+func WithNameGlob(pattern string) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		ok, err := path.Match(pattern, d.Name())
+		if err != nil {
+			return false, fmt.Errorf("%w", err)
+		}
+		return ok, nil
+	}
+}
+
+// WithFSType selects descriptors containing a partition with filesystem type fs.
+func WithFSType(fs FSType) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		if got, _, _, err := d.raw.getPartitionMetadata(); err == nil {
+			return got == fs, nil
+		}
+		return false, nil
+	}
+}
+
+// WithArch selects descriptors containing a partition for the CPU architecture goarch, specified
+// using the Go runtime naming convention (for example, "amd64"). Partitions of any type are
+// selected.
+func WithArch(goarch string) DescriptorSelectorFunc {
+	arch := getSIFArch(goarch)
+
+	return func(d Descriptor) (bool, error) {
+		if arch == hdrArchUnknown {
+			return false, fmt.Errorf("%w: %v", errUnknownArchitcture, goarch)
+		}
+
+		var p partition
+		if d.raw.DataType != DataPartition || d.raw.getExtra(binaryUnmarshaler{&p}) != nil {
+			return false, nil
+		}
+		return p.Arch == arch, nil
+	}
+}
+
+// WithSBOMFormat selects descriptors containing a SBOM in format f.
+func WithSBOMFormat(f SBOMFormat) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		if got, err := d.SBOMMetadata(); err == nil {
+			return got == f, nil
+		}
+		return false, nil
+	}
+}
+
+// WithSizeRange selects descriptors with a data object size of at least minSize bytes, and at
+// most maxSize bytes.
+func WithSizeRange(minSize, maxSize int64) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		return minSize <= d.Size() && d.Size() <= maxSize, nil
+	}
+}
+
+// WithCreatedBetween selects descriptors with a creation time at or after start, and before end.
+func WithCreatedBetween(start, end time.Time) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		t := d.CreatedAt()
+		return !t.Before(start) && t.Before(end), nil
+	}
+}
+
+// Or selects descriptors selected by any of fns. The selector funcs are called in order, until
+// one selects the descriptor or returns an error.
+func Or(fns ...DescriptorSelectorFunc) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		for _, fn := range fns {
+			if ok, err := fn(d); ok || err != nil {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// Not selects descriptors that are not selected by fn.
+func Not(fn DescriptorSelectorFunc) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		ok, err := fn(d)
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	}
+}
+
+// WithAnnotation selects descriptors of data objects that have an annotation with key k and
+// value v.
+func WithAnnotation(k, v string) DescriptorSelectorFunc {
+	return func(d Descriptor) (bool, error) {
+		annotations, err := d.Annotations()
+		if err != nil {
+			return false, err
+		}
+
+		got, ok := annotations[k]
+		return ok && got == v, nil
+	}
+}
+
 // descriptorFromRaw populates a Descriptor from rd.
 func (f *FileImage) descriptorFromRaw(rd *rawDescriptor) Descriptor {
 	return Descriptor{
 		raw:        *rd,
 		r:          f.rw,
+		f:          f,
 		relativeID: rd.ID - f.minIDs[rd.GroupID],
 	}
 }
@@ -133,6 +296,26 @@ func (f *FileImage) GetDescriptors(fns ...DescriptorSelectorFunc) ([]Descriptor,
 	return ds, nil
 }
 
+// Descriptors returns an iterator over the in-use descriptors for which all selector funcs return
+// true. If a selector func returns an error, the error is yielded and iteration stops. Unlike
+// GetDescriptors, no error is yielded if the image contains no data objects.
+//
+// Descriptors are yielded in order of their position in the descriptor section. If f is modified
+// during iteration, the descriptors yielded may not reflect the modification.
+func (f *FileImage) Descriptors(fns ...DescriptorSelectorFunc) iter.Seq2[Descriptor, error] {
+	return func(yield func(Descriptor, error) bool) {
+		err := f.withDescriptors(multiSelectorFunc(fns...), func(d *rawDescriptor) error {
+			if !yield(f.descriptorFromRaw(d), nil) {
+				return errAbort
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errAbort) {
+			yield(Descriptor{}, fmt.Errorf("%w", err))
+		}
+	}
+}
+
 // getDescriptor returns a pointer to the in-use descriptor selected by fns. If no descriptor is
 // selected by fns, ErrObjectNotFound is returned. If multiple descriptors are selected by fns,
 // ErrMultipleObjectsFound is returned.
@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2023, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -7,7 +7,10 @@ package sif
 
 import (
 	"errors"
+	"path"
+	"reflect"
 	"testing"
+	"time"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 )
@@ -240,6 +243,48 @@ func TestFileImage_GetDescriptor(t *testing.T) {
 			},
 			wantID: 1,
 		},
+		{
+			name: "PrimaryPartitionForArch",
+			fns: []DescriptorSelectorFunc{
+				WithPrimaryPartitionForArch("386"),
+			},
+			wantID: 1,
+		},
+		{
+			name: "PrimaryPartitionForArchNotFound",
+			fns: []DescriptorSelectorFunc{
+				WithPrimaryPartitionForArch("amd64"),
+			},
+			wantErr: ErrObjectNotFound,
+		},
+		{
+			name: "PrimaryPartitionForArchUnknown",
+			fns: []DescriptorSelectorFunc{
+				WithPrimaryPartitionForArch("cray"),
+			},
+			wantErr: errUnknownArchitcture,
+		},
+		{
+			name: "Platform",
+			fns: []DescriptorSelectorFunc{
+				WithPlatform(v1.Platform{Architecture: "386"}),
+			},
+			wantID: 1,
+		},
+		{
+			name: "PlatformVariantNotFound",
+			fns: []DescriptorSelectorFunc{
+				WithPlatform(v1.Platform{Architecture: "386", Variant: "sse2"}),
+			},
+			wantErr: ErrObjectNotFound,
+		},
+		{
+			name: "PlatformUnknownArchitecture",
+			fns: []DescriptorSelectorFunc{
+				WithPlatform(v1.Platform{Architecture: "cray"}),
+			},
+			wantErr: errUnknownArchitcture,
+		},
 		{
 			name: "OCIBlobDigest",
 			fns: []DescriptorSelectorFunc{
@@ -265,6 +310,188 @@ func TestFileImage_GetDescriptor(t *testing.T) {
 	}
 }
 
+func TestFileImage_Descriptors(t *testing.T) {
+	t1 := time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	f, err := CreateContainer(&Buffer{},
+		OptCreateDeterministic(),
+		OptCreateWithDescriptors(
+			getDescriptorInput(t, DataDeffile, []byte("bootstrap: docker"),
+				OptObjectName("def"),
+				OptObjectTime(t1),
+			),
+			getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+				OptObjectName("rootfs.squashfs"),
+				OptObjectTime(t1),
+				OptPartitionMetadata(FsSquash, PartPrimSys, "amd64"),
+			),
+			getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed, 0xfa, 0xce},
+				OptObjectName("overlay.img"),
+				OptObjectTime(t2),
+				OptPartitionMetadata(FsExt3, PartOverlay, "386"),
+			),
+			getDescriptorInput(t, DataSBOM, []byte("{}"),
+				OptObjectName("sbom.cdx.json"),
+				OptObjectTime(t2),
+				OptSBOMMetadata(SBOMFormatCycloneDXJSON),
+			),
+			getDescriptorInput(t, DataSBOM, []byte("{ }"),
+				OptObjectName("sbom.spdx.json"),
+				OptObjectTime(t2),
+				OptSBOMMetadata(SBOMFormatSPDXJSON),
+			),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		fns     []DescriptorSelectorFunc
+		wantIDs []uint32
+		wantErr error
+	}{
+		{
+			name:    "All",
+			wantIDs: []uint32{1, 2, 3, 4, 5},
+		},
+		{
+			name:    "DataType",
+			fns:     []DescriptorSelectorFunc{WithDataType(DataPartition)},
+			wantIDs: []uint32{2, 3},
+		},
+		{
+			name:    "Name",
+			fns:     []DescriptorSelectorFunc{WithName("overlay.img")},
+			wantIDs: []uint32{3},
+		},
+		{
+			name:    "NameGlob",
+			fns:     []DescriptorSelectorFunc{WithNameGlob("sbom.*.json")},
+			wantIDs: []uint32{4, 5},
+		},
+		{
+			name:    "NameGlobBadPattern",
+			fns:     []DescriptorSelectorFunc{WithNameGlob("[")},
+			wantErr: path.ErrBadPattern,
+		},
+		{
+			name:    "FSType",
+			fns:     []DescriptorSelectorFunc{WithFSType(FsExt3)},
+			wantIDs: []uint32{3},
+		},
+		{
+			name:    "Arch",
+			fns:     []DescriptorSelectorFunc{WithArch("386")},
+			wantIDs: []uint32{3},
+		},
+		{
+			name:    "ArchUnknown",
+			fns:     []DescriptorSelectorFunc{WithArch("cray")},
+			wantErr: errUnknownArchitcture,
+		},
+		{
+			name:    "SBOMFormat",
+			fns:     []DescriptorSelectorFunc{WithSBOMFormat(SBOMFormatSPDXJSON)},
+			wantIDs: []uint32{5},
+		},
+		{
+			name:    "SizeRange",
+			fns:     []DescriptorSelectorFunc{WithSizeRange(2, 3)},
+			wantIDs: []uint32{2, 4, 5},
+		},
+		{
+			name:    "CreatedBetween",
+			fns:     []DescriptorSelectorFunc{WithCreatedBetween(t1, t2)},
+			wantIDs: []uint32{1, 2},
+		},
+		{
+			name: "Or",
+			fns: []DescriptorSelectorFunc{
+				Or(WithDataType(DataDeffile), WithSBOMFormat(SBOMFormatCycloneDXJSON)),
+			},
+			wantIDs: []uint32{1, 4},
+		},
+		{
+			name:    "OrNone",
+			fns:     []DescriptorSelectorFunc{Or()},
+			wantIDs: nil,
+		},
+		{
+			name:    "OrError",
+			fns:     []DescriptorSelectorFunc{Or(WithDataType(DataDeffile), WithID(0))},
+			wantIDs: []uint32{1},
+			wantErr: ErrInvalidObjectID,
+		},
+		{
+			name: "Not",
+			fns: []DescriptorSelectorFunc{
+				WithDataType(DataPartition),
+				Not(WithPartitionType(PartPrimSys)),
+			},
+			wantIDs: []uint32{3},
+		},
+		{
+			name:    "NotError",
+			fns:     []DescriptorSelectorFunc{Not(WithGroupID(0))},
+			wantErr: ErrInvalidGroupID,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []uint32
+			var err error
+
+			for d, derr := range f.Descriptors(tt.fns...) {
+				if derr != nil {
+					err = derr
+					break
+				}
+				ids = append(ids, d.ID())
+			}
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := ids, tt.wantIDs; !reflect.DeepEqual(got, want) {
+				t.Errorf("got IDs %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFileImage_DescriptorsBreak(t *testing.T) {
+	f, err := CreateContainer(&Buffer{},
+		OptCreateDeterministic(),
+		OptCreateWithDescriptors(
+			getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+			getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []uint32
+	for d, err := range f.Descriptors() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, d.ID())
+
+		if d.ID() == 1 {
+			break
+		}
+	}
+
+	if got, want := ids, []uint32{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got IDs %v, want %v", got, want)
+	}
+}
+
 func TestFileImage_WithDescriptors(t *testing.T) {
 	ds := []rawDescriptor{
 		{
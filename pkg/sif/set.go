@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
@@ -46,12 +46,18 @@ var (
 	errNotSystem    = errors.New("data object not a system partition")
 )
 
-// SetPrimPart sets the specified system partition to be the primary one.
+// SetPrimPart sets the specified system partition to be the primary one for its CPU architecture.
+// If there is an existing primary system partition for the same architecture, it is demoted to a
+// system partition. Primary system partitions for other architectures are not modified.
 //
 // By default, the image/object modification times are set to the current time for
 // non-deterministic images, and unset otherwise. To override this, consider using
 // OptSetDeterministic or OptSetWithTime.
 func (f *FileImage) SetPrimPart(id uint32, opts ...SetOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
 	so := setOpts{}
 
 	if !f.isDeterministic() {
@@ -87,8 +93,8 @@ func (f *FileImage) SetPrimPart(id uint32, opts ...SetOpt) error {
 		return fmt.Errorf("%w", errNotSystem)
 	}
 
-	// If there is currently a primary system partition, update it.
-	if d, err := f.getDescriptor(WithPartitionType(PartPrimSys)); err == nil {
+	// If there is currently a primary system partition for the same architecture, update it.
+	if d, err := f.getDescriptor(withPrimaryPartitionForSIFArch(p.Arch)); err == nil {
 		var p partition
 		if err := d.getExtra(binaryUnmarshaler{&p}); err != nil {
 			return fmt.Errorf("%w", err)
@@ -118,7 +124,7 @@ func (f *FileImage) SetPrimPart(id uint32, opts ...SetOpt) error {
 		return fmt.Errorf("%w", err)
 	}
 
-	f.h.Arch = p.Arch
+	f.h.Arch = f.calculatedArch()
 	f.h.ModifiedAt = so.t.Unix()
 
 	if err := f.writeHeader(); err != nil {
@@ -134,6 +140,10 @@ func (f *FileImage) SetPrimPart(id uint32, opts ...SetOpt) error {
 // non-deterministic images, and unset otherwise. To override this, consider using
 // OptSetDeterministic or OptSetWithTime.
 func (f *FileImage) SetMetadata(id uint32, md encoding.BinaryMarshaler, opts ...SetOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
 	so := setOpts{}
 
 	if !f.isDeterministic() {
@@ -176,6 +186,10 @@ func (f *FileImage) SetMetadata(id uint32, md encoding.BinaryMarshaler, opts ...
 // non-deterministic images, and unset otherwise. To override this, consider using
 // OptSetDeterministic or OptSetWithTime.
 func (f *FileImage) SetOCIBlobDigest(id uint32, h v1.Hash, opts ...SetOpt) error {
+	if f.readOnly {
+		return fmt.Errorf("%w", ErrReadOnly)
+	}
+
 	rd, err := f.getDescriptor(WithID(id))
 	if err != nil {
 		return fmt.Errorf("%w", err)
@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -14,6 +14,9 @@ import (
 	"github.com/sebdah/goldie/v2"
 )
 
+// testArchUnknown is an architecture code that is not known to this package.
+var testArchUnknown = archType{'9', '9', '\x00'}
+
 func TestSetPrimPart(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -30,6 +33,21 @@ func TestSetPrimPart(t *testing.T) {
 			id:      1,
 			wantErr: ErrObjectNotFound,
 		},
+		{
+			name: "UnknownArch",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptMetadata(partition{Fstype: FsSquash, Parttype: PartPrimSys, Arch: testArchUnknown}),
+					),
+					getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+						OptMetadata(partition{Fstype: FsSquash, Parttype: PartSystem, Arch: testArchUnknown}),
+					),
+				),
+			},
+			id: 2,
+		},
 		{
 			name: "Deterministic",
 			createOpts: []CreateOpt{
@@ -55,7 +73,7 @@ func TestSetPrimPart(t *testing.T) {
 						OptPartitionMetadata(FsRaw, PartPrimSys, "386"),
 					),
 					getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
-						OptPartitionMetadata(FsRaw, PartSystem, "amd64"),
+						OptPartitionMetadata(FsRaw, PartSystem, "386"),
 					),
 				),
 			},
@@ -78,6 +96,21 @@ func TestSetPrimPart(t *testing.T) {
 		},
 		{
 			name: "Two",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsRaw, PartPrimSys, "386"),
+					),
+					getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+						OptPartitionMetadata(FsRaw, PartSystem, "386"),
+					),
+				),
+			},
+			id: 2,
+		},
+		{
+			name: "TwoArch",
 			createOpts: []CreateOpt{
 				OptCreateDeterministic(),
 				OptCreateWithDescriptors(
@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2023, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
@@ -117,6 +117,9 @@ const (
 	descrEntityLen  = 256        // len("Joe Bloe <jbloe@gmail.com>...")
 	descrNameLen    = 128        // descriptor name (string identifier)
 	descrMaxPrivLen = 384        // size reserved for descriptor specific data
+
+	partVariantLen = 32 // len("v8")...
+	partOSLen      = 32 // len("linux")...
 )
 
 // DataType represents the different SIF data object types stored in the image.
@@ -359,7 +362,10 @@ type FileImage struct {
 	rds []rawDescriptor // Raw descriptors from image.
 
 	closeOnUnload bool              // Close rw on Unload.
+	readOnly      bool              // Image cannot be modified.
 	minIDs        map[uint32]uint32 // Minimum object IDs for each group ID.
+
+	tx *Tx // Transaction staging modifications, if image is staged.
 }
 
 // LaunchScript returns the image launch script.
@@ -373,7 +379,9 @@ func (f *FileImage) Version() string {
 }
 
 // PrimaryArch returns the primary CPU architecture of the image, or "unknown" if the primary CPU
-// architecture cannot be determined.
+// architecture cannot be determined. If the image contains primary system partitions for multiple
+// architectures, the architecture of the first is returned. To select the primary system partition
+// for a specific architecture, use WithPrimaryPartitionForArch.
 func (f *FileImage) PrimaryArch() string { return f.h.Arch.GoArch() }
 
 // ID returns the ID of the image.
@@ -0,0 +1,166 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	errOCIBlobDigestRequired = errors.New("OCI blob digest must be specified")
+	errObjectSizeMismatch    = errors.New("object size does not match data")
+	errOffsetWritten         = errors.New("offset already written")
+)
+
+// layoutDataObject records details of the data object described by di, whose size is n, in the
+// descriptor at index i, using time t. No data is written.
+func (f *FileImage) layoutDataObject(i int, di DescriptorInput, n int64, t time.Time) error {
+	// The digest of OCI blobs is recorded in the descriptor, so must be known in advance.
+	if ob, ok := di.opts.md.(*ociBlob); ok && ob.digest.Hex == "" {
+		return errOCIBlobDigestRequired
+	}
+
+	d, offsetUnaligned, err := f.allocateDataObject(i, di)
+	if err != nil {
+		return err
+	}
+
+	offset, err := nextAligned(offsetUnaligned, di.opts.alignment)
+	if err != nil {
+		return err
+	}
+
+	if err := di.fillDescriptor(t, d); err != nil {
+		return err
+	}
+	d.Used = true
+	d.Offset = offset
+	d.Size = n
+	d.SizeWithPadding = offset - offsetUnaligned + n
+
+	f.recordDataObject(d)
+
+	return nil
+}
+
+// streamWriter writes a SIF image sequentially to an io.Writer.
+type streamWriter struct {
+	w   io.Writer
+	off int64 // Current offset within image.
+}
+
+// Write writes b at the current offset.
+func (sw *streamWriter) Write(b []byte) (int, error) {
+	n, err := sw.w.Write(b)
+	sw.off += int64(n)
+	return n, err
+}
+
+// writeAt writes the binary representation of data at offset off, padding with zero bytes as
+// required.
+func (sw *streamWriter) writeAt(off int64, data any) error {
+	if err := sw.padTo(off); err != nil {
+		return err
+	}
+
+	return binary.Write(sw, binary.LittleEndian, data)
+}
+
+// padTo writes zero bytes until the current offset reaches off.
+func (sw *streamWriter) padTo(off int64) error {
+	if off < sw.off {
+		return fmt.Errorf("%w: %v", errOffsetWritten, off)
+	}
+
+	_, err := io.CopyN(sw, zeroReader{}, off-sw.off)
+	return err
+}
+
+// writeDataObject writes the data object described by di, whose details are recorded in d.
+func (sw *streamWriter) writeDataObject(di DescriptorInput, d rawDescriptor) error {
+	if err := sw.padTo(d.Offset); err != nil {
+		return err
+	}
+
+	if _, err := io.CopyN(sw, di.r, d.Size); errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: object %v is smaller than %v bytes", errObjectSizeMismatch, d.ID, d.Size)
+	} else if err != nil {
+		return err
+	}
+
+	// Ensure there is no data remaining.
+	if n, _ := io.ReadFull(di.r, make([]byte, 1)); n > 0 {
+		return fmt.Errorf("%w: object %v is larger than %v bytes", errObjectSizeMismatch, d.ID, d.Size)
+	}
+
+	return di.verify()
+}
+
+// WriteContainer writes a new SIF container to w, according to opts. One or more data objects
+// can optionally be specified using OptCreateWithDescriptors.
+//
+// The header, descriptors and data objects are written sequentially, so w need not support
+// seeking. The output is identical to that produced by CreateContainer for the same opts. Since
+// the descriptors precede the data objects, the size of each data object must be known in
+// advance. Sizes can be specified using OptObjectSize, or are otherwise determined from the
+// data object reader if it implements a Len method (such as *bytes.Reader), or io.Seeker (such as
+// *os.File). Likewise, the digest of each OCI blob must be specified using OptOCIBlobDigest. If a
+// data object does not match its specified size or digest, an error is returned.
+//
+// By default, the image ID is set to a randomly generated value. To override this, consider using
+// OptCreateDeterministic or OptCreateWithID.
+//
+// By default, the image creation time is set to the current time. To override this, consider using
+// OptCreateDeterministic or OptCreateWithTime.
+//
+// By default, the image will support a maximum of 48 descriptors. To change this, consider using
+// OptCreateWithDescriptorCapacity.
+//
+// A launch script can optionally be set using OptCreateWithLaunchScript.
+func WriteContainer(w io.Writer, opts ...CreateOpt) error {
+	co, err := getCreateOpts(opts...)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	f, err := newContainer(nil, co)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	for i, di := range co.dis {
+		n, err := di.size()
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if err := f.layoutDataObject(i, di, n, co.t); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	sw := &streamWriter{w: w}
+
+	if err := sw.writeAt(0, f.h); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := sw.writeAt(f.h.DescriptorsOffset, f.rds); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	for i, di := range co.dis {
+		if err := sw.writeDataObject(di, f.rds[i]); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// getOCIBlobDigest returns the digest of the OCI blob b.
+func getOCIBlobDigest(t *testing.T, b []byte) v1.Hash {
+	t.Helper()
+
+	h, _, err := v1.SHA256(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// getTestFile returns an open file containing b.
+func getTestFile(t *testing.T, b []byte) *os.File {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "object")
+
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func TestWriteContainer(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    func(t *testing.T) []CreateOpt
+		wantErr error
+	}{
+		{
+			name: "ErrInsufficientCapacity",
+			opts: func(t *testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptorCapacity(0),
+					OptCreateWithDescriptors(
+						getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					),
+				}
+			},
+			wantErr: errInsufficientCapacity,
+		},
+		{
+			name: "ErrObjectSizeUnknown",
+			opts: func(t *testing.T) []CreateOpt {
+				di, err := NewDescriptorInput(DataGeneric, io.MultiReader(bytes.NewReader([]byte{0xfa, 0xce})))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(di),
+				}
+			},
+			wantErr: errObjectSizeUnknown,
+		},
+		{
+			name: "ErrObjectSizeMismatchSmaller",
+			opts: func(t *testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(
+						getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce},
+							OptObjectSize(3),
+						),
+					),
+				}
+			},
+			wantErr: errObjectSizeMismatch,
+		},
+		{
+			name: "ErrObjectSizeMismatchLarger",
+			opts: func(t *testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(
+						getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce},
+							OptObjectSize(1),
+						),
+					),
+				}
+			},
+			wantErr: errObjectSizeMismatch,
+		},
+		{
+			name: "ErrOCIBlobDigestRequired",
+			opts: func(t *testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(
+						getDescriptorInput(t, DataOCIBlob, []byte("{}")),
+					),
+				}
+			},
+			wantErr: errOCIBlobDigestRequired,
+		},
+		{
+			name: "ErrOCIBlobDigestMismatch",
+			opts: func(t *testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(
+						getDescriptorInput(t, DataOCIBlob, []byte("{}"),
+							OptOCIBlobDigest(getOCIBlobDigest(t, []byte("[]"))),
+						),
+					),
+				}
+			},
+			wantErr: errOCIBlobDigestMismatch,
+		},
+		{
+			name: "Empty",
+			opts: func(*testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateDeterministic(),
+				}
+			},
+		},
+		{
+			name: "EmptyWithOptions",
+			opts: func(*testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateWithLaunchScript("#!/usr/bin/env launch-script\n"),
+					OptCreateWithID("de170c43-36ab-44a8-bca9-1ea1a070a274"),
+					OptCreateWithTime(time.Unix(946702800, 0)),
+					OptCreateWithDescriptorCapacity(1),
+				}
+			},
+		},
+		{
+			name: "Descriptors",
+			opts: func(t *testing.T) []CreateOpt {
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(
+						getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce},
+							OptObjectName("name"),
+						),
+						getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+							OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+						),
+						getDescriptorInput(t, DataGeneric, []byte{0xde, 0xad},
+							OptObjectAlignment(128),
+							OptLinkedID(1),
+						),
+						getDescriptorInput(t, DataOCIBlob, []byte("{}"),
+							OptOCIBlobDigest(getOCIBlobDigest(t, []byte("{}"))),
+						),
+					),
+				}
+			},
+		},
+		{
+			name: "ObjectSize",
+			opts: func(t *testing.T) []CreateOpt {
+				di, err := NewDescriptorInput(DataGeneric, io.MultiReader(bytes.NewReader([]byte{0xfa, 0xce})),
+					OptObjectSize(2),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(di),
+				}
+			},
+		},
+		{
+			name: "File",
+			opts: func(t *testing.T) []CreateOpt {
+				di, err := NewDescriptorInput(DataGeneric, getTestFile(t, []byte{0xfa, 0xce}))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return []CreateOpt{
+					OptCreateDeterministic(),
+					OptCreateWithDescriptors(di),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			err := WriteContainer(&b, tt.opts(t)...)
+
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				var want Buffer
+
+				if _, err := CreateContainer(&want, tt.opts(t)...); err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(b.Bytes(), want.Bytes()) {
+					t.Errorf("output does not match CreateContainer")
+				}
+			}
+		})
+	}
+}
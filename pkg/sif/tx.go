@@ -0,0 +1,247 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// ErrTxDone is the error returned when an operation is performed on a transaction that has
+// already been committed or rolled back.
+var ErrTxDone = errors.New("transaction has already been committed or rolled back")
+
+// Tx is a transaction that stages modifications to a FileImage.
+//
+// Data objects added within a transaction are written to unused space at the end of the image as
+// they are added, but the header and descriptors of the image are not modified until Commit is
+// called. Operations that would modify the data of existing objects, such as zeroing deleted
+// objects, are deferred until Commit.
+type Tx struct {
+	f    *FileImage // Image being modified.
+	s    *FileImage // Staged copy of image.
+	end  int64      // End of image when the transaction began.
+	done bool       // Transaction has been committed or rolled back.
+
+	zero    []rawDescriptor // Deleted objects to zero on commit.
+	compact bool            // Remove unused space at end of image on commit.
+}
+
+// Begin starts a transaction on f. Modifications are staged in the returned Tx, and are applied
+// to f when Commit is called, or discarded when Rollback is called.
+//
+// The caller must call Commit or Rollback to complete the transaction. The FileImage must not be
+// modified outside of the transaction until the transaction is complete.
+func (f *FileImage) Begin() (*Tx, error) {
+	if f.readOnly {
+		return nil, fmt.Errorf("%w", ErrReadOnly)
+	}
+
+	end, err := f.rw.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	tx := &Tx{
+		f:   f,
+		end: end,
+	}
+
+	tx.s = &FileImage{
+		rw:     f.rw,
+		h:      f.h,
+		rds:    slices.Clone(f.rds),
+		minIDs: maps.Clone(f.minIDs),
+		tx:     tx,
+	}
+
+	return tx, nil
+}
+
+// do calls fn with the staged image. If fn returns an error, any modifications to the staged image
+// made by fn are discarded.
+func (tx *Tx) do(fn func(*FileImage) error) error {
+	if tx.done {
+		return fmt.Errorf("%w", ErrTxDone)
+	}
+
+	h, rds, minIDs := tx.s.h, slices.Clone(tx.s.rds), maps.Clone(tx.s.minIDs)
+	zero, compact := len(tx.zero), tx.compact
+
+	if err := fn(tx.s); err != nil {
+		tx.s.h, tx.s.rds, tx.s.minIDs = h, rds, minIDs
+		tx.zero, tx.compact = tx.zero[:zero], compact
+
+		return err
+	}
+
+	return nil
+}
+
+// AddObject stages the addition of a new data object and its descriptor. See
+// FileImage.AddObject for details.
+//
+// The object data is written to the image immediately, but is not referenced by the image until
+// the transaction is committed. If growth of the descriptor capacity is requested using
+// OptAddGrowDescriptors, the descriptor section must be able to grow without relocating data
+// objects.
+func (tx *Tx) AddObject(di DescriptorInput, opts ...AddOpt) error {
+	return tx.do(func(f *FileImage) error {
+		return f.AddObject(di, opts...)
+	})
+}
+
+// DeleteObject stages the deletion of the data object with id. See FileImage.DeleteObject for
+// details.
+//
+// If OptDeleteZero or OptDeleteCompact are specified, the corresponding operations are deferred
+// until the transaction is committed.
+func (tx *Tx) DeleteObject(id uint32, opts ...DeleteOpt) error {
+	return tx.DeleteObjects(WithID(id), opts...)
+}
+
+// DeleteObjects stages the deletion of the data objects selected by fn. See
+// FileImage.DeleteObjects for details.
+//
+// If OptDeleteZero or OptDeleteCompact are specified, the corresponding operations are deferred
+// until the transaction is committed.
+func (tx *Tx) DeleteObjects(fn DescriptorSelectorFunc, opts ...DeleteOpt) error {
+	return tx.do(func(f *FileImage) error {
+		return f.DeleteObjects(fn, opts...)
+	})
+}
+
+// ReplaceObject stages replacement of the data of the object with id with the contents of r. See
+// FileImage.ReplaceObject for details.
+//
+// The replacement data is written to the image immediately, but is not referenced by the image
+// until the transaction is committed.
+func (tx *Tx) ReplaceObject(id uint32, r io.Reader, opts ...ReplaceOpt) ([]Descriptor, error) {
+	var sigs []Descriptor
+
+	err := tx.do(func(f *FileImage) error {
+		var err error
+		sigs, err = f.ReplaceObject(id, r, opts...)
+		return err
+	})
+
+	return sigs, err
+}
+
+// SetPrimPart stages setting the specified system partition to be the primary one. See
+// FileImage.SetPrimPart for details.
+func (tx *Tx) SetPrimPart(id uint32, opts ...SetOpt) error {
+	return tx.do(func(f *FileImage) error {
+		return f.SetPrimPart(id, opts...)
+	})
+}
+
+// SetMetadata stages setting the metadata of the data object with id to md. See
+// FileImage.SetMetadata for details.
+func (tx *Tx) SetMetadata(id uint32, md encoding.BinaryMarshaler, opts ...SetOpt) error {
+	return tx.do(func(f *FileImage) error {
+		return f.SetMetadata(id, md, opts...)
+	})
+}
+
+// SetOCIBlobDigest stages updating the digest of the OCI blob object with id to h. See
+// FileImage.SetOCIBlobDigest for details.
+func (tx *Tx) SetOCIBlobDigest(id uint32, h v1.Hash, opts ...SetOpt) error {
+	return tx.do(func(f *FileImage) error {
+		return f.SetOCIBlobDigest(id, h, opts...)
+	})
+}
+
+// SetAnnotations stages setting the annotations of the data object with id to annotations. See
+// FileImage.SetAnnotations for details.
+func (tx *Tx) SetAnnotations(id uint32, annotations map[string]string, opts ...SetOpt) error {
+	return tx.do(func(f *FileImage) error {
+		return f.SetAnnotations(id, annotations, opts...)
+	})
+}
+
+// Commit applies the modifications staged in tx to the image. The descriptors and header of the
+// image are each written once. If the backing storage implements a Sync method (such as
+// *os.File), it is used to commit object data before descriptors are written.
+//
+// If the descriptors or header cannot be written, the previous descriptors and header of the image
+// are restored, and an error is returned. In this case, the transaction remains active, and the
+// caller should call Rollback to remove any object data written within the transaction.
+func (tx *Tx) Commit() error {
+	if tx.done {
+		return fmt.Errorf("%w", ErrTxDone)
+	}
+
+	f := tx.f
+
+	h, rds, minIDs := f.h, f.rds, f.minIDs
+
+	f.h = tx.s.h
+	f.rds = tx.s.rds
+	f.minIDs = tx.s.minIDs
+
+	if tx.compact {
+		f.h.DataSize = f.calculatedDataSize()
+	}
+
+	if err := f.commitHeaderAndDescriptors(); err != nil {
+		f.h, f.rds, f.minIDs = h, rds, minIDs
+
+		// Restore the previous descriptors and header in backing storage, which may have been
+		// partially overwritten.
+		if rerr := f.commitHeaderAndDescriptors(); rerr != nil {
+			return errors.Join(err, fmt.Errorf("failed to restore image: %w", rerr))
+		}
+
+		return fmt.Errorf("%w", err)
+	}
+
+	tx.done = true
+
+	for i := range tx.zero {
+		if err := f.zero(&tx.zero[i]); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	if tx.compact {
+		if err := f.rw.Truncate(f.h.DataOffset + f.h.DataSize); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	return nil
+}
+
+// commitHeaderAndDescriptors writes the descriptors and header in f to backing storage.
+func (f *FileImage) commitHeaderAndDescriptors() error {
+	if err := f.commitDescriptors(); err != nil {
+		return err
+	}
+
+	return f.writeHeader()
+}
+
+// Rollback discards the modifications staged in tx, and removes any object data written to the
+// image within the transaction.
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return fmt.Errorf("%w", ErrTxDone)
+	}
+	tx.done = true
+
+	if err := tx.f.rw.Truncate(tx.end); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
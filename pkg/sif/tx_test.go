@@ -0,0 +1,309 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package sif
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+)
+
+func TestTx(t *testing.T) {
+	tests := []struct {
+		name       string
+		createOpts []CreateOpt
+		fn         func(*Tx) error
+		rollback   bool
+		wantErr    error
+	}{
+		{
+			name: "AddObject",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			fn: func(tx *Tx) error {
+				return tx.AddObject(getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}))
+			},
+		},
+		{
+			name: "AddObjectRollback",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			fn: func(tx *Tx) error {
+				return tx.AddObject(getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}))
+			},
+			rollback: true,
+		},
+		{
+			name: "ReplaceObject",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			fn: func(tx *Tx) error {
+				if err := tx.DeleteObject(2, OptDeleteZero(true), OptDeleteCompact(true)); err != nil {
+					return err
+				}
+				return tx.AddObject(getDescriptorInput(t, DataGeneric, []byte{0xde, 0xad, 0xbe, 0xef}))
+			},
+		},
+		{
+			name: "ReplaceObjectRollback",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			fn: func(tx *Tx) error {
+				if err := tx.DeleteObject(2, OptDeleteZero(true), OptDeleteCompact(true)); err != nil {
+					return err
+				}
+				return tx.AddObject(getDescriptorInput(t, DataGeneric, []byte{0xde, 0xad, 0xbe, 0xef}))
+			},
+			rollback: true,
+		},
+		{
+			name: "ReplaceObjectData",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			fn: func(tx *Tx) error {
+				_, err := tx.ReplaceObject(2, bytes.NewReader([]byte{0xde, 0xad, 0xbe, 0xef}))
+				return err
+			},
+		},
+		{
+			name: "DeleteCompact",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+					getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed}),
+				),
+			},
+			fn: func(tx *Tx) error {
+				return tx.DeleteObject(2, OptDeleteCompact(true))
+			},
+		},
+		{
+			name: "SetPrimPart",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+					getDescriptorInput(t, DataPartition, []byte{0xfe, 0xed},
+						OptPartitionMetadata(FsSquash, PartSystem, "386"),
+					),
+				),
+			},
+			fn: func(tx *Tx) error {
+				return tx.SetPrimPart(2)
+			},
+		},
+		{
+			name: "SetMetadata",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			},
+			fn: func(tx *Tx) error {
+				return tx.SetMetadata(1, newOCIBlobDigest())
+			},
+		},
+		{
+			name: "ErrPrimaryPartition",
+			createOpts: []CreateOpt{
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataPartition, []byte{0xfa, 0xce},
+						OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+					),
+				),
+			},
+			fn: func(tx *Tx) error {
+				if err := tx.AddObject(getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed})); err != nil {
+					return err
+				}
+
+				err := tx.AddObject(getDescriptorInput(t, DataPartition, []byte{0xde, 0xad},
+					OptPartitionMetadata(FsSquash, PartPrimSys, "386"),
+				))
+				if !errors.Is(err, errPrimaryPartition) {
+					return err
+				}
+
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Buffer
+
+			f, err := CreateContainer(&b, tt.createOpts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			before := bytes.Clone(b.Bytes())
+
+			tx, err := f.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := tt.fn(tx), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			// Header and descriptors must not be modified prior to commit.
+			if got, want := b.Bytes()[:f.DataOffset()], before[:f.DataOffset()]; !bytes.Equal(got, want) {
+				t.Error("header/descriptors modified before commit")
+			}
+
+			if tt.rollback {
+				if err := tx.Rollback(); err != nil {
+					t.Fatal(err)
+				}
+
+				if got, want := b.Bytes(), before; !bytes.Equal(got, want) {
+					t.Error("image modified after rollback")
+				}
+			} else if err := tx.Commit(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := tx.Commit(), ErrTxDone; !errors.Is(got, want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+
+			if got, want := tx.Rollback(), ErrTxDone; !errors.Is(got, want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+
+			if got, want := tx.AddObject(getDescriptorInput(t, DataGeneric, nil)), ErrTxDone; !errors.Is(got, want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+
+			if err := f.UnloadContainer(); err != nil {
+				t.Error(err)
+			}
+
+			g := goldie.New(t, goldie.WithTestNameForDir(true))
+			g.Assert(t, tt.name, b.Bytes())
+		})
+	}
+}
+
+var errTestWrite = errors.New("write failed")
+
+// failingBuffer is a Buffer that fails the write with index failWrite.
+type failingBuffer struct {
+	*Buffer
+	writes    int
+	failWrite int
+}
+
+func (b *failingBuffer) Write(p []byte) (int, error) {
+	defer func() { b.writes++ }()
+
+	if b.writes == b.failWrite {
+		return 0, errTestWrite
+	}
+	return b.Buffer.Write(p)
+}
+
+func TestTx_CommitWriteFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		failWrite int
+	}{
+		{
+			name:      "Descriptors",
+			failWrite: 0,
+		},
+		{
+			name:      "Header",
+			failWrite: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &failingBuffer{Buffer: &Buffer{}, failWrite: -1}
+
+			f, err := CreateContainer(b,
+				OptCreateDeterministic(),
+				OptCreateWithDescriptors(
+					getDescriptorInput(t, DataGeneric, []byte{0xfa, 0xce}),
+				),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			before := bytes.Clone(b.Bytes())
+
+			tx, err := f.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tx.AddObject(getDescriptorInput(t, DataGeneric, []byte{0xfe, 0xed})); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tx.DeleteObject(1); err != nil {
+				t.Fatal(err)
+			}
+
+			b.writes, b.failWrite = 0, tt.failWrite
+
+			if got, want := tx.Commit(), errTestWrite; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			// The image must reflect its state prior to the commit.
+			if got, want := f.DescriptorsFree(), f.DescriptorsTotal()-1; got != want {
+				t.Errorf("got %v free descriptors, want %v", got, want)
+			}
+
+			if _, err := f.GetDescriptor(WithID(1)); err != nil {
+				t.Error(err)
+			}
+
+			if err := tx.Rollback(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := b.Bytes(), before; !bytes.Equal(got, want) {
+				t.Error("image modified after failed commit and rollback")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// getCheck returns a command that checks a SIF image for structural problems.
+func (c *command) getCheck() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check <sif_path>",
+		Short: "Check image for structural problems",
+		Long: `Check a SIF image for structural problems that are not detected when the
+image is loaded.
+
+The global header and object descriptors are checked for consistency with each
+other and with the size of the image. The digests of OCI blobs are verified, and
+JSON objects and SBOMs are parsed. Each problem found is displayed along with its
+severity. The command fails if any problem of error severity is found.`,
+		Example: c.opts.rootPath + " check image.sif",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.initApp,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return c.app.Check(args[0])
+		},
+		DisableFlagsInUseLine: true,
+	}
+}
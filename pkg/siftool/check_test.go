@@ -0,0 +1,37 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_command_getCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		opts commandOpts
+		path string
+	}{
+		{
+			name: "OneGroup",
+			path: filepath.Join(corpus, "one-group.sif"),
+		},
+		{
+			name: "TwoGroupsSignedPGP",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{opts: tt.opts}
+
+			cmd := c.getCheck()
+
+			runCommand(t, cmd, []string{tt.path}, nil)
+		})
+	}
+}
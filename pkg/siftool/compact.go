@@ -0,0 +1,27 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// getCompact returns a command that compacts a SIF.
+func (c *command) getCompact() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compact <sif_path>",
+		Short: "Compact SIF image",
+		Long: "Compact a SIF image, relocating data objects to eliminate unused space such as that left " +
+			"behind by deleted objects.",
+		Example: c.opts.rootPath + " compact image.sif",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.initApp,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return c.app.Compact(args[0])
+		},
+		DisableFlagsInUseLine: true,
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"testing"
+)
+
+func Test_command_getCompact(t *testing.T) {
+	tests := []struct {
+		name string
+		opts commandOpts
+	}{
+		{
+			name: "OK",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{opts: tt.opts}
+
+			cmd := c.getCompact()
+
+			runCommand(t, cmd, []string{makeTestSIF(t, true)}, nil)
+		})
+	}
+}
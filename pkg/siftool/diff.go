@@ -0,0 +1,51 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// getDiffExamples returns diff command examples based on rootPath.
+func getDiffExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " diff old.sif new.sif",
+		rootPath + " diff --content old.sif new.sif",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getDiff returns a command that displays the differences between two SIF images.
+func (c *command) getDiff() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [flags] <sif_path_a> <sif_path_b>",
+		Short: "Display differences between two images",
+		Long: `Display the differences between two SIF images, such as two builds of the same
+container.
+
+Differences in the global header are displayed first. Data objects are then
+matched between the images by data type, name, group and content digest, and
+objects that were added, removed, modified or moved are displayed. An object is
+modified if its name, size, metadata or content differs, and moved if only its
+ID, group, link or offset differs.
+
+With --content, a line-oriented diff is also displayed for modified definition
+files, environment variables and labels.`,
+		Example: getDiffExamples(c.opts.rootPath),
+		Args:    cobra.ExactArgs(2),
+		PreRunE: c.initApp,
+	}
+
+	content := cmd.Flags().Bool("content", false, "display content differences of text objects")
+
+	cmd.RunE = func(_ *cobra.Command, args []string) error {
+		return c.app.Diff(args[0], args[1], *content)
+	}
+
+	return cmd
+}
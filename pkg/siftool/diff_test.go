@@ -0,0 +1,47 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func Test_command_getDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  commandOpts
+		flags []string
+		pathA string
+		pathB string
+	}{
+		{
+			name:  "Identical",
+			pathA: filepath.Join(corpus, "one-group.sif"),
+			pathB: filepath.Join(corpus, "one-group.sif"),
+		},
+		{
+			name:  "OneGroupSigned",
+			pathA: filepath.Join(corpus, "one-group.sif"),
+			pathB: filepath.Join(corpus, "one-group-signed-pgp.sif"),
+		},
+		{
+			name:  "Content",
+			flags: []string{"--content"},
+			pathA: filepath.Join(corpus, "two-groups.sif"),
+			pathB: filepath.Join(corpus, "one-group.sif"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{opts: tt.opts}
+
+			cmd := c.getDiff()
+
+			runCommand(t, cmd, append(tt.flags, tt.pathA, tt.pathB), nil)
+		})
+	}
+}
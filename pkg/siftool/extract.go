@@ -0,0 +1,68 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// getExtractExamples returns extract command examples based on rootPath.
+func getExtractExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " extract image.sif 1 rootfs",
+		rootPath + " extract --primary image.sif rootfs",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getExtract returns a command that extracts the file system in a partition from a SIF image.
+func (c *command) getExtract() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extract [flags] <sif_path> <id|--primary> <dir>",
+		Short: "Extract partition contents",
+		Long: `Extract the contents of a partition in a SIF image to a directory.
+
+The partition to extract is specified by object ID, or using --primary to select
+the primary system partition. Only squashfs partitions are supported.
+
+Permissions, symbolic links, hard links, extended attributes and modification
+times are preserved. Ownership is preserved when run as root. Entries that would
+be extracted outside of the destination directory are refused.`,
+		Example: getExtractExamples(c.opts.rootPath),
+		PreRunE: c.initApp,
+	}
+
+	primary := cmd.Flags().Bool("primary", false, "extract the primary system partition")
+
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if *primary {
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+		return cobra.ExactArgs(3)(cmd, args)
+	}
+
+	cmd.RunE = func(_ *cobra.Command, args []string) error {
+		fn := sif.WithPartitionType(sif.PartPrimSys)
+
+		if !*primary {
+			id, err := strconv.ParseUint(args[1], 10, 32)
+			if err != nil {
+				return fmt.Errorf("while converting id: %w", err)
+			}
+
+			fn = sif.WithID(uint32(id))
+		}
+
+		return c.app.Extract(args[0], fn, args[len(args)-1])
+	}
+
+	return cmd
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func Test_command_getExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name: "Primary",
+			args: []string{"--primary", filepath.Join(corpus, "one-group.sif")},
+		},
+		{
+			name: "ID",
+			args: []string{filepath.Join(corpus, "one-group.sif"), "2"},
+		},
+		{
+			name:    "InvalidID",
+			args:    []string{filepath.Join(corpus, "one-group.sif"), "two"},
+			wantErr: strconv.ErrSyntax,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{}
+
+			cmd := c.getExtract()
+
+			runCommand(t, cmd, append(tt.args, filepath.Join(t.TempDir(), "rootfs")), tt.wantErr)
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2018, Divya Cote <divya.cote@gmail.com> All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
@@ -14,16 +14,20 @@ import (
 
 // getHeader returns a command that displays the global SIF header.
 func (c *command) getHeader() *cobra.Command {
-	return &cobra.Command{
-		Use:     "header <sif_path>",
-		Short:   "Display global header",
-		Long:    "Display global header from a SIF image.",
-		Example: c.opts.rootPath + " header image.sif",
+	cmd := &cobra.Command{
+		Use:   "header [flags] <sif_path>",
+		Short: "Display global header",
+		Long: "Display global header from a SIF image. For multi-architecture images, the\n" +
+			"primary system partition for the host architecture is identified.\n\n" + outputSchemaNote,
+		Example: c.opts.rootPath + " header image.sif\n" + c.opts.rootPath + " header --output json image.sif",
 		Args:    cobra.ExactArgs(1),
 		PreRunE: c.initApp,
 		RunE: func(_ *cobra.Command, args []string) error {
 			return c.app.Header(args[0])
 		},
-		DisableFlagsInUseLine: true,
 	}
+
+	addOutputFlag(cmd)
+
+	return cmd
 }
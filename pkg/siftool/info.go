@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2018, Divya Cote <divya.cote@gmail.com> All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
@@ -18,11 +18,11 @@ import (
 // getInfo returns a command that displays detailed information of an object descriptor from a SIF
 // image.
 func (c *command) getInfo() *cobra.Command {
-	return &cobra.Command{
-		Use:     "info <id> <sif_path>",
+	cmd := &cobra.Command{
+		Use:     "info [flags] <id> <sif_path>",
 		Short:   "Display data object info",
-		Long:    "Display info about a data object from a SIF image.",
-		Example: c.opts.rootPath + " info 1 image.sif",
+		Long:    "Display info about a data object from a SIF image, including any annotations.\n\n" + outputSchemaNote,
+		Example: c.opts.rootPath + " info 1 image.sif\n" + c.opts.rootPath + " info --output json 1 image.sif",
 		Args:    cobra.ExactArgs(2),
 		PreRunE: c.initApp,
 		RunE: func(_ *cobra.Command, args []string) error {
@@ -33,6 +33,9 @@ func (c *command) getInfo() *cobra.Command {
 
 			return c.app.Info(args[1], uint32(id))
 		},
-		DisableFlagsInUseLine: true,
 	}
+
+	addOutputFlag(cmd)
+
+	return cmd
 }
@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2022, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -12,10 +12,12 @@ import (
 
 func Test_command_getInfo(t *testing.T) {
 	tests := []struct {
-		name string
-		opts commandOpts
-		id   string
-		path string
+		name    string
+		opts    commandOpts
+		id      string
+		flags   []string
+		path    string
+		wantErr error
 	}{
 		{
 			name: "One",
@@ -32,6 +34,25 @@ func Test_command_getInfo(t *testing.T) {
 			id:   "3",
 			path: filepath.Join(corpus, "one-group-signed-pgp.sif"),
 		},
+		{
+			name:  "ThreeJSON",
+			id:    "3",
+			flags: []string{"--output", "json"},
+			path:  filepath.Join(corpus, "one-group-signed-pgp.sif"),
+		},
+		{
+			name:  "ThreeYAML",
+			id:    "3",
+			flags: []string{"-o", "yaml"},
+			path:  filepath.Join(corpus, "one-group-signed-pgp.sif"),
+		},
+		{
+			name:    "InvalidOutput",
+			id:      "1",
+			flags:   []string{"--output", "xml"},
+			path:    filepath.Join(corpus, "one-group-signed-pgp.sif"),
+			wantErr: errInvalidOutputFormat,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -39,7 +60,7 @@ func Test_command_getInfo(t *testing.T) {
 
 			cmd := c.getInfo()
 
-			runCommand(t, cmd, []string{tt.id, tt.path}, nil)
+			runCommand(t, cmd, append(tt.flags, tt.id, tt.path), tt.wantErr)
 		})
 	}
 }
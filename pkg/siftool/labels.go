@@ -0,0 +1,97 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var errInvalidLabel = errors.New("label must be of the form key=value")
+
+// getLabelsSetExamples returns labels set command examples based on rootPath.
+func getLabelsSetExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " labels set image.sif org.opencontainers.image.version=1.0",
+		rootPath + " labels set --unset maintainer image.sif",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getLabelsGet returns a command that displays the labels of a container in a SIF image.
+func (c *command) getLabelsGet() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get [flags] <sif_path>",
+		Short: "Display container labels",
+		Long: "Display the labels of the container in a SIF image. If the image contains more than\n" +
+			"one labels object, the most recently created object is used.\n\n" + outputSchemaNote,
+		Example: c.opts.rootPath + " labels get image.sif\n" + c.opts.rootPath + " labels get --output json image.sif",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.initApp,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return c.app.Labels(args[0])
+		},
+	}
+
+	addOutputFlag(cmd)
+
+	return cmd
+}
+
+// getLabelsSet returns a command that updates the labels of a container in a SIF image.
+func (c *command) getLabelsSet() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [flags] <sif_path> [key=value...]",
+		Short: "Update container labels",
+		Long: `Update the labels of the container in a SIF image. Each key=value argument adds
+or updates a label, and each --unset flag removes a label. Other labels are
+retained.
+
+If the image contains a labels object, the data of the most recently created
+labels object is replaced. Otherwise, a new labels object is added. Any
+signatures of the object group containing the labels are invalidated.`,
+		Example: getLabelsSetExamples(c.opts.rootPath),
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: c.initApp,
+	}
+
+	unset := cmd.Flags().StringSlice("unset", nil, "label to remove")
+
+	cmd.RunE = func(_ *cobra.Command, args []string) error {
+		set := make(map[string]string)
+		for _, arg := range args[1:] {
+			k, v, ok := strings.Cut(arg, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("%w: %q", errInvalidLabel, arg)
+			}
+			set[k] = v
+		}
+
+		return c.app.SetLabels(args[0], set, *unset)
+	}
+
+	return cmd
+}
+
+// getLabels returns a command that groups commands that operate on container labels.
+func (c *command) getLabels() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "labels",
+		Short: "Manage container labels",
+		Long:  "Display or update the labels of the container in a SIF image.",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		c.getLabelsGet(),
+		c.getLabelsSet(),
+	)
+
+	return cmd
+}
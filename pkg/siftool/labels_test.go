@@ -0,0 +1,87 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"testing"
+
+	"github.com/sylabs/sif/v2/internal/app/siftool"
+)
+
+// makeTestSIFWithLabels returns the path to a SIF containing labels.
+func makeTestSIFWithLabels(t *testing.T) string {
+	t.Helper()
+
+	path := makeTestSIF(t, false)
+
+	app, err := siftool.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.SetLabels(path, map[string]string{"a": "1", "b": "2"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func Test_command_getLabelsGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name: "Text",
+			args: []string{makeTestSIFWithLabels(t)},
+		},
+		{
+			name: "JSON",
+			args: []string{"--output", "json", makeTestSIFWithLabels(t)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{}
+
+			cmd := c.getLabelsGet()
+
+			runCommand(t, cmd, tt.args, tt.wantErr)
+		})
+	}
+}
+
+func Test_command_getLabelsSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name: "Add",
+			args: []string{makeTestSIF(t, true), "a=1", "b="},
+		},
+		{
+			name: "Update",
+			args: []string{makeTestSIFWithLabels(t), "a=2", "--unset", "b"},
+		},
+		{
+			name:    "InvalidLabel",
+			args:    []string{makeTestSIF(t, true), "a"},
+			wantErr: errInvalidLabel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{}
+
+			cmd := c.getLabelsSet()
+
+			runCommand(t, cmd, tt.args, tt.wantErr)
+		})
+	}
+}
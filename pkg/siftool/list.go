@@ -1,4 +1,4 @@
-// Copyright (c) 2018-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2018-2026, Sylabs Inc. All rights reserved.
 // Copyright (c) 2018, Divya Cote <divya.cote@gmail.com> All rights reserved.
 // Copyright (c) 2017, SingularityWare, LLC. All rights reserved.
 // Copyright (c) 2017, Yannick Cote <yhcote@gmail.com> All rights reserved.
@@ -14,16 +14,20 @@ import (
 
 // getList returns a command that lists object descriptors from a SIF image.
 func (c *command) getList() *cobra.Command {
-	return &cobra.Command{
-		Use:     "list <sif_path>",
-		Short:   "List data objects",
-		Long:    "List data objects from a SIF image.",
-		Example: c.opts.rootPath + " list image.sif",
+	cmd := &cobra.Command{
+		Use:   "list [flags] <sif_path>",
+		Short: "List data objects",
+		Long: "List data objects from a SIF image. For multi-architecture images, the primary\n" +
+			"system partition for the host architecture is marked [host].\n\n" + outputSchemaNote,
+		Example: c.opts.rootPath + " list image.sif\n" + c.opts.rootPath + " list --output yaml image.sif",
 		Args:    cobra.ExactArgs(1),
 		PreRunE: c.initApp,
 		RunE: func(_ *cobra.Command, args []string) error {
 			return c.app.List(args[0])
 		},
-		DisableFlagsInUseLine: true,
 	}
+
+	addOutputFlag(cmd)
+
+	return cmd
 }
@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2022, Sylabs Inc. All rights reserved.
+// Copyright (c) 2021-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -13,9 +13,11 @@ import (
 
 func Test_command_getList(t *testing.T) {
 	tests := []struct {
-		name string
-		opts commandOpts
-		path string
+		name    string
+		opts    commandOpts
+		flags   []string
+		path    string
+		wantErr error
 	}{
 		{
 			name: "Empty",
@@ -61,6 +63,22 @@ func Test_command_getList(t *testing.T) {
 			name: "TwoGroupsSignedPGP",
 			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
 		},
+		{
+			name:  "OneGroupJSON",
+			flags: []string{"--output", "json"},
+			path:  filepath.Join(corpus, "one-group.sif"),
+		},
+		{
+			name:  "OneGroupYAML",
+			flags: []string{"-o", "yaml"},
+			path:  filepath.Join(corpus, "one-group.sif"),
+		},
+		{
+			name:    "InvalidOutput",
+			flags:   []string{"--output", "xml"},
+			path:    filepath.Join(corpus, "one-group.sif"),
+			wantErr: errInvalidOutputFormat,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -68,7 +86,7 @@ func Test_command_getList(t *testing.T) {
 
 			cmd := c.getList()
 
-			runCommand(t, cmd, []string{tt.path}, nil)
+			runCommand(t, cmd, append(tt.flags, tt.path), tt.wantErr)
 		})
 	}
 }
@@ -0,0 +1,194 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/spf13/cobra"
+	"github.com/sylabs/sif/v2/pkg/sif"
+	"github.com/sylabs/sif/v2/pkg/user" //nolint:staticcheck // No replacement is available yet.
+)
+
+var (
+	errObjectSelectionConflict = errors.New("--id and --parttype are mutually exclusive")
+	errNotRSAKey               = errors.New("key is not an RSA private key")
+)
+
+// readDecryptionKey reads a PEM-encoded RSA private key from the file at path.
+func readDecryptionKey(path string) (*rsa.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pk, err := cryptoutils.UnmarshalPEMToPrivateKey(b, cryptoutils.SkipPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key: %w", err)
+	}
+
+	key, ok := pk.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errNotRSAKey, pk)
+	}
+
+	return key, nil
+}
+
+// isRawPartition reports whether the partition of the SIF image at path with object ID id, or of
+// partition type pt, is a raw partition. If neither id nor pt is specified, the primary system
+// partition for the host architecture is considered, as selected by user.Mount.
+func isRawPartition(path string, id uint32, pt sif.PartType) (bool, error) {
+	f, err := sif.LoadContainerFromPath(path, sif.OptLoadWithFlag(os.O_RDONLY))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = f.UnloadContainer() }()
+
+	var d sif.Descriptor
+	switch {
+	case id != 0:
+		d, err = f.GetDescriptor(sif.WithID(id))
+	case pt != 0:
+		d, err = f.GetDescriptor(sif.WithPartitionType(pt))
+	default:
+		if d, err = f.GetDescriptor(sif.WithPrimaryPartitionForArch(runtime.GOARCH)); err != nil {
+			d, err = f.GetDescriptor(sif.WithPartitionType(sif.PartPrimSys))
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	fs, _, _, err := d.PartitionMetadata()
+	if err != nil {
+		return false, err
+	}
+
+	return fs == sif.FsRaw, nil
+}
+
+// getMountExamples returns mount command examples based on rootPath.
+func getMountExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " mount image.sif rootfs",
+		rootPath + " mount --id 3 image.sif data",
+		rootPath + " mount --foreground image.sif rootfs",
+		rootPath + " mount --key private.pem encrypted.sif rootfs",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getMount returns a command that mounts a partition of a SIF image.
+func (c *command) getMount() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mount [flags] <sif_path> <mount_path>",
+		Short: "Mount partition",
+		Long: `Mount a partition of a SIF image using FUSE.
+
+By default, the primary system partition for the host architecture is mounted.
+To mount a different partition, use --id or --parttype. Squashfs partitions are
+mounted using squashfuse, and ext3 partitions using fuse2fs. Raw partitions are
+exposed as a single read-only file named partition.raw, without requiring a
+loop device. Encrypted squashfs partitions are decrypted using the RSA private
+key specified by --key.
+
+With --foreground, the command waits until interrupted, and then unmounts the
+partition using fusermount. Otherwise, unmount the partition using the unmount
+command. Raw partitions, and the decrypted contents of encrypted partitions, are
+served by the command itself, so mounting them implies --foreground.`,
+		Example: getMountExamples(c.opts.rootPath),
+		Args:    cobra.ExactArgs(2),
+		PreRunE: c.initApp,
+	}
+
+	id := cmd.Flags().Uint32("id", 0, "mount partition with specified object ID")
+	partType := cmd.Flags().Int32("parttype", 0, `mount partition of specified type:
+  1-System,    2-PrimSys,   3-Data,
+  4-Overlay`)
+	keyPath := cmd.Flags().String("key", "", "path to PEM-encoded RSA private key used to decrypt partition")
+	squashfusePath := cmd.Flags().String("squashfuse-path", "", "path to squashfuse binary")
+	fuse2fsPath := cmd.Flags().String("fuse2fs-path", "", "path to fuse2fs binary")
+	fusermountPath := cmd.Flags().String("fusermount-path", "", "path to fusermount binary")
+	foreground := cmd.Flags().Bool("foreground", false, "wait until interrupted, then unmount")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var opts []user.MountOpt
+
+		switch {
+		case *id != 0 && *partType != 0:
+			return errObjectSelectionConflict
+		case *id != 0:
+			opts = append(opts, user.OptMountObjectID(*id))
+		case *partType != 0:
+			opts = append(opts, user.OptMountPartitionType(sif.PartType(*partType)))
+		}
+
+		if *keyPath != "" {
+			key, err := readDecryptionKey(*keyPath)
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, user.OptMountDecryptionKey(key))
+		}
+
+		if *squashfusePath != "" {
+			opts = append(opts, user.OptMountSquashfusePath(*squashfusePath))
+		}
+
+		if *fuse2fsPath != "" {
+			opts = append(opts, user.OptMountFuse2fsPath(*fuse2fsPath))
+		}
+
+		if *fusermountPath != "" {
+			opts = append(opts, user.OptMountFusermountPath(*fusermountPath))
+		}
+
+		if err := c.app.Mount(cmd.Context(), args[0], args[1], opts...); err != nil {
+			return err
+		}
+
+		if !*foreground && *keyPath == "" {
+			raw, err := isRawPartition(args[0], *id, sif.PartType(*partType))
+			if err != nil {
+				return err
+			}
+
+			if !raw {
+				return nil
+			}
+		}
+
+		// Wait until interrupted. The signal handler is installed only once mounted, so that an
+		// interrupted mount terminates the command as usual.
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Fprintf(cmd.ErrOrStderr(), "Mounted %v at %v. Press Ctrl+C to unmount.\n", args[0], args[1])
+
+		<-ctx.Done()
+
+		var uopts []user.UnmountOpt
+
+		if *fusermountPath != "" {
+			uopts = append(uopts, user.OptUnmountFusermountPath(*fusermountPath))
+		}
+
+		return c.app.Unmount(context.WithoutCancel(ctx), args[1], uopts...)
+	}
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sylabs/sif/v2/internal/pkg/exectest"
+)
+
+func Test_command_getMount(t *testing.T) {
+	squashfusePath := exectest.Script(t, "squashfuse", `echo "$1" "$2"`)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name: "Default",
+			args: []string{"--squashfuse-path", squashfusePath},
+		},
+		{
+			name: "ID",
+			args: []string{"--squashfuse-path", squashfusePath, "--id", "2"},
+		},
+		{
+			name: "PartType",
+			args: []string{"--squashfuse-path", squashfusePath, "--parttype", "2"},
+		},
+		{
+			name:    "IDAndPartType",
+			args:    []string{"--squashfuse-path", squashfusePath, "--id", "2", "--parttype", "2"},
+			wantErr: errObjectSelectionConflict,
+		},
+		{
+			name:    "NotRSAKey",
+			args:    []string{"--key", filepath.Join("..", "..", "test", "keys", "ed25519-private.pem")},
+			wantErr: errNotRSAKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{}
+
+			cmd := c.getMount()
+
+			args := append(tt.args, filepath.Join(corpus, "one-group.sif"), t.TempDir())
+
+			runCommand(t, cmd, args, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/sylabs/sif/v2/internal/app/siftool"
+)
+
+// outputFlag is the name of the flag that selects the output format.
+const outputFlag = "output"
+
+// outputSchemaNote describes the structured output formats in command help.
+const outputSchemaNote = `With --output json or --output yaml, output is written according to a
+versioned schema, indicated by the schemaVersion field. The current schema
+version is 1. Fields may be added without changing the schema version.`
+
+var errInvalidOutputFormat = errors.New("invalid output format")
+
+// outputFormatNames maps output format names to the corresponding output format.
+var outputFormatNames = map[string]siftool.OutputFormat{
+	"text": siftool.OutputFormatText,
+	"json": siftool.OutputFormatJSON,
+	"yaml": siftool.OutputFormatYAML,
+}
+
+// outputFormatValue implements pflag.Value for an output format.
+type outputFormatValue struct {
+	name   string
+	format siftool.OutputFormat
+}
+
+func (v *outputFormatValue) String() string { return v.name }
+
+func (v *outputFormatValue) Set(s string) error {
+	f, ok := outputFormatNames[s]
+	if !ok {
+		return fmt.Errorf("%w: %v", errInvalidOutputFormat, s)
+	}
+
+	v.name, v.format = s, f
+	return nil
+}
+
+func (v *outputFormatValue) Type() string { return "format" }
+
+// addOutputFlag adds a flag to cmd that selects the output format. The selected format is applied
+// to the app by initApp.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().VarP(&outputFormatValue{name: "text"}, outputFlag, "o", "output format (text, json or yaml)")
+}
+
+// getOutputFormat returns the output format selected using the flags of cmd.
+func getOutputFormat(cmd *cobra.Command) siftool.OutputFormat {
+	if f := cmd.Flags().Lookup(outputFlag); f != nil {
+		if v, ok := f.Value.(*outputFormatValue); ok {
+			return v.format
+		}
+	}
+	return siftool.OutputFormatText
+}
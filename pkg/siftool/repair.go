@@ -0,0 +1,51 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// getRepairExamples returns repair command examples based on rootPath.
+func getRepairExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " repair --dry-run image.sif",
+		rootPath + " repair image.sif",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getRepair returns a command that repairs structural problems in a SIF image.
+func (c *command) getRepair() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repair [flags] <sif_path>",
+		Short: "Repair image structural problems",
+		Long: `Repair recoverable structural problems in a SIF image, such as those caused by
+an incomplete upload or download.
+
+Descriptors of objects extending beyond the end of the image are cleared, the
+global header is updated to be consistent with the remaining objects, data
+following the data section is truncated, and OCI blob digests are recomputed.
+Each repair is displayed. With --dry-run, repairs are displayed, but the image
+is not modified.
+
+Problems that cannot be repaired are left unchanged. Use the check command to
+identify them.`,
+		Example: getRepairExamples(c.opts.rootPath),
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.initApp,
+	}
+
+	dryRun := cmd.Flags().Bool("dry-run", false, "display repairs without modifying the image")
+
+	cmd.RunE = func(_ *cobra.Command, args []string) error {
+		return c.app.Repair(args[0], *dryRun)
+	}
+
+	return cmd
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_command_getRepair(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     commandOpts
+		flags    []string
+		truncate bool
+	}{
+		{
+			name: "OK",
+		},
+		{
+			name:     "Truncated",
+			truncate: true,
+		},
+		{
+			name:     "TruncatedDryRun",
+			flags:    []string{"--dry-run"},
+			truncate: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{opts: tt.opts}
+
+			cmd := c.getRepair()
+
+			path := makeTestSIF(t, true)
+
+			if tt.truncate {
+				fi, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.Truncate(path, fi.Size()-1); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			runCommand(t, cmd, append(tt.flags, path), nil)
+		})
+	}
+}
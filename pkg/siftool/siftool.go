@@ -24,6 +24,7 @@ func (c *command) initApp(cmd *cobra.Command, _ []string) error {
 	app, err := siftool.New(
 		siftool.OptAppOutput(cmd.OutOrStdout()),
 		siftool.OptAppError(cmd.ErrOrStderr()),
+		siftool.OptAppOutputFormat(getOutputFormat(cmd)),
 	)
 	c.app = app
 
@@ -52,6 +53,9 @@ func OptWithExperimental(b bool) CommandOpt {
 // A set of commands are provided to display elements such as the SIF global
 // header, the data object descriptors and to dump data objects. It is also
 // possible to modify a SIF file via this tool via the add/del commands.
+//
+// Experimental commands, such as the mount/unmount commands, are added only if enabled using
+// OptWithExperimental.
 func AddCommands(cmd *cobra.Command, opts ...CommandOpt) error {
 	c := command{
 		opts: commandOpts{
@@ -69,12 +73,27 @@ func AddCommands(cmd *cobra.Command, opts ...CommandOpt) error {
 		c.getHeader(),
 		c.getList(),
 		c.getInfo(),
+		c.getCheck(),
+		c.getDiff(),
 		c.getDump(),
+		c.getExtract(),
 		c.getNew(),
 		c.getAdd(),
 		c.getDel(),
 		c.getSetPrim(),
+		c.getLabels(),
+		c.getCompact(),
+		c.getRepair(),
+		c.getSign(),
+		c.getVerify(),
 	)
 
+	if c.opts.experimental {
+		cmd.AddCommand(
+			c.getMount(),
+			c.getUnmount(),
+		)
+	}
+
 	return nil
 }
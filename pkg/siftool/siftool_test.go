@@ -84,10 +84,22 @@ func TestAddCommands(t *testing.T) {
 			name: "Add",
 			args: []string{"help", "add"},
 		},
+		{
+			name: "Check",
+			args: []string{"help", "check"},
+		},
+		{
+			name: "Compact",
+			args: []string{"help", "compact"},
+		},
 		{
 			name: "Del",
 			args: []string{"help", "del"},
 		},
+		{
+			name: "Diff",
+			args: []string{"help", "diff"},
+		},
 		{
 			name: "Dump",
 			args: []string{"help", "dump"},
@@ -104,14 +116,48 @@ func TestAddCommands(t *testing.T) {
 			name: "List",
 			args: []string{"help", "list"},
 		},
+		{
+			name: "Labels",
+			args: []string{"help", "labels"},
+		},
+		{
+			name: "LabelsGet",
+			args: []string{"help", "labels", "get"},
+		},
+		{
+			name: "LabelsSet",
+			args: []string{"help", "labels", "set"},
+		},
+		{
+			name: "Mount",
+			opts: []CommandOpt{OptWithExperimental(true)},
+			args: []string{"help", "mount"},
+		},
 		{
 			name: "New",
 			args: []string{"help", "new"},
 		},
+		{
+			name: "Repair",
+			args: []string{"help", "repair"},
+		},
 		{
 			name: "SetPrim",
 			args: []string{"help", "setprim"},
 		},
+		{
+			name: "Sign",
+			args: []string{"help", "sign"},
+		},
+		{
+			name: "Unmount",
+			opts: []CommandOpt{OptWithExperimental(true)},
+			args: []string{"help", "unmount"},
+		},
+		{
+			name: "Verify",
+			args: []string{"help", "verify"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
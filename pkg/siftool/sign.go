@@ -0,0 +1,146 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/spf13/cobra"
+	"github.com/sylabs/sif/v2/pkg/integrity"
+)
+
+var (
+	errKeyMaterialRequired = errors.New("one of --key or --pgp-key must be specified")
+	errKeyMaterialConflict = errors.New("--key and --pgp-key are mutually exclusive")
+	errNoEntities          = errors.New("no entities found in key ring")
+	errIDOutOfRange        = errors.New("ID out of range")
+)
+
+// toIDs converts vs to a slice of object/group IDs.
+func toIDs(vs []uint) ([]uint32, error) {
+	ids := make([]uint32, 0, len(vs))
+
+	for _, v := range vs {
+		if v > math.MaxUint32 {
+			return nil, fmt.Errorf("%w: %v", errIDOutOfRange, v)
+		}
+		ids = append(ids, uint32(v))
+	}
+
+	return ids, nil
+}
+
+// readKeyRing reads an armored PGP key ring from the file at path.
+func readKeyRing(path string) (openpgp.EntityList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	el, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key ring: %w", err)
+	}
+
+	if len(el) == 0 {
+		return nil, errNoEntities
+	}
+
+	return el, nil
+}
+
+// getSignExamples returns sign command examples based on rootPath.
+func getSignExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " sign --key private.pem image.sif",
+		rootPath + " sign --pgp-key private.asc image.sif",
+		rootPath + " sign --key private.pem --group 1 image.sif",
+		rootPath + " sign --key private.pem --object 1 --object 2 image.sif",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getSign returns a command that adds digital signature(s) to a SIF image.
+func (c *command) getSign() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign [flags] <sif_path>",
+		Short: "Add digital signature(s)",
+		Long: `Add digital signature(s) to a SIF image.
+
+Key material must be supplied as a PEM-encoded private key (RSA, ECDSA or Ed25519)
+using --key, or as an armored PGP private key using --pgp-key.
+
+By default, one signature is added per object group. To sign specific object
+groups or objects, use --group and/or --object.`,
+		Example: getSignExamples(c.opts.rootPath),
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.initApp,
+	}
+
+	keyPath := cmd.Flags().String("key", "", "path to PEM-encoded private key")
+	pgpKeyPath := cmd.Flags().String("pgp-key", "", "path to armored PGP private key")
+	groupIDs := cmd.Flags().UintSlice("group", nil, "sign object group with specified ID (may be repeated)")
+	objectIDs := cmd.Flags().UintSlice("object", nil, "sign object with specified ID (may be repeated)")
+
+	cmd.RunE = func(_ *cobra.Command, args []string) error {
+		var opts []integrity.SignerOpt
+
+		switch {
+		case *keyPath != "" && *pgpKeyPath != "":
+			return errKeyMaterialConflict
+
+		case *keyPath != "":
+			s, err := signature.LoadSignerFromPEMFile(*keyPath, crypto.SHA256, cryptoutils.SkipPassword)
+			if err != nil {
+				return fmt.Errorf("failed to load key: %w", err)
+			}
+
+			opts = append(opts, integrity.OptSignWithSigner(s))
+
+		case *pgpKeyPath != "":
+			el, err := readKeyRing(*pgpKeyPath)
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, integrity.OptSignWithEntity(el[0]))
+
+		default:
+			return errKeyMaterialRequired
+		}
+
+		groups, err := toIDs(*groupIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range groups {
+			opts = append(opts, integrity.OptSignGroup(id))
+		}
+
+		objects, err := toIDs(*objectIDs)
+		if err != nil {
+			return err
+		}
+
+		if len(objects) > 0 {
+			opts = append(opts, integrity.OptSignObjects(objects...))
+		}
+
+		return c.app.Sign(args[0], opts...)
+	}
+
+	return cmd
+}
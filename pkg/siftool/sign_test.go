@@ -0,0 +1,68 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+var keys = filepath.Join("..", "..", "test", "keys")
+
+func Test_command_getSign(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    commandOpts
+		flags   []string
+		wantErr error
+	}{
+		{
+			name:    "NoKeyMaterial",
+			wantErr: errKeyMaterialRequired,
+		},
+		{
+			name: "KeyMaterialConflict",
+			flags: []string{
+				"--key", filepath.Join(keys, "ed25519-private.pem"),
+				"--pgp-key", filepath.Join(keys, "private.asc"),
+			},
+			wantErr: errKeyMaterialConflict,
+		},
+		{
+			name: "ED25519",
+			flags: []string{
+				"--key", filepath.Join(keys, "ed25519-private.pem"),
+			},
+		},
+		{
+			name: "ECDSAGroup",
+			flags: []string{
+				"--key", filepath.Join(keys, "ecdsa-private.pem"),
+				"--group", "1",
+			},
+		},
+		{
+			name: "PGPObject",
+			flags: []string{
+				"--pgp-key", filepath.Join(keys, "private.asc"),
+				"--object", "1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{opts: tt.opts}
+
+			cmd := c.getSign()
+
+			args := make([]string, 0, 1+len(tt.flags))
+			args = append(args, makeTestSIF(t, true))
+			args = append(args, tt.flags...)
+
+			runCommand(t, cmd, args, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/sylabs/sif/v2/pkg/user" //nolint:staticcheck // No replacement is available yet.
+)
+
+// getUnmountExamples returns unmount command examples based on rootPath.
+func getUnmountExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " unmount rootfs",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getUnmount returns a command that unmounts a partition mounted by the mount command.
+func (c *command) getUnmount() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "unmount [flags] <mount_path>",
+		Short:   "Unmount partition",
+		Long:    "Unmount a partition of a SIF image, previously mounted using the mount command.",
+		Example: getUnmountExamples(c.opts.rootPath),
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.initApp,
+	}
+
+	fusermountPath := cmd.Flags().String("fusermount-path", "", "path to fusermount binary")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var opts []user.UnmountOpt
+
+		if *fusermountPath != "" {
+			opts = append(opts, user.OptUnmountFusermountPath(*fusermountPath))
+		}
+
+		return c.app.Unmount(cmd.Context(), args[0], opts...)
+	}
+
+	return cmd
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"testing"
+
+	"github.com/sylabs/sif/v2/internal/pkg/exectest"
+)
+
+func Test_command_getUnmount(t *testing.T) {
+	fusermountPath := exectest.Script(t, "fusermount", `echo "$1"`)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{
+			name: "Default",
+			args: []string{"--fusermount-path", fusermountPath},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{}
+
+			cmd := c.getUnmount()
+
+			runCommand(t, cmd, append(tt.args, t.TempDir()), tt.wantErr)
+		})
+	}
+}
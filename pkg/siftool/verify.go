@@ -0,0 +1,113 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"crypto"
+	"fmt"
+	"strings"
+
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/spf13/cobra"
+	"github.com/sylabs/sif/v2/pkg/integrity"
+)
+
+// getVerifyExamples returns verify command examples based on rootPath.
+func getVerifyExamples(rootPath string) string {
+	examples := []string{
+		rootPath + " verify --key public.pem image.sif",
+		rootPath + " verify --pgp-key public.asc image.sif",
+		rootPath + " verify --key public.pem --group 1 image.sif",
+		rootPath + " verify --pgp-key public.asc --legacy-all image.sif",
+	}
+	return strings.Join(examples, "\n")
+}
+
+// getVerify returns a command that verifies digital signature(s) in a SIF image.
+func (c *command) getVerify() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [flags] <sif_path>",
+		Short: "Verify digital signature(s)",
+		Long: `Verify digital signature(s) in a SIF image.
+
+Key material must be supplied as one or more PEM-encoded public keys (RSA, ECDSA
+or Ed25519) using --key, and/or as an armored PGP key ring using --pgp-key.
+
+By default, non-legacy signatures are verified for all object groups. To verify
+specific object groups or objects, use --group and/or --object. To verify legacy
+signatures, use --legacy or --legacy-all.
+
+A report is displayed for each signature that is examined. If verification fails,
+a non-zero exit code is returned.`,
+		Example: getVerifyExamples(c.opts.rootPath),
+		Args:    cobra.ExactArgs(1),
+		PreRunE: c.initApp,
+	}
+
+	keyPaths := cmd.Flags().StringSlice("key", nil, "path to PEM-encoded public key (may be repeated)")
+	pgpKeyPath := cmd.Flags().String("pgp-key", "", "path to armored PGP key ring")
+	groupIDs := cmd.Flags().UintSlice("group", nil, "verify object group with specified ID (may be repeated)")
+	objectIDs := cmd.Flags().UintSlice("object", nil, "verify object with specified ID (may be repeated)")
+	legacy := cmd.Flags().Bool("legacy", false, "verify legacy signatures")
+	legacyAll := cmd.Flags().Bool("legacy-all", false, "verify legacy signatures of all non-signature objects")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var opts []integrity.VerifierOpt
+
+		if len(*keyPaths) == 0 && *pgpKeyPath == "" {
+			return errKeyMaterialRequired
+		}
+
+		for _, path := range *keyPaths {
+			v, err := signature.LoadVerifierFromPEMFile(path, crypto.SHA256)
+			if err != nil {
+				return fmt.Errorf("failed to load key: %w", err)
+			}
+
+			opts = append(opts, integrity.OptVerifyWithVerifier(v))
+		}
+
+		if *pgpKeyPath != "" {
+			el, err := readKeyRing(*pgpKeyPath)
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, integrity.OptVerifyWithKeyRing(el))
+		}
+
+		groups, err := toIDs(*groupIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range groups {
+			opts = append(opts, integrity.OptVerifyGroup(id))
+		}
+
+		objects, err := toIDs(*objectIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range objects {
+			opts = append(opts, integrity.OptVerifyObject(id))
+		}
+
+		if *legacyAll {
+			opts = append(opts, integrity.OptVerifyLegacyAll())
+		} else if *legacy {
+			opts = append(opts, integrity.OptVerifyLegacy())
+		}
+
+		// Verification failures are reported per signature, so usage is not helpful beyond here.
+		cmd.SilenceUsage = true
+
+		return c.app.Verify(args[0], opts...)
+	}
+
+	return cmd
+}
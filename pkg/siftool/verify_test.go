@@ -0,0 +1,90 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package siftool
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sylabs/sif/v2/pkg/integrity"
+)
+
+func Test_command_getVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    commandOpts
+		flags   []string
+		path    string
+		wantErr error
+	}{
+		{
+			name:    "NoKeyMaterial",
+			path:    filepath.Join(corpus, "one-group-signed-dsse.sif"),
+			wantErr: errKeyMaterialRequired,
+		},
+		{
+			name: "DSSE",
+			path: filepath.Join(corpus, "one-group-signed-dsse.sif"),
+			flags: []string{
+				"--key", filepath.Join(keys, "ed25519-public.pem"),
+				"--key", filepath.Join(keys, "rsa-public.pem"),
+			},
+		},
+		{
+			name: "DSSEWrongKey",
+			path: filepath.Join(corpus, "one-group-signed-dsse.sif"),
+			flags: []string{
+				"--key", filepath.Join(keys, "ecdsa-public.pem"),
+			},
+			wantErr: &integrity.SignatureNotValidError{},
+		},
+		{
+			name: "PGPGroup",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			flags: []string{
+				"--pgp-key", filepath.Join(keys, "private.asc"),
+				"--group", "1",
+			},
+		},
+		{
+			name: "PGPObject",
+			path: filepath.Join(corpus, "two-groups-signed-pgp.sif"),
+			flags: []string{
+				"--pgp-key", filepath.Join(keys, "private.asc"),
+				"--object", "3",
+			},
+		},
+		{
+			name: "PGPLegacy",
+			path: filepath.Join(corpus, "one-group-signed-legacy-group.sif"),
+			flags: []string{
+				"--pgp-key", filepath.Join(keys, "private.asc"),
+				"--legacy",
+			},
+		},
+		{
+			name: "PGPLegacyAll",
+			path: filepath.Join(corpus, "one-group-signed-legacy-all.sif"),
+			flags: []string{
+				"--pgp-key", filepath.Join(keys, "private.asc"),
+				"--legacy-all",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &command{opts: tt.opts}
+
+			cmd := c.getVerify()
+
+			args := make([]string, 0, 1+len(tt.flags))
+			args = append(args, tt.path)
+			args = append(args, tt.flags...)
+
+			runCommand(t, cmd, args, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// ErrUnsupportedCompression is the error returned when a filesystem uses an unsupported
+// compression algorithm.
+var ErrUnsupportedCompression = errors.New("unsupported compression algorithm")
+
+var (
+	errDecompressedSize = errors.New("decompressed data exceeds maximum size")
+	errDictionarySize   = errors.New("dictionary size exceeds maximum")
+	errInvalidXZ        = errors.New("invalid xz stream")
+	errInvalidLZ4       = errors.New("invalid lz4 block")
+)
+
+// maxDictSize is the maximum LZMA dictionary size accepted, which bounds the memory allocated to
+// decompress a block. Dictionaries larger than the maximum block size are of no benefit.
+const maxDictSize = 1 << maxBlockLog
+
+// decompressor decompresses blocks of data.
+type decompressor interface {
+	// decompress returns the decompressed contents of b, which must not exceed max bytes.
+	decompress(b []byte, maxSize int) ([]byte, error)
+}
+
+// readAll returns the contents of r, which must not exceed maxSize bytes.
+func readAll(r io.Reader, maxSize int) ([]byte, error) {
+	out, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) > maxSize {
+		return nil, errDecompressedSize
+	}
+	return out, nil
+}
+
+// gzipDecompressor decompresses zlib-compressed blocks.
+type gzipDecompressor struct{}
+
+func (gzipDecompressor) decompress(b []byte, maxSize int) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return readAll(zr, maxSize)
+}
+
+// lzmaDecompressor decompresses lzma-compressed blocks.
+type lzmaDecompressor struct{}
+
+func (lzmaDecompressor) decompress(b []byte, maxSize int) ([]byte, error) {
+	lr, err := lzma.ReaderConfig{DictCap: maxDictSize}.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return readAll(lr, maxSize)
+}
+
+// xzDictSize returns the dictionary size of the LZMA2 filter in the first block of the xz stream
+// in b, or zero if the stream contains no blocks.
+func xzDictSize(b []byte) (int64, error) {
+	const streamHeaderLen = 12
+
+	if len(b) < streamHeaderLen+2 {
+		return 0, errInvalidXZ
+	}
+	b = b[streamHeaderLen:]
+
+	// A zero header size indicates the start of the index, rather than a block.
+	if b[0] == 0 {
+		return 0, nil
+	}
+
+	n := (int(b[0]) + 1) * 4
+	if len(b) < n {
+		return 0, errInvalidXZ
+	}
+
+	flags := b[1]
+	r := bytes.NewReader(b[2:n])
+
+	// Skip compressed and uncompressed sizes, if present.
+	for _, bit := range []byte{0x40, 0x80} {
+		if flags&bit == 0 {
+			continue
+		}
+		if _, err := binary.ReadUvarint(r); err != nil {
+			return 0, errInvalidXZ
+		}
+	}
+
+	// The LZMA2 filter is the last filter in the chain.
+	var id uint64
+	var props []byte
+
+	for range int(flags&0x3) + 1 {
+		var err error
+		if id, err = binary.ReadUvarint(r); err != nil {
+			return 0, errInvalidXZ
+		}
+
+		size, err := binary.ReadUvarint(r)
+		if err != nil || size > uint64(r.Len()) {
+			return 0, errInvalidXZ
+		}
+
+		props = make([]byte, size)
+		if _, err := io.ReadFull(r, props); err != nil {
+			return 0, errInvalidXZ
+		}
+	}
+
+	if id != 0x21 || len(props) != 1 {
+		return 0, errInvalidXZ
+	}
+
+	return lzma.DecodeDictCap(props[0])
+}
+
+// xzDecompressor decompresses xz-compressed blocks.
+type xzDecompressor struct{}
+
+func (xzDecompressor) decompress(b []byte, maxSize int) ([]byte, error) {
+	// The xz package sizes the dictionary according to the stream, so check the size before
+	// decoding, to bound the memory allocated. Each squashfs block is compressed as a single xz
+	// block, so only the first block is checked.
+	n, err := xzDictSize(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if n > maxDictSize {
+		return nil, fmt.Errorf("%w: %v", errDictionarySize, n)
+	}
+
+	xr, err := xz.ReaderConfig{SingleStream: true}.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return readAll(xr, maxSize)
+}
+
+// lz4Decompressor decompresses blocks compressed using the lz4 block format.
+type lz4Decompressor struct{}
+
+// lz4Length returns the length encoded by nibble v, followed by any extension bytes at the start
+// of b, and the number of extension bytes consumed.
+func lz4Length(v byte, b []byte) (int, int, error) {
+	n := int(v)
+	if v != 0xf {
+		return n, 0, nil
+	}
+
+	for i, c := range b {
+		n += int(c)
+		if c != 0xff {
+			return n, i + 1, nil
+		}
+	}
+
+	return 0, 0, errInvalidLZ4
+}
+
+func (lz4Decompressor) decompress(b []byte, maxSize int) ([]byte, error) {
+	out := make([]byte, 0, maxSize)
+
+	for i := 0; i < len(b); {
+		token := b[i]
+		i++
+
+		// Copy literals.
+		n, m, err := lz4Length(token>>4, b[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += m
+
+		if n > len(b)-i {
+			return nil, errInvalidLZ4
+		}
+		if n > maxSize-len(out) {
+			return nil, errDecompressedSize
+		}
+		out = append(out, b[i:i+n]...)
+		i += n
+
+		// The last sequence contains only literals.
+		if i == len(b) {
+			break
+		}
+
+		// Copy match, which may overlap the output being written.
+		if len(b)-i < 2 {
+			return nil, errInvalidLZ4
+		}
+		off := int(binary.LittleEndian.Uint16(b[i:]))
+		i += 2
+
+		if off == 0 || off > len(out) {
+			return nil, errInvalidLZ4
+		}
+
+		n, m, err = lz4Length(token&0xf, b[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += m
+		n += 4
+
+		if n > maxSize-len(out) {
+			return nil, errDecompressedSize
+		}
+		for start := len(out) - off; n > 0; n-- {
+			out = append(out, out[start])
+			start++
+		}
+	}
+
+	return out, nil
+}
+
+// zstdDecompressor decompresses zstd-compressed blocks.
+type zstdDecompressor struct {
+	d *zstd.Decoder
+}
+
+func (z zstdDecompressor) decompress(b []byte, maxSize int) ([]byte, error) {
+	out, err := z.d.DecodeAll(b, make([]byte, 0, maxSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) > maxSize {
+		return nil, errDecompressedSize
+	}
+	return out, nil
+}
+
+// newDecompressor returns a decompressor for compression algorithm c.
+func newDecompressor(c compression) (decompressor, error) { //nolint:ireturn
+	switch c {
+	case compressionGzip:
+		return gzipDecompressor{}, nil
+
+	case compressionLZMA:
+		return lzmaDecompressor{}, nil
+
+	case compressionXZ:
+		return xzDecompressor{}, nil
+
+	case compressionLZ4:
+		return lz4Decompressor{}, nil
+
+	case compressionZstd:
+		d, err := zstd.NewReader(nil,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxMemory(1<<maxBlockLog),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return zstdDecompressor{d}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrUnsupportedCompression, c)
+}
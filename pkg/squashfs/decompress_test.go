@@ -0,0 +1,166 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+)
+
+func TestLZ4Decompressor(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       []byte
+		maxSize int
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:    "Literals",
+			b:       []byte{0x30, 'a', 'b', 'c'},
+			maxSize: 3,
+			want:    []byte("abc"),
+		},
+		{
+			name:    "OverlappingMatch",
+			b:       []byte{0x22, 'a', 'b', 0x02, 0x00, 0x10, 'c'},
+			maxSize: 9,
+			want:    []byte("abababab" + "c"),
+		},
+		{
+			name:    "LongMatch",
+			b:       append([]byte{0x1f, 'a', 0x01, 0x00, 0x00, 0x10}, 'b'),
+			maxSize: 21,
+			want:    append(bytes.Repeat([]byte("a"), 20), 'b'),
+		},
+		{
+			name:    "ZeroOffset",
+			b:       []byte{0x10, 'a', 0x00, 0x00, 0x10, 'b'},
+			maxSize: 16,
+			wantErr: errInvalidLZ4,
+		},
+		{
+			name:    "OffsetBeforeStart",
+			b:       []byte{0x10, 'a', 0x02, 0x00, 0x10, 'b'},
+			maxSize: 16,
+			wantErr: errInvalidLZ4,
+		},
+		{
+			name:    "TruncatedLiterals",
+			b:       []byte{0x30, 'a', 'b'},
+			maxSize: 16,
+			wantErr: errInvalidLZ4,
+		},
+		{
+			name:    "TruncatedLength",
+			b:       []byte{0xf0, 0xff},
+			maxSize: 16,
+			wantErr: errInvalidLZ4,
+		},
+		{
+			name:    "TruncatedOffset",
+			b:       []byte{0x10, 'a', 0x01},
+			maxSize: 16,
+			wantErr: errInvalidLZ4,
+		},
+		{
+			name:    "LiteralsExceedMaxSize",
+			b:       []byte{0x30, 'a', 'b', 'c'},
+			maxSize: 2,
+			wantErr: errDecompressedSize,
+		},
+		{
+			name:    "MatchExceedsMaxSize",
+			b:       []byte{0x22, 'a', 'b', 0x02, 0x00, 0x10, 'c'},
+			maxSize: 8,
+			wantErr: errDecompressedSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := lz4Decompressor{}.decompress(tt.b, tt.maxSize)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := b, tt.want; !bytes.Equal(got, want) {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestXZDecompressor(t *testing.T) {
+	data := bytes.Repeat([]byte("squashfs"), 512)
+
+	// compress returns data compressed with the specified dictionary size.
+	compress := func(dictCap int) []byte {
+		var buf bytes.Buffer
+
+		w, err := xz.WriterConfig{DictCap: dictCap}.NewWriter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write(data); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		b       []byte
+		maxSize int
+		wantErr error
+	}{
+		{
+			name:    "OK",
+			b:       compress(1 << minBlockLog),
+			maxSize: len(data),
+		},
+		{
+			name:    "DictionarySize",
+			b:       compress(maxDictSize * 2),
+			maxSize: len(data),
+			wantErr: errDictionarySize,
+		},
+		{
+			name:    "Truncated",
+			b:       compress(1 << minBlockLog)[:12],
+			maxSize: len(data),
+			wantErr: errInvalidXZ,
+		},
+		{
+			name:    "ExceedsMaxSize",
+			b:       compress(1 << minBlockLog),
+			maxSize: len(data) - 1,
+			wantErr: errDecompressedSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := xzDecompressor{}.decompress(tt.b, tt.maxSize)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil && !bytes.Equal(b, data) {
+				t.Errorf("got %q, want %q", b, data)
+			}
+		})
+	}
+}
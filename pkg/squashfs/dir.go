@@ -0,0 +1,164 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+const (
+	maxDirEntries = 256 // Maximum number of entries following a directory header.
+	maxNameSize   = 256 // Maximum length of a directory entry name.
+	dirSizeOffset = 3   // Amount by which directory inode size exceeds listing size.
+	dirHeaderSize = 12  // Size of on-disk directory header.
+	dirEntrySize  = 8   // Size of on-disk directory entry, excluding name.
+)
+
+var errCorruptDirectory = errors.New("corrupt directory")
+
+// dirHeader is the on-disk representation of a directory header.
+type dirHeader struct {
+	Count       uint32 // Number of entries that follow, minus one.
+	Start       uint32 // Offset of inode metadata block, relative to inode table.
+	InodeNumber uint32 // Base inode number.
+}
+
+// dirEntryHeader is the on-disk representation of a directory entry, excluding name.
+type dirEntryHeader struct {
+	Offset      uint16 // Offset of inode within metadata block.
+	InodeOffset int16  // Inode number, relative to base inode number.
+	Type        inodeType
+	NameSize    uint16 // Length of name, minus one.
+}
+
+// dirent is a parsed directory entry.
+type dirent struct {
+	name string
+	ref  uint64 // Inode reference.
+	typ  inodeType
+}
+
+// scanDir calls fn for each entry of directory in, in the order they are stored, until fn
+// returns false.
+func (f *FS) scanDir(in *inode, fn func(dirent) bool) error {
+	if in.dirSize <= dirSizeOffset {
+		return nil
+	}
+
+	block := int64(f.sb.DirectoryTableStart) + int64(in.dirBlock) //nolint:gosec // Validated by ReadAt.
+
+	r, err := f.newMetadataReader(block, in.dirOffset)
+	if err != nil {
+		return err
+	}
+
+	var (
+		hb   [dirHeaderSize]byte
+		eb   [dirEntrySize]byte
+		name [maxNameSize]byte
+	)
+
+	for remaining := int64(in.dirSize) - dirSizeOffset; remaining > 0; {
+		if _, err := io.ReadFull(r, hb[:]); err != nil {
+			return err
+		}
+		remaining -= dirHeaderSize
+
+		h := dirHeader{
+			Count:       binary.LittleEndian.Uint32(hb[0:]),
+			Start:       binary.LittleEndian.Uint32(hb[4:]),
+			InodeNumber: binary.LittleEndian.Uint32(hb[8:]),
+		}
+
+		if h.Count >= maxDirEntries {
+			return fmt.Errorf("%w: directory header count %v", errCorruptDirectory, h.Count+1)
+		}
+
+		for range h.Count + 1 {
+			if _, err := io.ReadFull(r, eb[:]); err != nil {
+				return err
+			}
+
+			eh := dirEntryHeader{
+				Offset:   binary.LittleEndian.Uint16(eb[0:]),
+				Type:     inodeType(binary.LittleEndian.Uint16(eb[4:])),
+				NameSize: binary.LittleEndian.Uint16(eb[6:]),
+			}
+
+			if eh.NameSize >= maxNameSize {
+				return fmt.Errorf("%w: name size %v", errCorruptDirectory, eh.NameSize+1)
+			}
+
+			n := name[:eh.NameSize+1]
+			if _, err := io.ReadFull(r, n); err != nil {
+				return err
+			}
+			remaining -= dirEntrySize + int64(len(n))
+
+			if !isValidName(string(n)) {
+				return fmt.Errorf("%w: invalid name %q", errCorruptDirectory, n)
+			}
+
+			de := dirent{
+				name: string(n),
+				ref:  uint64(h.Start)<<16 | uint64(eh.Offset),
+				typ:  eh.Type,
+			}
+
+			if !fn(de) {
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// readDir returns the entries of directory in, in the order they are stored.
+func (f *FS) readDir(in *inode) ([]dirent, error) {
+	var des []dirent
+
+	err := f.scanDir(in, func(de dirent) bool {
+		des = append(des, de)
+		return true
+	})
+
+	return des, err
+}
+
+// isValidName reports whether name is a valid directory entry name.
+func isValidName(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, "/\x00")
+}
+
+// lookupEntry returns the inode of the entry with the specified name in directory in.
+func (f *FS) lookupEntry(in *inode, name string) (*inode, error) {
+	var (
+		ref   uint64
+		found bool
+	)
+
+	err := f.scanDir(in, func(de dirent) bool {
+		if de.name == name {
+			ref, found = de.ref, true
+		}
+		return !found
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fs.ErrNotExist
+	}
+
+	return f.readInode(ref)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+/*
+Package squashfs implements read-only access to squashfs filesystems, such as those found in the
+partitions of a Singularity Image Format (SIF) image, without the use of FUSE or elevated
+privileges.
+
+# Open
+
+To access the filesystem in the primary system partition of an image, use NewFSFromDescriptor:
+
+	d, err := f.GetDescriptor(sif.WithPartitionType(sif.PartPrimSys))
+	if err != nil {
+		return err
+	}
+
+	fsys, err := squashfs.NewFSFromDescriptor(d)
+	if err != nil {
+		return err
+	}
+
+To access a filesystem from an arbitrary io.ReaderAt, use NewFS.
+
+# Compression
+
+Filesystems compressed using gzip, lzma, xz, lz4 or zstd are supported, as are uncompressed
+filesystems. Filesystems compressed using lzo are not supported, and NewFS returns an error
+wrapping ErrUnsupportedCompression that names the compression algorithm. Compressor options
+recorded in the filesystem are not required to read it, so they are ignored. Compressed blocks
+that use an xz filter other than LZMA2, such as a branch/call/jump filter, cannot be read.
+
+# Read
+
+The returned FS implements fs.FS, so it can be used with the standard library. For example, to
+read a file:
+
+	b, err := fs.ReadFile(fsys, "etc/os-release")
+
+To walk the filesystem:
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	})
+
+Ownership, link count and device numbers are available from the *Inode returned by the Sys
+method of fs.FileInfo. Extended attributes are available using FS.Xattrs.
+*/
+package squashfs
@@ -0,0 +1,287 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sync"
+	"time"
+)
+
+var errCorruptData = errors.New("corrupt data block")
+
+// Inode contains filesystem-specific information about a file. It is returned by the Sys method
+// of the fs.FileInfo values produced by an FS.
+type Inode struct {
+	Number uint32 // Inode number.
+	Nlink  uint32 // Number of hard links.
+	UID    uint32 // User ID of owner.
+	GID    uint32 // Group ID of owner.
+	Major  uint32 // Major device number, for block and character devices.
+	Minor  uint32 // Minor device number, for block and character devices.
+}
+
+// fileInfo implements fs.FileInfo.
+type fileInfo struct {
+	name string
+	in   *inode
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.in.fileSize() }
+func (fi *fileInfo) Mode() fs.FileMode  { return fi.in.mode() }
+func (fi *fileInfo) ModTime() time.Time { return fi.in.modTime() }
+func (fi *fileInfo) IsDir() bool        { return fi.in.isDir() }
+
+// Sys returns a *Inode.
+func (fi *fileInfo) Sys() any {
+	return &Inode{
+		Number: fi.in.InodeNumber,
+		Nlink:  fi.in.nlink,
+		UID:    fi.in.uid,
+		GID:    fi.in.gid,
+		Major:  (fi.in.rdev >> 8) & 0xfff,
+		Minor:  (fi.in.rdev & 0xff) | ((fi.in.rdev >> 12) & 0xfff00),
+	}
+}
+
+// dirEntry implements fs.DirEntry.
+type dirEntry struct {
+	f *FS
+	dirent
+}
+
+func (de *dirEntry) Name() string      { return de.name }
+func (de *dirEntry) IsDir() bool       { return de.typ.basic() == inodeDir }
+func (de *dirEntry) Type() fs.FileMode { return de.typ.fileMode() }
+
+// Info returns a fs.FileInfo describing the entry.
+func (de *dirEntry) Info() (fs.FileInfo, error) { //nolint:ireturn
+	in, err := de.f.readInode(de.ref)
+	if err != nil {
+		return nil, err
+	}
+	return &fileInfo{name: de.name, in: in}, nil
+}
+
+// newFile returns a fs.File that reads the contents of in.
+func (f *FS) newFile(name string, in *inode) fs.File { //nolint:ireturn
+	fi := fileInfo{name: path.Base(name), in: in}
+
+	switch {
+	case in.isDir():
+		return &dirFile{fileInfo: fi, f: f, path: name}
+	case in.isRegular():
+		return &regularFile{
+			fileInfo:      fi,
+			SectionReader: io.NewSectionReader(f.newFileReader(in), 0, int64(in.size)), //nolint:gosec // Validated.
+		}
+	}
+
+	return &otherFile{fileInfo: fi}
+}
+
+// regularFile is an open regular file.
+type regularFile struct {
+	fileInfo
+	*io.SectionReader
+}
+
+func (f *regularFile) Stat() (fs.FileInfo, error) { return &f.fileInfo, nil } //nolint:ireturn
+func (f *regularFile) Close() error               { return nil }
+
+// otherFile is an open file that is not a regular file or directory.
+type otherFile struct {
+	fileInfo
+}
+
+func (f *otherFile) Stat() (fs.FileInfo, error) { return &f.fileInfo, nil } //nolint:ireturn
+func (f *otherFile) Read([]byte) (int, error)   { return 0, io.EOF }
+func (f *otherFile) Close() error               { return nil }
+
+// dirFile is an open directory.
+type dirFile struct {
+	fileInfo
+	f    *FS
+	path string
+
+	entries []fs.DirEntry // Remaining entries, populated on first call to ReadDir.
+	read    bool          // Whether entries have been populated.
+}
+
+func (d *dirFile) Stat() (fs.FileInfo, error) { return &d.fileInfo, nil } //nolint:ireturn
+func (d *dirFile) Close() error               { return nil }
+
+// Read returns an error, since directories cannot be read.
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.path, Err: errIsDir}
+}
+
+// ReadDir reads the contents of the directory, as described by fs.ReadDirFile.
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		des, err := d.f.dirEntries(d.in)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: d.path, Err: err}
+		}
+		d.entries, d.read = des, true
+	}
+
+	if n <= 0 {
+		des := d.entries
+		d.entries = nil
+		return des, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	n = min(n, len(d.entries))
+	des := d.entries[:n:n]
+	d.entries = d.entries[n:]
+	return des, nil
+}
+
+// fileReader reads the contents of a regular file.
+type fileReader struct {
+	f       *FS
+	in      *inode
+	offsets []int64 // Offset of each data block.
+
+	mu    sync.Mutex
+	index int64  // Index of cached block, or -1.
+	block []byte // Decompressed contents of cached block.
+}
+
+// newFileReader returns a reader that reads the contents of regular file in.
+func (f *FS) newFileReader(in *inode) *fileReader {
+	offsets := make([]int64, len(in.blockSizes))
+
+	off := int64(in.blocksStart) //nolint:gosec // Validated by ReadAt.
+	for i, size := range in.blockSizes {
+		offsets[i] = off
+		off += int64(size &^ dataBlockUncompressed)
+	}
+
+	return &fileReader{f: f, in: in, offsets: offsets, index: -1}
+}
+
+// ReadAt reads len(p) bytes from the file starting at offset off.
+func (r *fileReader) ReadAt(p []byte, off int64) (int, error) {
+	size := int64(r.in.size) //nolint:gosec // Validated when inode is read.
+	if off >= size {
+		return 0, io.EOF
+	}
+
+	blockSize := int64(r.f.sb.BlockSize)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int
+
+	for n < len(p) && off < size {
+		i := off / blockSize
+
+		if i != r.index {
+			b, err := r.readBlock(i, min(blockSize, size-i*blockSize))
+			if err != nil {
+				return n, err
+			}
+			r.index, r.block = i, b
+		}
+
+		c := copy(p[n:], r.block[off-i*blockSize:])
+		n += c
+		off += int64(c)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// readBlock returns the contents of block i, which is expected to be size bytes in length.
+func (r *fileReader) readBlock(i, size int64) ([]byte, error) {
+	if i >= int64(len(r.in.blockSizes)) {
+		return r.readTail(size)
+	}
+
+	onDisk := r.in.blockSizes[i] &^ dataBlockUncompressed
+
+	// Sparse blocks are not stored.
+	if onDisk == 0 {
+		return make([]byte, size), nil
+	}
+
+	b := make([]byte, onDisk)
+	if _, err := r.f.r.ReadAt(b, r.offsets[i]); err != nil {
+		return nil, fmt.Errorf("data block %v: %w", i, err)
+	}
+
+	if r.in.blockSizes[i]&dataBlockUncompressed == 0 {
+		var err error
+		if b, err = r.f.dec.decompress(b, int(r.f.sb.BlockSize)); err != nil {
+			return nil, fmt.Errorf("data block %v: %w", i, err)
+		}
+	}
+
+	if int64(len(b)) != size {
+		return nil, fmt.Errorf("%w: block %v is %v bytes, expected %v", errCorruptData, i, len(b), size)
+	}
+
+	return b, nil
+}
+
+// readTail returns the tail end of the file, which is stored in a fragment block.
+func (r *fileReader) readTail(size int64) ([]byte, error) {
+	b, err := r.f.readFragment(r.in.fragment)
+	if err != nil {
+		return nil, err
+	}
+
+	start := int64(r.in.fragOffset)
+	if start+size > int64(len(b)) {
+		return nil, fmt.Errorf("%w: tail end exceeds fragment %v", errCorruptData, r.in.fragment)
+	}
+
+	return b[start : start+size], nil
+}
+
+// readFragment returns the decompressed contents of fragment block i.
+func (f *FS) readFragment(i uint32) ([]byte, error) {
+	e, err := f.lookupFragment(i)
+	if err != nil {
+		return nil, err
+	}
+
+	start := int64(e.Start) //nolint:gosec // Validated by ReadAt.
+
+	if b, ok := f.fragments.get(start); ok {
+		return b.data, nil
+	}
+
+	b := make([]byte, e.Size&^dataBlockUncompressed)
+	if _, err := f.r.ReadAt(b, start); err != nil {
+		return nil, fmt.Errorf("fragment %v: %w", i, err)
+	}
+
+	if e.Size&dataBlockUncompressed == 0 {
+		if b, err = f.dec.decompress(b, int(f.sb.BlockSize)); err != nil {
+			return nil, fmt.Errorf("fragment %v: %w", i, err)
+		}
+	}
+
+	f.fragments.add(start, cachedBlock{data: b})
+
+	return b, nil
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+const maxSymlinks = 40 // Maximum number of symbolic links followed during path resolution.
+
+var (
+	errNotPartition      = errors.New("descriptor is not a partition")
+	errUnsupportedFSType = errors.New("unsupported filesystem type")
+	errNotDir            = errors.New("not a directory")
+	errIsDir             = errors.New("is a directory")
+	errTooManyLinks      = errors.New("too many levels of symbolic links")
+	errRootNotDir        = errors.New("root inode is not a directory")
+)
+
+// FS provides read-only access to the contents of a squashfs filesystem. It implements
+// fs.FS, fs.StatFS, fs.ReadDirFS and fs.ReadLinkFS. An FS is safe for concurrent use.
+type FS struct {
+	r         io.ReaderAt
+	sb        superblock
+	dec       decompressor
+	metadata  blockCache // Decompressed metadata blocks.
+	fragments blockCache // Decompressed fragment blocks.
+	tables    tables
+	root      *inode
+}
+
+// NewFS returns an FS that reads the squashfs filesystem from r.
+//
+// Filesystems compressed with gzip, lzma, xz, lz4 or zstd, or not compressed at all, are supported.
+// If the filesystem uses another compression algorithm, an error wrapping ErrUnsupportedCompression
+// is returned.
+func NewFS(r io.ReaderAt) (*FS, error) {
+	sb, err := readSuperblock(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read superblock: %w", err)
+	}
+
+	dec, err := newDecompressor(sb.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &FS{
+		r:   r,
+		sb:  sb,
+		dec: dec,
+	}
+
+	if f.root, err = f.readInode(sb.RootInode); err != nil {
+		return nil, fmt.Errorf("failed to read root inode: %w", err)
+	}
+
+	if !f.root.isDir() {
+		return nil, errRootNotDir
+	}
+
+	return f, nil
+}
+
+// NewFSFromDescriptor returns an FS that reads the squashfs filesystem contained in the
+// partition described by d.
+func NewFSFromDescriptor(d sif.Descriptor) (*FS, error) {
+	if dt := d.DataType(); dt != sif.DataPartition {
+		return nil, fmt.Errorf("%w: %v", errNotPartition, dt)
+	}
+
+	fsType, _, _, err := d.PartitionMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if fsType != sif.FsSquash {
+		return nil, fmt.Errorf("%w: %v", errUnsupportedFSType, fsType)
+	}
+
+	r, ok := d.GetReader().(io.ReaderAt)
+	if !ok {
+		return nil, fmt.Errorf("%w: reader does not implement io.ReaderAt", errUnsupportedFSType)
+	}
+
+	return NewFS(r)
+}
+
+// lookup returns the inode at the specified path. If follow is true and the final element of
+// name refers to a symbolic link, the link is followed. Symbolic links in other path elements are
+// always followed. Absolute symbolic link targets are resolved relative to the root of f.
+func (f *FS) lookup(name string, follow bool) (*inode, error) {
+	if !fs.ValidPath(name) {
+		return nil, fs.ErrInvalid
+	}
+
+	elems := strings.Split(name, "/")
+	dirs := []*inode{f.root}
+
+	for links := 0; len(elems) > 0; {
+		elem := elems[0]
+		elems = elems[1:]
+
+		if elem == "." || elem == "" {
+			continue
+		}
+
+		dir := dirs[len(dirs)-1]
+		if !dir.isDir() {
+			return nil, errNotDir
+		}
+
+		if elem == ".." {
+			if len(dirs) > 1 {
+				dirs = dirs[:len(dirs)-1]
+			}
+			continue
+		}
+
+		in, err := f.lookupEntry(dir, elem)
+		if err != nil {
+			return nil, err
+		}
+
+		if in.isSymlink() && (follow || len(elems) > 0) {
+			if links++; links > maxSymlinks {
+				return nil, errTooManyLinks
+			}
+
+			if strings.HasPrefix(in.target, "/") {
+				dirs = dirs[:1]
+			}
+
+			elems = append(strings.Split(in.target, "/"), elems...)
+			continue
+		}
+
+		dirs = append(dirs, in)
+	}
+
+	return dirs[len(dirs)-1], nil
+}
+
+// Open opens the named file, following symbolic links.
+func (f *FS) Open(name string) (fs.File, error) { //nolint:ireturn
+	in, err := f.lookup(name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return f.newFile(name, in), nil
+}
+
+// Stat returns a fs.FileInfo describing the named file, following symbolic links.
+func (f *FS) Stat(name string) (fs.FileInfo, error) { //nolint:ireturn
+	in, err := f.lookup(name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	return &fileInfo{name: path.Base(name), in: in}, nil
+}
+
+// Lstat returns a fs.FileInfo describing the named file. If the file is a symbolic link, the
+// returned fs.FileInfo describes the link itself.
+func (f *FS) Lstat(name string) (fs.FileInfo, error) { //nolint:ireturn
+	in, err := f.lookup(name, false)
+	if err != nil {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: err}
+	}
+
+	return &fileInfo{name: path.Base(name), in: in}, nil
+}
+
+// ReadLink returns the destination of the named symbolic link.
+func (f *FS) ReadLink(name string) (string, error) {
+	in, err := f.lookup(name, false)
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: err}
+	}
+
+	if !in.isSymlink() {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return in.target, nil
+}
+
+// ReadDir reads the named directory, following symbolic links, and returns a list of directory
+// entries sorted by filename.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	in, err := f.lookup(name, true)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	if !in.isDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+
+	des, err := f.dirEntries(in)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	return des, nil
+}
+
+// dirEntries returns the entries of directory in, sorted by filename.
+func (f *FS) dirEntries(in *inode) ([]fs.DirEntry, error) {
+	ents, err := f.readDir(in)
+	if err != nil {
+		return nil, err
+	}
+
+	des := make([]fs.DirEntry, 0, len(ents))
+	for _, e := range ents {
+		des = append(des, &dirEntry{f: f, dirent: e})
+	}
+
+	slices.SortFunc(des, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+
+	return des, nil
+}
+
+// Xattrs returns the extended attributes of the named file, keyed by name. If the file is a
+// symbolic link, the attributes of the link itself are returned.
+func (f *FS) Xattrs(name string) (map[string][]byte, error) {
+	in, err := f.lookup(name, false)
+	if err != nil {
+		return nil, &fs.PathError{Op: "xattrs", Path: name, Err: err}
+	}
+
+	xattrs, err := f.readXattrs(in)
+	if err != nil {
+		return nil, &fs.PathError{Op: "xattrs", Path: name, Err: err}
+	}
+
+	return xattrs, nil
+}
@@ -0,0 +1,551 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+var corpus = filepath.Join("..", "..", "test", "images")
+
+var (
+	bigData    = randomData(3*4096 + 100)
+	exactData  = randomData(2 * 4096)
+	sparseData = append(make([]byte, 2*4096), randomData(100)...)
+)
+
+func randomData(n int) []byte {
+	b := make([]byte, n)
+	r := rand.New(rand.NewSource(int64(n))) //nolint:gosec // Deterministic test data.
+	for i := range b {
+		b[i] = byte(r.Intn(16)) // Compressible, but not trivially.
+	}
+	return b
+}
+
+// testEntries returns the entries of a filesystem that exercises most features.
+func testEntries() []testEntry {
+	entries := []testEntry{
+		{path: "dev", mode: fs.ModeDir | 0o755},
+		{path: "dev/fifo", mode: fs.ModeNamedPipe | 0o600},
+		{path: "dev/loop", mode: fs.ModeDevice | 0o660, major: 7, minor: 300},
+		{path: "dev/null", mode: fs.ModeDevice | fs.ModeCharDevice | 0o666, major: 1, minor: 3, xattrs: map[string]string{
+			"trusted.x": "y",
+		}},
+		{path: "dev/sock", mode: fs.ModeSocket | 0o600},
+		{path: "empty", mode: 0o644},
+		{path: "etc", mode: fs.ModeDir | 0o755, xattrs: map[string]string{
+			"user.a": "value",
+		}},
+		{path: "etc/os-release", mode: 0o644, data: []byte("NAME=\"Test\"\n"), mtime: 1234567890},
+		{path: "etc/os-release-link", mode: fs.ModeSymlink | 0o777, target: "os-release"},
+		{path: "etc/passwd", mode: 0o644, data: []byte("root:x:0:0::/root:/bin/sh\n"), xattrs: map[string]string{
+			"security.selinux": "system_u:object_r:etc_t:s0",
+			"user.a":           "value",
+		}},
+		{path: "abs", mode: fs.ModeSymlink | 0o777, target: "/etc/os-release", xattrs: map[string]string{
+			"user.b": "link",
+		}},
+		{path: "tmp", mode: fs.ModeDir | fs.ModeSticky | 0o777},
+		{path: "usr", mode: fs.ModeDir | 0o755, uid: 1000, gid: 1001},
+		{path: "usr/bin", mode: fs.ModeDir | 0o755},
+		{path: "usr/bin/big", mode: 0o755, data: bigData},
+		{path: "usr/bin/exact", mode: 0o755, data: exactData},
+		{path: "usr/bin/link", link: "usr/bin/big"},
+		{path: "usr/bin/setuid", mode: fs.ModeSetuid | fs.ModeSetgid | 0o755, data: []byte("#!/bin/sh\n")},
+		{path: "usr/bin/sparse", mode: 0o644, data: sparseData},
+		{path: "usr/lib64", mode: fs.ModeSymlink | 0o777, target: "../etc"},
+		{path: "many", mode: fs.ModeDir | 0o755},
+	}
+
+	// Enough entries to span multiple directory headers and metadata blocks.
+	for i := range 1000 {
+		entries = append(entries, testEntry{
+			path: fmt.Sprintf("many/file-%04d", i),
+			mode: 0o644,
+			uid:  uint32(i % 3),
+			data: fmt.Appendf(nil, "%v\n", i),
+		})
+	}
+
+	return entries
+}
+
+func TestFS(t *testing.T) {
+	tests := []struct {
+		name string
+		opts testWriterOpts
+	}{
+		{"Gzip", testWriterOpts{comp: compressionGzip}},
+		{"LZMA", testWriterOpts{comp: compressionLZMA}},
+		{"XZ", testWriterOpts{comp: compressionXZ}},
+		{"LZ4", testWriterOpts{comp: compressionLZ4}},
+		{"Zstd", testWriterOpts{comp: compressionZstd}},
+		{"Uncompressed", testWriterOpts{uncompressed: true}},
+		{"NoFragments", testWriterOpts{noFragments: true}},
+		{"LargeBlocks", testWriterOpts{blockLog: 17}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFS(bytes.NewReader(writeTestFS(t, testEntries(), tt.opts)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := fstest.TestFS(f,
+				"abs",
+				"dev/null",
+				"empty",
+				"etc/os-release",
+				"etc/os-release-link",
+				"many/file-0999",
+				"usr/bin/big",
+				"usr/bin/link",
+				"usr/bin/sparse",
+				"usr/lib64",
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			files := map[string][]byte{
+				"abs":                   []byte("NAME=\"Test\"\n"),
+				"empty":                 {},
+				"etc/os-release":        []byte("NAME=\"Test\"\n"),
+				"etc/os-release-link":   []byte("NAME=\"Test\"\n"),
+				"many/file-0123":        []byte("123\n"),
+				"usr/bin/big":           bigData,
+				"usr/bin/exact":         exactData,
+				"usr/bin/link":          bigData,
+				"usr/bin/sparse":        sparseData,
+				"usr/lib64/passwd":      []byte("root:x:0:0::/root:/bin/sh\n"),
+				"usr/bin/../lib64/../x": nil,
+			}
+
+			for name, want := range files {
+				b, err := fs.ReadFile(f, name)
+				if want == nil {
+					if !errors.Is(err, fs.ErrInvalid) {
+						t.Errorf("%v: got error %v, want %v", name, err, fs.ErrInvalid)
+					}
+					continue
+				}
+
+				if err != nil {
+					t.Errorf("%v: %v", name, err)
+				} else if !bytes.Equal(b, want) {
+					t.Errorf("%v: got %v bytes, want %v bytes", name, len(b), len(want))
+				}
+			}
+		})
+	}
+}
+
+func TestFS_Lookup(t *testing.T) {
+	entries := []testEntry{
+		{path: "dir", mode: fs.ModeDir | 0o755},
+		{path: "dir/file", mode: 0o644, data: []byte("file")},
+		{path: "dangling", mode: fs.ModeSymlink | 0o777, target: "missing"},
+		{path: "loop", mode: fs.ModeSymlink | 0o777, target: "loop"},
+		{path: "escape", mode: fs.ModeSymlink | 0o777, target: "../../../dir/file"},
+		{path: "dotdot", mode: fs.ModeSymlink | 0o777, target: "dir/../dir/./file"},
+	}
+
+	f, err := NewFS(bytes.NewReader(writeTestFS(t, entries, testWriterOpts{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		follow   bool
+		wantMode fs.FileMode
+		wantErr  error
+	}{
+		{name: "Root", path: ".", wantMode: fs.ModeDir | 0o755},
+		{name: "File", path: "dir/file", wantMode: 0o644},
+		{name: "NotExist", path: "dir/missing", wantErr: fs.ErrNotExist},
+		{name: "NotDir", path: "dir/file/x", wantErr: errNotDir},
+		{name: "Invalid", path: "/dir", wantErr: fs.ErrInvalid},
+		{name: "DanglingNoFollow", path: "dangling", wantMode: fs.ModeSymlink | 0o777},
+		{name: "Dangling", path: "dangling", follow: true, wantErr: fs.ErrNotExist},
+		{name: "LoopNoFollow", path: "loop", wantMode: fs.ModeSymlink | 0o777},
+		{name: "Loop", path: "loop", follow: true, wantErr: errTooManyLinks},
+		{name: "LoopDir", path: "loop/x", wantErr: errTooManyLinks},
+		{name: "Escape", path: "escape", follow: true, wantMode: 0o644},
+		{name: "DotDot", path: "dotdot", follow: true, wantMode: 0o644},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := f.lookup(tt.path, tt.follow)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				if got, want := in.mode(), tt.wantMode; got != want {
+					t.Errorf("got mode %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFS_Lstat(t *testing.T) {
+	f, err := NewFS(bytes.NewReader(writeTestFS(t, testEntries(), testWriterOpts{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		wantMode  fs.FileMode
+		wantSize  int64
+		wantInode Inode
+	}{
+		{
+			name:      "Root",
+			path:      ".",
+			wantMode:  fs.ModeDir | 0o755,
+			wantSize:  107,
+			wantInode: Inode{Number: 1, Nlink: 7},
+		},
+		{
+			name:      "Dir",
+			path:      "usr",
+			wantMode:  fs.ModeDir | 0o755,
+			wantSize:  39,
+			wantInode: Inode{Number: 14, Nlink: 3, UID: 1000, GID: 1001},
+		},
+		{
+			name:      "Sticky",
+			path:      "tmp",
+			wantMode:  fs.ModeDir | fs.ModeSticky | 0o777,
+			wantSize:  3,
+			wantInode: Inode{Number: 13, Nlink: 2},
+		},
+		{
+			name:      "Setuid",
+			path:      "usr/bin/setuid",
+			wantMode:  fs.ModeSetuid | fs.ModeSetgid | 0o755,
+			wantSize:  10,
+			wantInode: Inode{Number: 18, Nlink: 1},
+		},
+		{
+			name:      "HardLink",
+			path:      "usr/bin/link",
+			wantMode:  0o755,
+			wantSize:  int64(len(bigData)),
+			wantInode: Inode{Number: 16, Nlink: 2},
+		},
+		{
+			name:      "Symlink",
+			path:      "usr/lib64",
+			wantMode:  fs.ModeSymlink | 0o777,
+			wantSize:  6,
+			wantInode: Inode{Number: 20, Nlink: 1},
+		},
+		{
+			name:      "CharDevice",
+			path:      "dev/null",
+			wantMode:  fs.ModeDevice | fs.ModeCharDevice | 0o666,
+			wantInode: Inode{Number: 5, Nlink: 1, Major: 1, Minor: 3},
+		},
+		{
+			name:      "BlockDevice",
+			path:      "dev/loop",
+			wantMode:  fs.ModeDevice | 0o660,
+			wantInode: Inode{Number: 4, Nlink: 1, Major: 7, Minor: 300},
+		},
+		{
+			name:      "Fifo",
+			path:      "dev/fifo",
+			wantMode:  fs.ModeNamedPipe | 0o600,
+			wantInode: Inode{Number: 3, Nlink: 1},
+		},
+		{
+			name:      "Socket",
+			path:      "dev/sock",
+			wantMode:  fs.ModeSocket | 0o600,
+			wantInode: Inode{Number: 6, Nlink: 1},
+		},
+		{
+			name:      "UID",
+			path:      "many/file-0002",
+			wantMode:  0o644,
+			wantSize:  2,
+			wantInode: Inode{Number: 24, Nlink: 1, UID: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fi, err := f.Lstat(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := fi.Mode(), tt.wantMode; got != want {
+				t.Errorf("got mode %v, want %v", got, want)
+			}
+
+			if got, want := fi.Size(), tt.wantSize; got != want {
+				t.Errorf("got size %v, want %v", got, want)
+			}
+
+			in, ok := fi.Sys().(*Inode)
+			if !ok {
+				t.Fatalf("unexpected Sys type %T", fi.Sys())
+			}
+
+			if got, want := *in, tt.wantInode; got != want {
+				t.Errorf("got inode %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestFS_ReadLink(t *testing.T) {
+	f, err := NewFS(bytes.NewReader(writeTestFS(t, testEntries(), testWriterOpts{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantTarget string
+		wantErr    error
+	}{
+		{name: "Relative", path: "etc/os-release-link", wantTarget: "os-release"},
+		{name: "Absolute", path: "abs", wantTarget: "/etc/os-release"},
+		{name: "ViaSymlink", path: "usr/lib64/os-release-link", wantTarget: "os-release"},
+		{name: "NotSymlink", path: "etc/os-release", wantErr: fs.ErrInvalid},
+		{name: "NotExist", path: "missing", wantErr: fs.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := f.ReadLink(tt.path)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := target, tt.wantTarget; got != want {
+				t.Errorf("got target %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFS_Xattrs(t *testing.T) {
+	f, err := NewFS(bytes.NewReader(writeTestFS(t, testEntries(), testWriterOpts{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantXattrs map[string][]byte
+	}{
+		{
+			name: "File",
+			path: "etc/passwd",
+			wantXattrs: map[string][]byte{
+				"security.selinux": []byte("system_u:object_r:etc_t:s0"),
+				"user.a":           []byte("value"),
+			},
+		},
+		{
+			name:       "OutOfLine",
+			path:       "etc",
+			wantXattrs: map[string][]byte{"user.a": []byte("value")},
+		},
+		{
+			name:       "Symlink",
+			path:       "abs",
+			wantXattrs: map[string][]byte{"user.b": []byte("link")},
+		},
+		{
+			name:       "Device",
+			path:       "dev/null",
+			wantXattrs: map[string][]byte{"trusted.x": []byte("y")},
+		},
+		{
+			name: "None",
+			path: "etc/os-release",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xattrs, err := f.Xattrs(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := xattrs, tt.wantXattrs; !maps.EqualFunc(got, want, bytes.Equal) {
+				t.Errorf("got xattrs %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNewFS(t *testing.T) {
+	b := writeTestFS(t, testEntries(), testWriterOpts{})
+
+	// corrupt returns a copy of b, with the superblock field at offset off set to v.
+	corrupt := func(off int, v uint16) []byte {
+		c := bytes.Clone(b)
+		binary.LittleEndian.PutUint16(c[off:], v)
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		b          []byte
+		wantErr    error
+		wantErrMsg string
+	}{
+		{name: "OK", b: b},
+		{name: "InvalidMagic", b: corrupt(0, 0), wantErr: errInvalidMagic},
+		{
+			name:       "UnsupportedCompression",
+			b:          corrupt(20, uint16(compressionLZO)),
+			wantErr:    ErrUnsupportedCompression,
+			wantErrMsg: "unsupported compression algorithm: lzo",
+		},
+		{
+			name:       "UnknownCompression",
+			b:          corrupt(20, 7),
+			wantErr:    ErrUnsupportedCompression,
+			wantErrMsg: "unsupported compression algorithm: unknown (7)",
+		},
+		{name: "InvalidBlockSize", b: corrupt(22, 11), wantErr: errInvalidBlockSize},
+		{name: "UnsupportedVersion", b: corrupt(28, 3), wantErr: errUnsupportedVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFS(bytes.NewReader(tt.b))
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+
+			if got, want := err, tt.wantErrMsg; want != "" && (got == nil || got.Error() != want) {
+				t.Errorf("got error %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewFSFromDescriptor(t *testing.T) {
+	b := writeTestFS(t, testEntries(), testWriterOpts{})
+
+	squash, err := sif.NewDescriptorInput(sif.DataPartition, bytes.NewReader(b),
+		sif.OptPartitionMetadata(sif.FsSquash, sif.PartPrimSys, "amd64"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ext3, err := sif.NewDescriptorInput(sif.DataPartition, bytes.NewReader(b),
+		sif.OptPartitionMetadata(sif.FsExt3, sif.PartData, "amd64"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generic, err := sif.NewDescriptorInput(sif.DataGeneric, bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := sif.CreateContainer(sif.NewBuffer(nil),
+		sif.OptCreateDeterministic(),
+		sif.OptCreateWithDescriptors(squash, ext3, generic),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint32
+		wantErr error
+	}{
+		{name: "Squash", id: 1},
+		{name: "Ext3", id: 2, wantErr: errUnsupportedFSType},
+		{name: "NotPartition", id: 3, wantErr: errNotPartition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := img.GetDescriptor(sif.WithID(tt.id))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFSFromDescriptor(d)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				if err := fstest.TestFS(f, "etc/os-release"); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewFSFromDescriptor_Corpus(t *testing.T) {
+	img, err := sif.LoadContainerFromPath(filepath.Join(corpus, "one-group.sif"), sif.OptLoadWithFlag(os.O_RDONLY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = img.UnloadContainer() })
+
+	d, err := img.GetDescriptor(sif.WithPartitionType(sif.PartPrimSys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewFSFromDescriptor(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fstest.TestFS(f, "hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := fs.ReadFile(f, "hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := string(b), "Hello from Sylabs!\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
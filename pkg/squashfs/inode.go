@@ -0,0 +1,343 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// inodeType is the type of an inode.
+type inodeType uint16
+
+// List of inode types.
+const (
+	inodeDir inodeType = iota + 1
+	inodeFile
+	inodeSymlink
+	inodeBlockDev
+	inodeCharDev
+	inodeFifo
+	inodeSocket
+	inodeExtDir
+	inodeExtFile
+	inodeExtSymlink
+	inodeExtBlockDev
+	inodeExtCharDev
+	inodeExtFifo
+	inodeExtSocket
+)
+
+// basic returns the basic type corresponding to t.
+func (t inodeType) basic() inodeType {
+	if t >= inodeExtDir {
+		return t - (inodeExtDir - inodeDir)
+	}
+	return t
+}
+
+// fileMode returns the file mode type bits corresponding to t.
+func (t inodeType) fileMode() fs.FileMode {
+	switch t.basic() {
+	case inodeDir:
+		return fs.ModeDir
+	case inodeSymlink:
+		return fs.ModeSymlink
+	case inodeBlockDev:
+		return fs.ModeDevice
+	case inodeCharDev:
+		return fs.ModeDevice | fs.ModeCharDevice
+	case inodeFifo:
+		return fs.ModeNamedPipe
+	case inodeSocket:
+		return fs.ModeSocket
+	}
+	return 0
+}
+
+const (
+	noFragment = 0xffffffff // Fragment index indicating a file has no fragment.
+	noXattr    = 0xffffffff // Xattr index indicating an inode has no xattrs.
+
+	dataBlockUncompressed = 1 << 24 // Data block size flag indicating block is uncompressed.
+
+	maxSymlinkSize = 4096 // Maximum length of a symbolic link target.
+)
+
+var (
+	errUnknownInodeType = errors.New("unknown inode type")
+	errCorruptInode     = errors.New("corrupt inode")
+)
+
+// inodeHeader is the on-disk header common to all inode types.
+type inodeHeader struct {
+	Type        inodeType
+	Mode        uint16
+	UIDIndex    uint16
+	GIDIndex    uint16
+	ModTime     uint32
+	InodeNumber uint32
+}
+
+// dirInode is the on-disk representation of a basic directory inode.
+type dirInode struct {
+	BlockStart  uint32
+	LinkCount   uint32
+	FileSize    uint16
+	BlockOffset uint16
+	ParentInode uint32
+}
+
+// extDirInode is the on-disk representation of an extended directory inode.
+type extDirInode struct {
+	LinkCount   uint32
+	FileSize    uint32
+	BlockStart  uint32
+	ParentInode uint32
+	IndexCount  uint16
+	BlockOffset uint16
+	XattrIndex  uint32
+}
+
+// fileInode is the on-disk representation of a basic file inode, excluding block sizes.
+type fileInode struct {
+	BlocksStart    uint32
+	FragmentIndex  uint32
+	FragmentOffset uint32
+	FileSize       uint32
+}
+
+// extFileInode is the on-disk representation of an extended file inode, excluding block sizes.
+type extFileInode struct {
+	BlocksStart    uint64
+	FileSize       uint64
+	Sparse         uint64
+	LinkCount      uint32
+	FragmentIndex  uint32
+	FragmentOffset uint32
+	XattrIndex     uint32
+}
+
+// symlinkInode is the on-disk representation of a symbolic link inode, excluding the target.
+type symlinkInode struct {
+	LinkCount  uint32
+	TargetSize uint32
+}
+
+// devInode is the on-disk representation of a basic device inode.
+type devInode struct {
+	LinkCount uint32
+	Device    uint32
+}
+
+// ipcInode is the on-disk representation of a basic fifo or socket inode.
+type ipcInode struct {
+	LinkCount uint32
+}
+
+// inode is a parsed inode.
+type inode struct {
+	inodeHeader
+
+	uid   uint32
+	gid   uint32
+	nlink uint32
+	xattr uint32 // Index into xattr ID table, or noXattr.
+
+	// Directories.
+	dirBlock  uint32 // Offset of directory listing metadata block, relative to directory table.
+	dirOffset uint16 // Offset of directory listing within metadata block.
+	dirSize   uint32 // Size of directory listing, plus 3.
+	parent    uint32 // Inode number of parent directory.
+
+	// Regular files.
+	blocksStart uint64   // Offset of first data block.
+	size        uint64   // File size.
+	fragment    uint32   // Index into fragment table, or noFragment.
+	fragOffset  uint32   // Offset of tail end within fragment block.
+	blockSizes  []uint32 // On-disk size of each data block.
+
+	// Symbolic links.
+	target string
+
+	// Devices.
+	rdev uint32
+}
+
+// mode returns the file mode of in.
+func (in *inode) mode() fs.FileMode {
+	m := fs.FileMode(in.Mode&0o777) | in.Type.fileMode()
+
+	if in.Mode&0o4000 != 0 {
+		m |= fs.ModeSetuid
+	}
+	if in.Mode&0o2000 != 0 {
+		m |= fs.ModeSetgid
+	}
+	if in.Mode&0o1000 != 0 {
+		m |= fs.ModeSticky
+	}
+
+	return m
+}
+
+// isDir reports whether in is a directory.
+func (in *inode) isDir() bool { return in.Type.basic() == inodeDir }
+
+// isSymlink reports whether in is a symbolic link.
+func (in *inode) isSymlink() bool { return in.Type.basic() == inodeSymlink }
+
+// isRegular reports whether in is a regular file.
+func (in *inode) isRegular() bool { return in.Type.basic() == inodeFile }
+
+// modTime returns the modification time of in.
+func (in *inode) modTime() time.Time { return time.Unix(int64(in.ModTime), 0) }
+
+// fileSize returns the size of in, as reported by fs.FileInfo.
+func (in *inode) fileSize() int64 {
+	switch {
+	case in.isRegular():
+		return int64(in.size) //nolint:gosec // Size validated when inode is read.
+	case in.isSymlink():
+		return int64(len(in.target))
+	case in.isDir():
+		return int64(in.dirSize)
+	}
+	return 0
+}
+
+// readInode reads the inode at reference ref.
+func (f *FS) readInode(ref uint64) (*inode, error) {
+	r, err := f.newMetadataReaderRef(int64(f.sb.InodeTableStart), ref) //nolint:gosec // Validated by ReadAt.
+	if err != nil {
+		return nil, err
+	}
+
+	in := &inode{xattr: noXattr}
+
+	if err := r.read(&in.inodeHeader); err != nil {
+		return nil, err
+	}
+
+	if err := f.readInodeBody(r, in); err != nil {
+		return nil, fmt.Errorf("inode %v: %w", in.InodeNumber, err)
+	}
+
+	if in.uid, err = f.lookupID(in.UIDIndex); err != nil {
+		return nil, fmt.Errorf("inode %v: %w", in.InodeNumber, err)
+	}
+
+	if in.gid, err = f.lookupID(in.GIDIndex); err != nil {
+		return nil, fmt.Errorf("inode %v: %w", in.InodeNumber, err)
+	}
+
+	return in, nil
+}
+
+// readInodeBody reads the type-specific portion of in from r.
+func (f *FS) readInodeBody(r *metadataReader, in *inode) error {
+	switch in.Type {
+	case inodeDir:
+		var d dirInode
+		if err := r.read(&d); err != nil {
+			return err
+		}
+		in.nlink, in.parent = d.LinkCount, d.ParentInode
+		in.dirBlock, in.dirOffset, in.dirSize = d.BlockStart, d.BlockOffset, uint32(d.FileSize)
+
+	case inodeExtDir:
+		var d extDirInode
+		if err := r.read(&d); err != nil {
+			return err
+		}
+		in.nlink, in.parent, in.xattr = d.LinkCount, d.ParentInode, d.XattrIndex
+		in.dirBlock, in.dirOffset, in.dirSize = d.BlockStart, d.BlockOffset, d.FileSize
+
+	case inodeFile:
+		var d fileInode
+		if err := r.read(&d); err != nil {
+			return err
+		}
+		in.nlink = 1
+		in.blocksStart, in.size = uint64(d.BlocksStart), uint64(d.FileSize)
+		in.fragment, in.fragOffset = d.FragmentIndex, d.FragmentOffset
+		return f.readBlockSizes(r, in)
+
+	case inodeExtFile:
+		var d extFileInode
+		if err := r.read(&d); err != nil {
+			return err
+		}
+		in.nlink, in.xattr = d.LinkCount, d.XattrIndex
+		in.blocksStart, in.size = d.BlocksStart, d.FileSize
+		in.fragment, in.fragOffset = d.FragmentIndex, d.FragmentOffset
+		return f.readBlockSizes(r, in)
+
+	case inodeSymlink, inodeExtSymlink:
+		var d symlinkInode
+		if err := r.read(&d); err != nil {
+			return err
+		}
+		if d.TargetSize > maxSymlinkSize {
+			return fmt.Errorf("%w: symlink target size %v", errCorruptInode, d.TargetSize)
+		}
+		target := make([]byte, d.TargetSize)
+		if err := r.read(target); err != nil {
+			return err
+		}
+		in.nlink, in.target = d.LinkCount, string(target)
+		if in.Type == inodeExtSymlink {
+			return r.read(&in.xattr)
+		}
+
+	case inodeBlockDev, inodeCharDev, inodeExtBlockDev, inodeExtCharDev:
+		var d devInode
+		if err := r.read(&d); err != nil {
+			return err
+		}
+		in.nlink, in.rdev = d.LinkCount, d.Device
+		if in.Type >= inodeExtDir {
+			return r.read(&in.xattr)
+		}
+
+	case inodeFifo, inodeSocket, inodeExtFifo, inodeExtSocket:
+		var d ipcInode
+		if err := r.read(&d); err != nil {
+			return err
+		}
+		in.nlink = d.LinkCount
+		if in.Type >= inodeExtDir {
+			return r.read(&in.xattr)
+		}
+
+	default:
+		return fmt.Errorf("%w: %v", errUnknownInodeType, in.Type)
+	}
+
+	return nil
+}
+
+// readBlockSizes reads the data block sizes of regular file in from r.
+func (f *FS) readBlockSizes(r *metadataReader, in *inode) error {
+	if in.size > 1<<62 {
+		return fmt.Errorf("%w: file size %v", errCorruptInode, in.size)
+	}
+
+	n := in.size / uint64(f.sb.BlockSize)
+	if in.fragment == noFragment && in.size%uint64(f.sb.BlockSize) != 0 {
+		n++
+	}
+
+	// Each block size occupies 4 bytes, so the count is bounded by the size of the filesystem.
+	if n*4 > f.sb.BytesUsed {
+		return fmt.Errorf("%w: %v data blocks", errCorruptInode, n)
+	}
+
+	in.blockSizes = make([]uint32, n)
+	return r.read(in.blockSizes)
+}
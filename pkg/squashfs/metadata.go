@@ -0,0 +1,185 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	metadataBlockSize = 8192 // Maximum uncompressed size of a metadata block.
+
+	metadataUncompressed = 0x8000 // Metadata block header flag indicating block is uncompressed.
+
+	maxCachedBlocks = 512 // Maximum number of decompressed blocks to cache.
+)
+
+var (
+	errEmptyMetadataBlock = errors.New("empty metadata block")
+	errCorruptTable       = errors.New("corrupt table")
+)
+
+// cachedBlock is a decompressed block.
+type cachedBlock struct {
+	data []byte
+	next int64 // Offset of the following block.
+}
+
+// blockCache caches decompressed blocks, indexed by their offset.
+type blockCache struct {
+	mu     sync.Mutex
+	blocks map[int64]cachedBlock
+}
+
+// get returns the block at offset off, if present.
+func (c *blockCache) get(off int64) (cachedBlock, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.blocks[off]
+	return b, ok
+}
+
+// add adds block b at offset off. If the cache is full, it is emptied first.
+func (c *blockCache) add(off int64, b cachedBlock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.blocks == nil || len(c.blocks) >= maxCachedBlocks {
+		c.blocks = make(map[int64]cachedBlock)
+	}
+	c.blocks[off] = b
+}
+
+// readMetadataBlock returns the decompressed metadata block at offset off.
+func (f *FS) readMetadataBlock(off int64) (cachedBlock, error) {
+	if b, ok := f.metadata.get(off); ok {
+		return b, nil
+	}
+
+	var h [2]byte
+	if _, err := f.r.ReadAt(h[:], off); err != nil {
+		return cachedBlock{}, err
+	}
+
+	header := binary.LittleEndian.Uint16(h[:])
+	size := int64(header &^ metadataUncompressed)
+	if size == 0 {
+		return cachedBlock{}, fmt.Errorf("%w at offset %v", errEmptyMetadataBlock, off)
+	}
+
+	data := make([]byte, size)
+	if _, err := f.r.ReadAt(data, off+2); err != nil {
+		return cachedBlock{}, err
+	}
+
+	if header&metadataUncompressed == 0 {
+		var err error
+		if data, err = f.dec.decompress(data, metadataBlockSize); err != nil {
+			return cachedBlock{}, fmt.Errorf("metadata block at offset %v: %w", off, err)
+		}
+	}
+
+	b := cachedBlock{data: data, next: off + 2 + size}
+	f.metadata.add(off, b)
+
+	return b, nil
+}
+
+// metadataReader reads a stream of data stored in consecutive metadata blocks.
+type metadataReader struct {
+	f    *FS
+	next int64  // Offset of next block.
+	buf  []byte // Unread data from current block.
+}
+
+// newMetadataReader returns a reader that reads metadata starting at offset off within the
+// metadata block at offset block.
+func (f *FS) newMetadataReader(block int64, off uint16) (*metadataReader, error) {
+	b, err := f.readMetadataBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(off) > len(b.data) {
+		return nil, fmt.Errorf("%w: offset %v exceeds metadata block size", errCorruptTable, off)
+	}
+
+	return &metadataReader{f: f, next: b.next, buf: b.data[off:]}, nil
+}
+
+// newMetadataReaderRef returns a reader that reads metadata at reference ref, relative to the
+// table at offset start. The upper bits of ref contain the offset of the metadata block relative
+// to start, and the lower 16 bits contain the offset within the block.
+func (f *FS) newMetadataReaderRef(start int64, ref uint64) (*metadataReader, error) {
+	return f.newMetadataReader(start+int64(ref>>16), uint16(ref))
+}
+
+// Read reads data into p, crossing block boundaries as required.
+func (r *metadataReader) Read(p []byte) (int, error) {
+	var n int
+
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			b, err := r.f.readMetadataBlock(r.next)
+			if err != nil {
+				if n > 0 && errors.Is(err, io.EOF) {
+					err = io.ErrUnexpectedEOF
+				}
+				return n, err
+			}
+			r.next, r.buf = b.next, b.data
+		}
+
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+
+	return n, nil
+}
+
+// read reads binary data from r into data.
+func (r *metadataReader) read(data any) error {
+	return binary.Read(r, binary.LittleEndian, data)
+}
+
+// readTable reads a table of count entries, each of size entrySize, that is stored in metadata
+// blocks. The table is located using the array of metadata block offsets at offset start.
+func (f *FS) readTable(start int64, count, entrySize int) ([]byte, error) {
+	size := count * entrySize
+	if size == 0 {
+		return nil, nil
+	}
+
+	blocks := make([]uint64, (size+metadataBlockSize-1)/metadataBlockSize)
+
+	err := binary.Read(io.NewSectionReader(f.r, start, int64(len(blocks))*8), binary.LittleEndian, blocks)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errCorruptTable, err)
+	}
+
+	table := make([]byte, 0, size)
+
+	for _, off := range blocks {
+		b, err := f.readMetadataBlock(int64(off)) //nolint:gosec // Overflow is detected by ReadAt.
+		if err != nil {
+			return nil, err
+		}
+
+		table = append(table, b.data...)
+	}
+
+	if len(table) < size {
+		return nil, fmt.Errorf("%w: got %v bytes, want %v", errCorruptTable, len(table), size)
+	}
+
+	return table[:size], nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	magic = 0x73717368 // "hsqs"
+
+	versionMajor = 4
+	versionMinor = 0
+
+	minBlockLog = 12 // 4KiB
+	maxBlockLog = 20 // 1MiB
+
+	noTable = 0xffffffffffffffff // Table start indicating the table is not present.
+)
+
+// compression identifies the compression algorithm used by a squashfs filesystem.
+type compression uint16
+
+// List of compression algorithms.
+const (
+	compressionGzip compression = iota + 1 // gzip (zlib)
+	compressionLZMA                        // lzma
+	compressionLZO                         // lzo
+	compressionXZ                          // xz
+	compressionLZ4                         // lz4
+	compressionZstd                        // zstd
+)
+
+// String returns a human-readable representation of c.
+func (c compression) String() string {
+	switch c {
+	case compressionGzip:
+		return "gzip"
+	case compressionLZMA:
+		return "lzma"
+	case compressionLZO:
+		return "lzo"
+	case compressionXZ:
+		return "xz"
+	case compressionLZ4:
+		return "lz4"
+	case compressionZstd:
+		return "zstd"
+	}
+	return fmt.Sprintf("unknown (%d)", uint16(c))
+}
+
+// superblock is the on-disk representation of the squashfs superblock.
+type superblock struct {
+	Magic               uint32
+	InodeCount          uint32
+	ModTime             uint32
+	BlockSize           uint32
+	FragmentCount       uint32
+	Compression         compression
+	BlockLog            uint16
+	Flags               uint16
+	IDCount             uint16
+	VersionMajor        uint16
+	VersionMinor        uint16
+	RootInode           uint64
+	BytesUsed           uint64
+	IDTableStart        uint64
+	XattrIDTableStart   uint64
+	InodeTableStart     uint64
+	DirectoryTableStart uint64
+	FragmentTableStart  uint64
+	ExportTableStart    uint64
+}
+
+// superblockSize is the size of the on-disk superblock, in bytes.
+const superblockSize = 96
+
+var (
+	errInvalidMagic       = errors.New("invalid squashfs magic")
+	errUnsupportedVersion = errors.New("unsupported squashfs version")
+	errInvalidBlockSize   = errors.New("invalid block size")
+)
+
+// readSuperblock reads and validates the superblock from r.
+func readSuperblock(r io.ReaderAt) (superblock, error) {
+	var sb superblock
+
+	err := binary.Read(io.NewSectionReader(r, 0, superblockSize), binary.LittleEndian, &sb)
+	if err != nil {
+		return superblock{}, err
+	}
+
+	if sb.Magic != magic {
+		return superblock{}, errInvalidMagic
+	}
+
+	if sb.VersionMajor != versionMajor || sb.VersionMinor != versionMinor {
+		return superblock{}, fmt.Errorf("%w: %v.%v", errUnsupportedVersion, sb.VersionMajor, sb.VersionMinor)
+	}
+
+	if sb.BlockLog < minBlockLog || sb.BlockLog > maxBlockLog || sb.BlockSize != 1<<sb.BlockLog {
+		return superblock{}, fmt.Errorf("%w: %v", errInvalidBlockSize, sb.BlockSize)
+	}
+
+	return sb, nil
+}
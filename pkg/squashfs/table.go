@@ -0,0 +1,170 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	idEntrySize       = 4  // Size of on-disk ID table entry.
+	fragmentEntrySize = 16 // Size of on-disk fragment table entry.
+	xattrIDEntrySize  = 16 // Size of on-disk xattr ID table entry.
+)
+
+var (
+	errInvalidIDIndex       = errors.New("invalid ID index")
+	errInvalidFragmentIndex = errors.New("invalid fragment index")
+	errInvalidXattrIndex    = errors.New("invalid xattr index")
+)
+
+// tables holds lookup tables, which are read on first use.
+type tables struct {
+	idsOnce sync.Once
+	ids     []uint32
+	idsErr  error
+
+	fragmentsOnce sync.Once
+	fragments     []fragmentEntry
+	fragmentsErr  error
+
+	xattrsOnce sync.Once
+	xattrStart int64 // Offset of xattr key/value metadata.
+	xattrIDs   []xattrIDEntry
+	xattrsErr  error
+}
+
+// lookupID returns the ID at index i of the ID table.
+func (f *FS) lookupID(i uint16) (uint32, error) {
+	f.tables.idsOnce.Do(func() {
+		b, err := f.readTable(int64(f.sb.IDTableStart), int(f.sb.IDCount), idEntrySize) //nolint:gosec // Validated by ReadAt.
+		if err != nil {
+			f.tables.idsErr = fmt.Errorf("ID table: %w", err)
+			return
+		}
+
+		f.tables.ids = make([]uint32, f.sb.IDCount)
+		for i := range f.tables.ids {
+			f.tables.ids[i] = binary.LittleEndian.Uint32(b[i*idEntrySize:])
+		}
+	})
+
+	if f.tables.idsErr != nil {
+		return 0, f.tables.idsErr
+	}
+
+	if int(i) >= len(f.tables.ids) {
+		return 0, fmt.Errorf("%w: %v", errInvalidIDIndex, i)
+	}
+
+	return f.tables.ids[i], nil
+}
+
+// fragmentEntry is the on-disk representation of a fragment table entry.
+type fragmentEntry struct {
+	Start  uint64
+	Size   uint32
+	Unused uint32
+}
+
+// lookupFragment returns the entry at index i of the fragment table.
+func (f *FS) lookupFragment(i uint32) (fragmentEntry, error) {
+	f.tables.fragmentsOnce.Do(func() {
+		b, err := f.readTable(
+			int64(f.sb.FragmentTableStart), //nolint:gosec // Validated by ReadAt.
+			int(f.sb.FragmentCount),
+			fragmentEntrySize,
+		)
+		if err != nil {
+			f.tables.fragmentsErr = fmt.Errorf("fragment table: %w", err)
+			return
+		}
+
+		f.tables.fragments = make([]fragmentEntry, f.sb.FragmentCount)
+		for i := range f.tables.fragments {
+			e := b[i*fragmentEntrySize:]
+			f.tables.fragments[i] = fragmentEntry{
+				Start: binary.LittleEndian.Uint64(e),
+				Size:  binary.LittleEndian.Uint32(e[8:]),
+			}
+		}
+	})
+
+	if f.tables.fragmentsErr != nil {
+		return fragmentEntry{}, f.tables.fragmentsErr
+	}
+
+	if int(i) >= len(f.tables.fragments) {
+		return fragmentEntry{}, fmt.Errorf("%w: %v", errInvalidFragmentIndex, i)
+	}
+
+	return f.tables.fragments[i], nil
+}
+
+// xattrIDEntry is the on-disk representation of an xattr ID table entry.
+type xattrIDEntry struct {
+	Ref   uint64 // Reference to first key/value pair.
+	Count uint32 // Number of key/value pairs.
+	Size  uint32 // Total size of key/value pairs.
+}
+
+// xattrTableHeader is the on-disk representation of the xattr ID table header.
+type xattrTableHeader struct {
+	XattrTableStart uint64
+	XattrIDs        uint32
+	Unused          uint32
+}
+
+// xattrTableHeaderSize is the size of the on-disk xattr ID table header.
+const xattrTableHeaderSize = 16
+
+// lookupXattrID returns the entry at index i of the xattr ID table.
+func (f *FS) lookupXattrID(i uint32) (xattrIDEntry, error) {
+	f.tables.xattrsOnce.Do(func() {
+		if f.sb.XattrIDTableStart == noTable {
+			return
+		}
+
+		start := int64(f.sb.XattrIDTableStart) //nolint:gosec // Validated by ReadAt.
+
+		var h xattrTableHeader
+		if err := binary.Read(io.NewSectionReader(f.r, start, xattrTableHeaderSize), binary.LittleEndian, &h); err != nil {
+			f.tables.xattrsErr = fmt.Errorf("xattr table: %w", err)
+			return
+		}
+
+		b, err := f.readTable(start+xattrTableHeaderSize, int(h.XattrIDs), xattrIDEntrySize)
+		if err != nil {
+			f.tables.xattrsErr = fmt.Errorf("xattr table: %w", err)
+			return
+		}
+
+		f.tables.xattrStart = int64(h.XattrTableStart) //nolint:gosec // Validated by ReadAt.
+		f.tables.xattrIDs = make([]xattrIDEntry, h.XattrIDs)
+		for i := range f.tables.xattrIDs {
+			e := b[i*xattrIDEntrySize:]
+			f.tables.xattrIDs[i] = xattrIDEntry{
+				Ref:   binary.LittleEndian.Uint64(e),
+				Count: binary.LittleEndian.Uint32(e[8:]),
+				Size:  binary.LittleEndian.Uint32(e[12:]),
+			}
+		}
+	})
+
+	if f.tables.xattrsErr != nil {
+		return xattrIDEntry{}, f.tables.xattrsErr
+	}
+
+	if int(i) >= len(f.tables.xattrIDs) {
+		return xattrIDEntry{}, fmt.Errorf("%w: %v", errInvalidXattrIndex, i)
+	}
+
+	return f.tables.xattrIDs[i], nil
+}
@@ -0,0 +1,728 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// testEntry describes an entry in a test filesystem.
+type testEntry struct {
+	path         string
+	mode         fs.FileMode // Type and permission bits.
+	uid, gid     uint32
+	mtime        uint32
+	data         []byte            // Contents of regular file.
+	target       string            // Target of symbolic link.
+	link         string            // Path of regular file to hard link to.
+	major, minor uint32            // Device numbers.
+	xattrs       map[string]string // Extended attributes.
+}
+
+// testWriterOpts are options for writeTestFS.
+type testWriterOpts struct {
+	comp         compression
+	blockLog     uint16
+	uncompressed bool // Store all blocks uncompressed.
+	noFragments  bool // Store tail ends in data blocks.
+}
+
+// testNode is a node in a test filesystem.
+type testNode struct {
+	testEntry
+
+	children []testChild
+	number   uint32
+	parent   uint32
+	nlink    uint32
+	ref      uint64 // Inode reference, once written.
+	written  bool
+
+	blocksStart uint64
+	blockSizes  []uint32
+	fragment    uint32
+	fragOffset  uint32
+}
+
+// testChild is an entry in a test directory.
+type testChild struct {
+	name string
+	n    *testNode
+}
+
+// metaWriter writes a stream of data to consecutive metadata blocks.
+type metaWriter struct {
+	w      *testWriter
+	out    bytes.Buffer
+	buf    []byte
+	blocks []int // Offset of each block within out.
+	size   int   // Total uncompressed size written.
+}
+
+// ref returns a reference to the current position.
+func (m *metaWriter) ref() uint64 { return uint64(m.out.Len())<<16 | uint64(len(m.buf)) }
+
+func (m *metaWriter) write(data any) {
+	var b bytes.Buffer
+	if err := binary.Write(&b, binary.LittleEndian, data); err != nil {
+		panic(err)
+	}
+	m.buf = append(m.buf, b.Bytes()...)
+	m.size += b.Len()
+
+	for len(m.buf) >= metadataBlockSize {
+		m.flush(m.buf[:metadataBlockSize])
+		m.buf = m.buf[metadataBlockSize:]
+	}
+}
+
+func (m *metaWriter) flush(b []byte) {
+	m.blocks = append(m.blocks, m.out.Len())
+
+	data, compressed := m.w.compress(b)
+
+	header := uint16(len(data))
+	if !compressed {
+		header |= metadataUncompressed
+	}
+
+	m.write16(header)
+	m.out.Write(data)
+}
+
+func (m *metaWriter) write16(v uint16) {
+	m.out.Write(binary.LittleEndian.AppendUint16(nil, v))
+}
+
+func (m *metaWriter) finish() {
+	if len(m.buf) > 0 {
+		m.flush(m.buf)
+		m.buf = nil
+	}
+}
+
+// testWriter writes a squashfs filesystem.
+type testWriter struct {
+	opts      testWriterOpts
+	blockSize int
+
+	out       bytes.Buffer
+	inodes    metaWriter
+	dirs      metaWriter
+	xattrs    metaWriter
+	fragBuf   []byte
+	fragments []fragmentEntry
+	ids       []uint32
+	xattrIDs  []xattrIDEntry
+	oolValues map[string]uint64 // References to previously written xattr values.
+}
+
+// lz4AppendLength appends the extension bytes encoding length n to b.
+func lz4AppendLength(b []byte, n int) []byte {
+	for ; n >= 0xff; n -= 0xff {
+		b = append(b, 0xff)
+	}
+	return append(b, byte(n))
+}
+
+// lz4AppendSequence appends an lz4 sequence to b, consisting of literals lit, followed by a match
+// of length n at offset off. If off is zero, the sequence contains only literals.
+func lz4AppendSequence(b, lit []byte, off, n int) []byte {
+	token := byte(min(len(lit), 0xf)) << 4
+	if off != 0 {
+		token |= byte(min(n-4, 0xf))
+	}
+
+	b = append(b, token)
+	if len(lit) >= 0xf {
+		b = lz4AppendLength(b, len(lit)-0xf)
+	}
+	b = append(b, lit...)
+
+	if off == 0 {
+		return b
+	}
+
+	b = binary.LittleEndian.AppendUint16(b, uint16(off))
+	if n-4 >= 0xf {
+		b = lz4AppendLength(b, n-4-0xf)
+	}
+	return b
+}
+
+// lz4Compress returns b compressed using the lz4 block format, using a simple greedy matcher.
+func lz4Compress(b []byte) []byte {
+	var c []byte
+
+	// The last five bytes of a block are literals.
+	end := len(b) - 5
+
+	prev := make(map[uint32]int)
+	anchor := 0
+
+	for i := 0; i+4 <= end; {
+		k := binary.LittleEndian.Uint32(b[i:])
+
+		j, ok := prev[k]
+		prev[k] = i
+
+		if !ok || i-j > 0xffff {
+			i++
+			continue
+		}
+
+		n := 4
+		for i+n < end && b[j+n] == b[i+n] {
+			n++
+		}
+
+		c = lz4AppendSequence(c, b[anchor:i], i-j, n)
+		i += n
+		anchor = i
+	}
+
+	return lz4AppendSequence(c, b[anchor:], 0, 0)
+}
+
+func (w *testWriter) compress(b []byte) ([]byte, bool) {
+	if w.opts.uncompressed {
+		return b, false
+	}
+
+	var c []byte
+
+	switch w.opts.comp {
+	case compressionGzip:
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(b); err != nil {
+			panic(err)
+		}
+		if err := zw.Close(); err != nil {
+			panic(err)
+		}
+		c = buf.Bytes()
+
+	case compressionLZMA:
+		var buf bytes.Buffer
+		lw, err := lzma.WriterConfig{DictCap: w.blockSize, SizeInHeader: true, Size: int64(len(b))}.NewWriter(&buf)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := lw.Write(b); err != nil {
+			panic(err)
+		}
+		if err := lw.Close(); err != nil {
+			panic(err)
+		}
+		c = buf.Bytes()
+
+	case compressionXZ:
+		var buf bytes.Buffer
+		xw, err := xz.WriterConfig{DictCap: w.blockSize, CheckSum: xz.CRC32}.NewWriter(&buf)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := xw.Write(b); err != nil {
+			panic(err)
+		}
+		if err := xw.Close(); err != nil {
+			panic(err)
+		}
+		c = buf.Bytes()
+
+	case compressionLZ4:
+		c = lz4Compress(b)
+
+	case compressionZstd:
+		e, err := zstd.NewWriter(nil)
+		if err != nil {
+			panic(err)
+		}
+		c = e.EncodeAll(b, nil)
+
+	default:
+		panic("unsupported compression")
+	}
+
+	if len(c) < len(b) {
+		return c, true
+	}
+	return b, false
+}
+
+// writeBlock writes data block b, and returns its size field.
+func (w *testWriter) writeBlock(b []byte) uint32 {
+	if !slices.ContainsFunc(b, func(c byte) bool { return c != 0 }) {
+		return 0 // Sparse.
+	}
+
+	data, compressed := w.compress(b)
+	w.out.Write(data)
+
+	size := uint32(len(data))
+	if !compressed {
+		size |= dataBlockUncompressed
+	}
+	return size
+}
+
+func (w *testWriter) flushFragment() {
+	if len(w.fragBuf) == 0 {
+		return
+	}
+
+	start := uint64(w.out.Len())
+
+	data, compressed := w.compress(w.fragBuf)
+	w.out.Write(data)
+
+	size := uint32(len(data))
+	if !compressed {
+		size |= dataBlockUncompressed
+	}
+
+	w.fragments = append(w.fragments, fragmentEntry{Start: start, Size: size})
+	w.fragBuf = nil
+}
+
+// writeData writes the contents of regular file n.
+func (w *testWriter) writeData(n *testNode) {
+	n.blocksStart = uint64(w.out.Len())
+	n.fragment = noFragment
+
+	data := n.data
+	for len(data) >= w.blockSize {
+		n.blockSizes = append(n.blockSizes, w.writeBlock(data[:w.blockSize]))
+		data = data[w.blockSize:]
+	}
+
+	if len(data) == 0 {
+		return
+	}
+
+	if w.opts.noFragments {
+		n.blockSizes = append(n.blockSizes, w.writeBlock(data))
+		return
+	}
+
+	if len(w.fragBuf)+len(data) > w.blockSize {
+		w.flushFragment()
+	}
+
+	n.fragment = uint32(len(w.fragments))
+	n.fragOffset = uint32(len(w.fragBuf))
+	w.fragBuf = append(w.fragBuf, data...)
+}
+
+func (w *testWriter) idIndex(id uint32) uint16 {
+	i := slices.Index(w.ids, id)
+	if i < 0 {
+		i = len(w.ids)
+		w.ids = append(w.ids, id)
+	}
+	return uint16(i)
+}
+
+// writeXattrs writes the extended attributes of n, and returns the xattr ID.
+func (w *testWriter) writeXattrs(n *testNode) uint32 {
+	if len(n.xattrs) == 0 {
+		return noXattr
+	}
+
+	e := xattrIDEntry{Ref: w.xattrs.ref(), Count: uint32(len(n.xattrs))}
+	start := w.xattrs.size
+
+	keys := make([]string, 0, len(n.xattrs))
+	for k := range n.xattrs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		t := slices.IndexFunc(xattrPrefixes, func(p string) bool { return strings.HasPrefix(k, p) })
+		name := strings.TrimPrefix(k, xattrPrefixes[t])
+		v := n.xattrs[k]
+
+		if ref, ok := w.oolValues[v]; ok {
+			w.xattrs.write(xattrKey{Type: uint16(t) | xattrOOL, NameSize: uint16(len(name))})
+			w.xattrs.write([]byte(name))
+			w.xattrs.write(uint32(8))
+			w.xattrs.write(ref)
+			continue
+		}
+
+		w.xattrs.write(xattrKey{Type: uint16(t), NameSize: uint16(len(name))})
+		w.xattrs.write([]byte(name))
+		w.oolValues[v] = w.xattrs.ref()
+		w.xattrs.write(uint32(len(v)))
+		w.xattrs.write([]byte(v))
+	}
+
+	e.Size = uint32(w.xattrs.size - start)
+	w.xattrIDs = append(w.xattrIDs, e)
+
+	return uint32(len(w.xattrIDs) - 1)
+}
+
+// unixMode returns the mode of n, as stored in an inode.
+func (n *testNode) unixMode() uint16 {
+	m := uint16(n.mode.Perm())
+
+	if n.mode&fs.ModeSetuid != 0 {
+		m |= 0o4000
+	}
+	if n.mode&fs.ModeSetgid != 0 {
+		m |= 0o2000
+	}
+	if n.mode&fs.ModeSticky != 0 {
+		m |= 0o1000
+	}
+
+	switch n.inodeType().basic() {
+	case inodeDir:
+		m |= 0o040000
+	case inodeFile:
+		m |= 0o100000
+	case inodeSymlink:
+		m |= 0o120000
+	case inodeBlockDev:
+		m |= 0o060000
+	case inodeCharDev:
+		m |= 0o020000
+	case inodeFifo:
+		m |= 0o010000
+	case inodeSocket:
+		m |= 0o140000
+	}
+
+	return m
+}
+
+// inodeType returns the basic inode type of n.
+func (n *testNode) inodeType() inodeType {
+	switch {
+	case n.mode.IsDir():
+		return inodeDir
+	case n.mode&fs.ModeSymlink != 0:
+		return inodeSymlink
+	case n.mode&fs.ModeCharDevice != 0:
+		return inodeCharDev
+	case n.mode&fs.ModeDevice != 0:
+		return inodeBlockDev
+	case n.mode&fs.ModeNamedPipe != 0:
+		return inodeFifo
+	case n.mode&fs.ModeSocket != 0:
+		return inodeSocket
+	}
+	return inodeFile
+}
+
+// writeDir writes the directory listing of n, and returns its location and size.
+func (w *testWriter) writeDir(n *testNode) (uint32, uint16, uint32) {
+	ref := w.dirs.ref()
+	start := w.dirs.size
+
+	for children := n.children; len(children) > 0; {
+		block := uint32(children[0].n.ref >> 16)
+		base := children[0].n.number
+
+		count := 0
+		for count < len(children) && count < maxDirEntries &&
+			uint32(children[count].n.ref>>16) == block &&
+			int64(children[count].n.number)-int64(base) <= 32767 &&
+			int64(children[count].n.number)-int64(base) >= -32768 {
+			count++
+		}
+
+		w.dirs.write(dirHeader{Count: uint32(count - 1), Start: block, InodeNumber: base})
+
+		for _, c := range children[:count] {
+			w.dirs.write(dirEntryHeader{
+				Offset:      uint16(c.n.ref),
+				InodeOffset: int16(int64(c.n.number) - int64(base)),
+				Type:        c.n.inodeType(),
+				NameSize:    uint16(len(c.name) - 1),
+			})
+			w.dirs.write([]byte(c.name))
+		}
+
+		children = children[count:]
+	}
+
+	size := w.dirs.size - start + dirSizeOffset
+
+	return uint32(ref >> 16), uint16(ref), uint32(size)
+}
+
+// writeNode writes the inode of n, writing the inodes of any children first.
+func (w *testWriter) writeNode(n *testNode) {
+	if n.written {
+		return
+	}
+	n.written = true
+
+	for _, c := range n.children {
+		w.writeNode(c.n)
+	}
+
+	var (
+		dirBlock  uint32
+		dirOffset uint16
+		dirSize   uint32
+	)
+	if n.mode.IsDir() {
+		dirBlock, dirOffset, dirSize = w.writeDir(n)
+	}
+
+	xattr := w.writeXattrs(n)
+	ext := xattr != noXattr
+
+	t := n.inodeType()
+	if ext || (t == inodeFile && n.nlink > 1) || (t == inodeDir && dirSize > 0xffff) {
+		t += inodeExtDir - inodeDir
+	}
+
+	n.ref = w.inodes.ref()
+
+	w.inodes.write(inodeHeader{
+		Type:        t,
+		Mode:        n.unixMode(),
+		UIDIndex:    w.idIndex(n.uid),
+		GIDIndex:    w.idIndex(n.gid),
+		ModTime:     n.mtime,
+		InodeNumber: n.number,
+	})
+
+	switch t {
+	case inodeDir:
+		w.inodes.write(dirInode{
+			BlockStart:  dirBlock,
+			LinkCount:   n.nlink,
+			FileSize:    uint16(dirSize),
+			BlockOffset: dirOffset,
+			ParentInode: n.parent,
+		})
+
+	case inodeExtDir:
+		w.inodes.write(extDirInode{
+			LinkCount:   n.nlink,
+			FileSize:    dirSize,
+			BlockStart:  dirBlock,
+			ParentInode: n.parent,
+			BlockOffset: dirOffset,
+			XattrIndex:  xattr,
+		})
+
+	case inodeFile:
+		w.inodes.write(fileInode{
+			BlocksStart:    uint32(n.blocksStart),
+			FragmentIndex:  n.fragment,
+			FragmentOffset: n.fragOffset,
+			FileSize:       uint32(len(n.data)),
+		})
+		w.inodes.write(n.blockSizes)
+
+	case inodeExtFile:
+		w.inodes.write(extFileInode{
+			BlocksStart:    n.blocksStart,
+			FileSize:       uint64(len(n.data)),
+			LinkCount:      n.nlink,
+			FragmentIndex:  n.fragment,
+			FragmentOffset: n.fragOffset,
+			XattrIndex:     xattr,
+		})
+		w.inodes.write(n.blockSizes)
+
+	case inodeSymlink, inodeExtSymlink:
+		w.inodes.write(symlinkInode{LinkCount: n.nlink, TargetSize: uint32(len(n.target))})
+		w.inodes.write([]byte(n.target))
+
+	case inodeBlockDev, inodeCharDev, inodeExtBlockDev, inodeExtCharDev:
+		rdev := (n.minor & 0xff) | (n.major << 8) | ((n.minor &^ 0xff) << 12)
+		w.inodes.write(devInode{LinkCount: n.nlink, Device: rdev})
+
+	case inodeFifo, inodeSocket, inodeExtFifo, inodeExtSocket:
+		w.inodes.write(ipcInode{LinkCount: n.nlink})
+	}
+
+	if ext && t != inodeExtDir && t != inodeExtFile {
+		w.inodes.write(xattr)
+	}
+}
+
+// writeTable writes a table stored in metadata m, followed by the array of metadata block
+// offsets, and returns the offset of the array.
+func (w *testWriter) writeTable(m *metaWriter) uint64 {
+	m.finish()
+
+	start := w.out.Len()
+	w.out.Write(m.out.Bytes())
+
+	table := uint64(w.out.Len())
+	for _, off := range m.blocks {
+		w.out.Write(binary.LittleEndian.AppendUint64(nil, uint64(start+off)))
+	}
+	return table
+}
+
+// writeTestFS returns a squashfs filesystem containing the specified entries. Parent
+// directories must precede their children. The root directory is created implicitly.
+func writeTestFS(t *testing.T, entries []testEntry, opts testWriterOpts) []byte {
+	t.Helper()
+
+	if opts.comp == 0 {
+		opts.comp = compressionGzip
+	}
+	if opts.blockLog == 0 {
+		opts.blockLog = minBlockLog
+	}
+
+	w := &testWriter{
+		opts:      opts,
+		blockSize: 1 << opts.blockLog,
+		oolValues: make(map[string]uint64),
+	}
+	w.inodes.w, w.dirs.w, w.xattrs.w = w, w, w
+
+	root := &testNode{testEntry: testEntry{path: ".", mode: fs.ModeDir | 0o755}, nlink: 2}
+	nodes := map[string]*testNode{".": root}
+	order := []*testNode{root}
+
+	for _, e := range entries {
+		parent, ok := nodes[path.Dir(e.path)]
+		if !ok {
+			t.Fatalf("parent of %v not found", e.path)
+		}
+
+		name := path.Base(e.path)
+
+		if e.link != "" {
+			n, ok := nodes[e.link]
+			if !ok {
+				t.Fatalf("link target %v not found", e.link)
+			}
+			n.nlink++
+			parent.children = append(parent.children, testChild{name, n})
+			continue
+		}
+
+		n := &testNode{testEntry: e, nlink: 1}
+
+		if e.mode.IsDir() {
+			n.nlink = 2
+			parent.nlink++
+		}
+
+		nodes[e.path] = n
+		order = append(order, n)
+		parent.children = append(parent.children, testChild{name, n})
+	}
+
+	// Sort children, and assign inode numbers.
+	for i, n := range order {
+		slices.SortFunc(n.children, func(a, b testChild) int { return strings.Compare(a.name, b.name) })
+
+		n.number = uint32(i + 1)
+		for _, c := range n.children {
+			c.n.parent = n.number
+		}
+	}
+	root.parent = uint32(len(order) + 1)
+
+	// Write superblock placeholder, then data.
+	w.out.Write(make([]byte, superblockSize))
+
+	for _, n := range order {
+		if n.inodeType() == inodeFile {
+			w.writeData(n)
+		}
+	}
+	w.flushFragment()
+
+	w.writeNode(root)
+
+	sb := superblock{
+		Magic:            magic,
+		InodeCount:       uint32(len(order)),
+		BlockSize:        uint32(w.blockSize),
+		FragmentCount:    uint32(len(w.fragments)),
+		Compression:      opts.comp,
+		BlockLog:         opts.blockLog,
+		VersionMajor:     versionMajor,
+		VersionMinor:     versionMinor,
+		RootInode:        root.ref,
+		ExportTableStart: noTable,
+	}
+
+	w.inodes.finish()
+	sb.InodeTableStart = uint64(w.out.Len())
+	w.out.Write(w.inodes.out.Bytes())
+
+	w.dirs.finish()
+	sb.DirectoryTableStart = uint64(w.out.Len())
+	w.out.Write(w.dirs.out.Bytes())
+
+	frags := metaWriter{w: w}
+	for _, e := range w.fragments {
+		frags.write(e)
+	}
+	sb.FragmentTableStart = w.writeTable(&frags)
+
+	ids := metaWriter{w: w}
+	ids.write(w.ids)
+	sb.IDTableStart = w.writeTable(&ids)
+	sb.IDCount = uint16(len(w.ids))
+
+	sb.XattrIDTableStart = noTable
+	if len(w.xattrIDs) > 0 {
+		w.xattrs.finish()
+		xattrStart := uint64(w.out.Len())
+		w.out.Write(w.xattrs.out.Bytes())
+
+		xids := metaWriter{w: w}
+		xids.write(w.xattrIDs)
+		xids.finish()
+
+		start := w.out.Len()
+		w.out.Write(xids.out.Bytes())
+
+		sb.XattrIDTableStart = uint64(w.out.Len())
+		w.write(xattrTableHeader{XattrTableStart: xattrStart, XattrIDs: uint32(len(w.xattrIDs))})
+		for _, off := range xids.blocks {
+			w.write(uint64(start + off))
+		}
+	}
+
+	sb.BytesUsed = uint64(w.out.Len())
+
+	b := w.out.Bytes()
+
+	var h bytes.Buffer
+	if err := binary.Write(&h, binary.LittleEndian, sb); err != nil {
+		t.Fatal(err)
+	}
+	copy(b, h.Bytes())
+
+	return b
+}
+
+func (w *testWriter) write(data any) {
+	if err := binary.Write(&w.out, binary.LittleEndian, data); err != nil {
+		panic(err)
+	}
+}
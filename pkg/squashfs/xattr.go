@@ -0,0 +1,109 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package squashfs
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	xattrTypeMask = 0x00ff // Mask of xattr key type, which selects the key prefix.
+	xattrOOL      = 0x0100 // Xattr key type flag indicating value is stored out of line.
+
+	maxXattrValueSize = 1 << 16 // Maximum size of xattr value.
+)
+
+// xattrPrefixes are the key prefixes corresponding to each xattr key type.
+var xattrPrefixes = []string{"user.", "trusted.", "security."}
+
+var errCorruptXattr = errors.New("corrupt xattr")
+
+// xattrKey is the on-disk representation of an xattr key, excluding name.
+type xattrKey struct {
+	Type     uint16
+	NameSize uint16
+}
+
+// readXattrValue reads an xattr value from r.
+func readXattrValue(r *metadataReader) ([]byte, error) {
+	var size uint32
+	if err := r.read(&size); err != nil {
+		return nil, err
+	}
+
+	if size > maxXattrValueSize {
+		return nil, fmt.Errorf("%w: value size %v", errCorruptXattr, size)
+	}
+
+	b := make([]byte, size)
+	if err := r.read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readXattrs returns the extended attributes of in.
+func (f *FS) readXattrs(in *inode) (map[string][]byte, error) {
+	if in.xattr == noXattr {
+		return nil, nil
+	}
+
+	e, err := f.lookupXattrID(in.xattr)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := f.newMetadataReaderRef(f.tables.xattrStart, e.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	xattrs := make(map[string][]byte)
+
+	for range e.Count {
+		var k xattrKey
+		if err := r.read(&k); err != nil {
+			return nil, err
+		}
+
+		t := int(k.Type & xattrTypeMask)
+		if t >= len(xattrPrefixes) {
+			return nil, fmt.Errorf("%w: key type %v", errCorruptXattr, k.Type)
+		}
+
+		name := make([]byte, k.NameSize)
+		if err := r.read(name); err != nil {
+			return nil, err
+		}
+
+		v, err := readXattrValue(r)
+		if err != nil {
+			return nil, err
+		}
+
+		// Out of line values are stored elsewhere, and referenced by the value.
+		if k.Type&xattrOOL != 0 {
+			if len(v) != 8 {
+				return nil, fmt.Errorf("%w: out of line reference size %v", errCorruptXattr, len(v))
+			}
+
+			or, err := f.newMetadataReaderRef(f.tables.xattrStart, binary.LittleEndian.Uint64(v))
+			if err != nil {
+				return nil, err
+			}
+
+			if v, err = readXattrValue(or); err != nil {
+				return nil, err
+			}
+		}
+
+		xattrs[xattrPrefixes[t]+string(name)] = v
+	}
+
+	return xattrs, nil
+}
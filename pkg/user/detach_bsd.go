@@ -0,0 +1,18 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+//go:build darwin || freebsd
+
+package user
+
+import "errors"
+
+var errDetachNotSupported = errors.New("lazy unmount not supported")
+
+// unmountDetach lazily unmounts the filesystem at path. This is not supported on this platform,
+// so fusermount is used instead.
+func unmountDetach(string) error {
+	return errDetachNotSupported
+}
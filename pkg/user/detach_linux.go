@@ -0,0 +1,15 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+//go:build linux
+
+package user
+
+import "golang.org/x/sys/unix"
+
+// unmountDetach lazily unmounts the filesystem at path, which requires privilege.
+func unmountDetach(path string) error {
+	return unix.Unmount(path, unix.MNT_DETACH)
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+//go:build !(linux || darwin || freebsd)
+
+package user
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var errFUSENotSupported = errors.New("FUSE not supported on this platform")
+
+// serveSection mounts a FUSE filesystem that exposes the contents of r as a file. This is not
+// supported on this platform.
+func serveSection(string, string, *io.SectionReader, func() error) (func() error, string, error) {
+	return nil, "", errFUSENotSupported
+}
+
+// detach lazily unmounts the filesystem at mountPath. This is not supported on this platform.
+func detach(context.Context, string, mountOpts) error {
+	return errFUSENotSupported
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+//go:build linux || darwin || freebsd
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+)
+
+// sectionFile is a read-only FUSE file node that exposes the contents of r.
+type sectionFile struct {
+	fs.Inode
+
+	r *io.SectionReader
+}
+
+var (
+	_ fs.NodeGetattrer = (*sectionFile)(nil)
+	_ fs.NodeOpener    = (*sectionFile)(nil)
+	_ fs.NodeReader    = (*sectionFile)(nil)
+)
+
+func (f *sectionFile) Getattr(_ context.Context, _ fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	out.Mode = syscall.S_IFREG | 0o400
+	out.Size = uint64(f.r.Size()) //nolint:gosec // Size is non-negative.
+	return fs.OK
+}
+
+func (f *sectionFile) Open(_ context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR) != 0 {
+		return nil, 0, syscall.EROFS
+	}
+	return nil, fuse.FOPEN_KEEP_CACHE, fs.OK
+}
+
+func (f *sectionFile) Read(_ context.Context, _ fs.FileHandle, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) { //nolint:lll
+	n, err := f.r.ReadAt(dest, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, syscall.EIO
+	}
+	return fuse.ReadResultData(dest[:n]), fs.OK
+}
+
+// sectionRoot is the root FUSE directory node, which contains a single sectionFile with the
+// specified name.
+type sectionRoot struct {
+	fs.Inode
+
+	name string
+	r    *io.SectionReader
+}
+
+var _ fs.NodeOnAdder = (*sectionRoot)(nil)
+
+func (n *sectionRoot) OnAdd(ctx context.Context) {
+	ch := n.NewPersistentInode(ctx, &sectionFile{r: n.r}, fs.StableAttr{Mode: syscall.S_IFREG})
+	n.AddChild(n.name, ch, false)
+}
+
+// serveSection mounts a FUSE filesystem into dir, which exposes the contents of r as a read-only
+// file with the specified name, and returns a function to unmount the filesystem, and the path to
+// the file. The filesystem is served by the calling process. Once the filesystem is unmounted,
+// release is called to release the resources backing r.
+func serveSection(dir, name string, r *io.SectionReader, release func() error) (func() error, string, error) {
+	server, err := fs.Mount(dir, &sectionRoot{name: name, r: r}, &fs.Options{
+		MountOptions: fuse.MountOptions{
+			FsName:      "sif",
+			Name:        "sif",
+			DirectMount: true,
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	go func() {
+		server.Wait()
+		_ = release()
+	}()
+
+	return server.Unmount, filepath.Join(dir, name), nil
+}
+
+// detach lazily unmounts the filesystem at mountPath, so that it is no longer accessible by path,
+// but continues to be served to processes that have files open within it.
+func detach(ctx context.Context, mountPath string, mo mountOpts) error {
+	if err := unmountDetach(mountPath); err == nil {
+		return nil
+	}
+
+	args := []string{
+		"-u",
+		"-z",
+		filepath.Clean(mountPath),
+	}
+	cmd := exec.CommandContext(ctx, mo.fusermountPath, args...) //nolint:gosec
+	cmd.Stdout = mo.stdout
+	cmd.Stderr = mo.stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to unmount: %w", err)
+	}
+
+	return nil
+}
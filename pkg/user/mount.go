@@ -1,4 +1,4 @@
-// Copyright (c) 2022-2023, Sylabs Inc. All rights reserved.
+// Copyright (c) 2022-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the
 // LICENSE file distributed with the sources of this project regarding your
 // rights to use or distribute this software.
@@ -8,16 +8,42 @@ package user
 
 import (
 	"context"
+	"crypto/rsa"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 
+	"github.com/sylabs/sif/v2/pkg/encryption"
 	"github.com/sylabs/sif/v2/pkg/sif"
 )
 
+// runMountCommand runs the mount command at name with the specified arguments.
+func runMountCommand(ctx context.Context, name string, args []string, mo mountOpts) error {
+	//nolint:gosec // note (gosec exclusion) - we require callers to be able to specify binaries not on PATH
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = mo.stdout
+	cmd.Stderr = mo.stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to mount: %w", err)
+	}
+
+	return nil
+}
+
+const (
+	// decryptedName is the name of the file that exposes a decrypted partition.
+	decryptedName = "partition.squashfs"
+
+	// rawName is the name of the file that exposes a raw partition.
+	rawName = "partition.raw"
+)
+
 // mountSquashFS mounts the SquashFS filesystem from path at offset into mountPath.
 func mountSquashFS(ctx context.Context, offset int64, path, mountPath string, mo mountOpts) error {
 	args := []string{
@@ -25,13 +51,98 @@ func mountSquashFS(ctx context.Context, offset int64, path, mountPath string, mo
 		filepath.Clean(path),
 		filepath.Clean(mountPath),
 	}
-	//nolint:gosec // note (gosec exclusion) - we require callers to be able to specify squashfuse not on PATH
-	cmd := exec.CommandContext(ctx, mo.squashfusePath, args...)
-	cmd.Stdout = mo.stdout
-	cmd.Stderr = mo.stderr
+	return runMountCommand(ctx, mo.squashfusePath, args, mo)
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to mount: %w", err)
+// mountEncryptedSquashFS mounts the encrypted SquashFS partition described by d, from the SIF file
+// at path, into mountPath.
+//
+// The decrypted partition is not written to disk. Instead, it is exposed as a file within a FUSE
+// filesystem served by the calling process, from which it is mounted using squashfuse. The FUSE
+// filesystem is then detached, so that it remains available only to squashfuse, and is unmounted
+// once squashfuse closes the file. The FUSE filesystem reads the SIF file through its own handle,
+// which remains open until the FUSE filesystem is unmounted.
+func mountEncryptedSquashFS(ctx context.Context, path string, d sif.Descriptor, mountPath string, mo mountOpts) error { //nolint:lll
+	if mo.decryptionKey == nil {
+		return errDecryptionKeyRequired
+	}
+
+	f, err := sif.LoadContainerFromPath(path, sif.OptLoadWithFlag(os.O_RDONLY))
+	if err != nil {
+		return fmt.Errorf("failed to load image: %w", err)
+	}
+
+	// Once the FUSE filesystem is served, it is responsible for unloading the image.
+	served := false
+	defer func() {
+		if !served {
+			_ = f.UnloadContainer()
+		}
+	}()
+
+	if d, err = f.GetDescriptor(sif.WithID(d.ID())); err != nil {
+		return fmt.Errorf("failed to get partition descriptor: %w", err)
+	}
+
+	r, err := encryption.NewReader(f, d, mo.decryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt partition: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "sif-decrypted-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(dir)
+
+	unmount, decryptedPath, err := serveSection(dir, decryptedName, r, f.UnloadContainer)
+	if err != nil {
+		return fmt.Errorf("failed to serve decrypted partition: %w", err)
+	}
+	served = true
+
+	if err := mountSquashFS(ctx, 0, decryptedPath, mountPath, mo); err != nil {
+		return errors.Join(err, unmount())
+	}
+
+	if err := detach(ctx, dir, mo); err != nil {
+		return fmt.Errorf("failed to detach decrypted partition: %w", err)
+	}
+
+	return nil
+}
+
+// mountExt3 mounts the ext3 filesystem from path at offset into mountPath.
+func mountExt3(ctx context.Context, offset int64, path, mountPath string, mo mountOpts) error {
+	mode := "ro"
+	if mo.readWrite {
+		mode = "rw"
+	}
+
+	args := []string{
+		"-o", fmt.Sprintf("%v,offset=%d", mode, offset),
+		filepath.Clean(path),
+		filepath.Clean(mountPath),
+	}
+	return runMountCommand(ctx, mo.fuse2fsPath, args, mo)
+}
+
+// mountRaw exposes the raw partition described by d, from the SIF file at path, as a read-only
+// file within mountPath.
+//
+// The file is exposed within a FUSE filesystem served by the calling process, so no loop device
+// is required. The FUSE filesystem reads the SIF file through its own handle, which remains open
+// until the FUSE filesystem is unmounted.
+func mountRaw(path string, d sif.Descriptor, mountPath string) error {
+	fp, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	r := io.NewSectionReader(fp, d.Offset(), d.Size())
+
+	if _, _, err := serveSection(mountPath, rawName, r, fp.Close); err != nil {
+		return errors.Join(fmt.Errorf("failed to serve raw partition: %w", err), fp.Close())
 	}
 
 	return nil
@@ -42,6 +153,11 @@ type mountOpts struct {
 	stdout         io.Writer
 	stderr         io.Writer
 	squashfusePath string
+	fuse2fsPath    string
+	fusermountPath string
+	readWrite      bool
+	selector       sif.DescriptorSelectorFunc
+	decryptionKey  *rsa.PrivateKey
 }
 
 // MountOpt are used to specify mount options.
@@ -77,47 +193,250 @@ func OptMountSquashfusePath(path string) MountOpt {
 	}
 }
 
-var errUnsupportedFSType = errors.New("unrecognized filesystem type")
+var errFuse2fsPathInvalid = errors.New("fuse2fs path must be relative or absolute")
 
-// Mount mounts the primary system partition of the SIF file at path into mountPath.
-//
-// Mount may start one or more underlying processes. By default, stdout and stderr of these
-// processes is discarded. To modify this behavior, consider using OptMountStdout and/or
-// OptMountStderr.
-//
-// By default, Mount searches for a squashfuse binary in the directories named by the PATH
-// environment variable. To override this behavior, consider using OptMountSquashfusePath().
-func Mount(ctx context.Context, path, mountPath string, opts ...MountOpt) error {
+// OptMountFuse2fsPath sets an explicit path to the fuse2fs binary, which is used to mount ext3
+// partitions. The path must be an absolute or relative path.
+func OptMountFuse2fsPath(path string) MountOpt {
+	return func(mo *mountOpts) error {
+		if filepath.Base(path) == path {
+			return errFuse2fsPathInvalid
+		}
+		mo.fuse2fsPath = path
+		return nil
+	}
+}
+
+// OptMountFusermountPath sets an explicit path to the fusermount binary, which is used when
+// mounting encrypted squashfs partitions. The path must be an absolute or relative path.
+func OptMountFusermountPath(path string) MountOpt {
+	return func(mo *mountOpts) error {
+		if filepath.Base(path) == path {
+			return errFusermountPathInvalid
+		}
+		mo.fusermountPath = path
+		return nil
+	}
+}
+
+// OptMountReadWrite specifies whether the partition is mounted read-write. Only ext3 partitions
+// may be mounted read-write.
+func OptMountReadWrite(b bool) MountOpt {
+	return func(mo *mountOpts) error {
+		mo.readWrite = b
+		return nil
+	}
+}
+
+// OptMountDecryptionKey specifies key as the RSA private key used to decrypt encrypted squashfs
+// partitions.
+func OptMountDecryptionKey(key *rsa.PrivateKey) MountOpt {
+	return func(mo *mountOpts) error {
+		mo.decryptionKey = key
+		return nil
+	}
+}
+
+// OptMountObjectID selects the partition with the specified object ID.
+func OptMountObjectID(id uint32) MountOpt {
+	return func(mo *mountOpts) error {
+		mo.selector = sif.WithID(id)
+		return nil
+	}
+}
+
+// OptMountPartitionType selects the partition with the specified partition type.
+func OptMountPartitionType(pt sif.PartType) MountOpt {
+	return func(mo *mountOpts) error {
+		mo.selector = sif.WithPartitionType(pt)
+		return nil
+	}
+}
+
+// OptMountDescriptorSelector selects the partition(s) using fn.
+func OptMountDescriptorSelector(fn sif.DescriptorSelectorFunc) MountOpt {
+	return func(mo *mountOpts) error {
+		mo.selector = fn
+		return nil
+	}
+}
+
+var (
+	errUnsupportedFSType     = errors.New("unrecognized filesystem type")
+	errReadWriteNotSupported = errors.New("read-write mount not supported for filesystem type")
+	errDecryptionKeyRequired = errors.New("decryption key required to mount encrypted partition")
+)
+
+// mountDescriptor mounts the partition described by d, from the SIF file f at path, into
+// mountPath.
+func mountDescriptor(ctx context.Context, f *sif.FileImage, d sif.Descriptor, path, mountPath string, mo mountOpts) error { //nolint:lll
+	fs, _, _, err := d.PartitionMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to get partition metadata: %w", err)
+	}
+
+	if mo.readWrite && fs != sif.FsExt3 {
+		return fmt.Errorf("%w: %v", errReadWriteNotSupported, fs)
+	}
+
+	switch fs {
+	case sif.FsSquash:
+		return mountSquashFS(ctx, d.Offset(), path, mountPath, mo)
+	case sif.FsEncryptedSquashfs:
+		return mountEncryptedSquashFS(ctx, path, d, mountPath, mo)
+	case sif.FsExt3:
+		return mountExt3(ctx, d.Offset(), path, mountPath, mo)
+	case sif.FsRaw:
+		return mountRaw(path, d, mountPath)
+	default:
+		return errUnsupportedFSType
+	}
+}
+
+// getMountOpts returns mount options configured according to opts.
+func getMountOpts(opts ...MountOpt) (mountOpts, error) {
 	mo := mountOpts{
 		squashfusePath: "squashfuse",
+		fuse2fsPath:    "fuse2fs",
+		fusermountPath: "fusermount",
 	}
 
 	for _, opt := range opts {
 		if err := opt(&mo); err != nil {
-			return fmt.Errorf("%w", err)
+			return mountOpts{}, fmt.Errorf("%w", err)
 		}
 	}
 
+	return mo, nil
+}
+
+// getPrimaryPartition returns the primary system partition in f for CPU architecture goarch. If
+// there is no such partition, the primary system partition in f is returned, provided it is
+// unique.
+func getPrimaryPartition(f *sif.FileImage, goarch string) (sif.Descriptor, error) {
+	if d, err := f.GetDescriptor(sif.WithPrimaryPartitionForArch(goarch)); err == nil {
+		return d, nil
+	}
+	return f.GetDescriptor(sif.WithPartitionType(sif.PartPrimSys))
+}
+
+// Mount mounts a partition of the SIF file at path into mountPath.
+//
+// Squashfs partitions are mounted using squashfuse, and ext3 partitions are mounted using
+// fuse2fs. Partitions are mounted read-only, unless OptMountReadWrite is specified for an ext3
+// partition.
+//
+// Raw partitions are exposed as a single read-only file named "partition.raw" within mountPath.
+// The file is served through FUSE by the calling process, so no loop device is required, and the
+// partition should be unmounted before the calling process exits.
+//
+// Encrypted squashfs partitions are decrypted using the RSA private key specified by
+// OptMountDecryptionKey. The decrypted filesystem is not written to disk. Instead, it is served
+// to squashfuse through FUSE by the calling process, so the partition should be unmounted before
+// the calling process exits.
+//
+// By default, the primary system partition for the host CPU architecture is mounted. If there is
+// no such partition, and the image contains a single primary system partition, that partition is
+// mounted. To mount a different partition, consider using OptMountObjectID, OptMountPartitionType
+// or OptMountDescriptorSelector. The selected partition must be unique.
+//
+// Mount may start one or more underlying processes. By default, stdout and stderr of these
+// processes is discarded. To modify this behavior, consider using OptMountStdout and/or
+// OptMountStderr.
+//
+// By default, Mount searches for squashfuse, fuse2fs and fusermount binaries in the directories
+// named by the PATH environment variable. To override this behavior, consider using
+// OptMountSquashfusePath(), OptMountFuse2fsPath() and/or OptMountFusermountPath().
+func Mount(ctx context.Context, path, mountPath string, opts ...MountOpt) error {
+	mo, err := getMountOpts(opts...)
+	if err != nil {
+		return err
+	}
+
 	f, err := sif.LoadContainerFromPath(path, sif.OptLoadWithFlag(os.O_RDONLY))
 	if err != nil {
 		return fmt.Errorf("failed to load image: %w", err)
 	}
 	defer func() { _ = f.UnloadContainer() }()
 
-	d, err := f.GetDescriptor(sif.WithPartitionType(sif.PartPrimSys))
+	var d sif.Descriptor
+	if mo.selector == nil {
+		d, err = getPrimaryPartition(f, runtime.GOARCH)
+	} else {
+		d, err = f.GetDescriptor(mo.selector)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get partition descriptor: %w", err)
 	}
 
-	fs, _, _, err := d.PartitionMetadata()
+	return mountDescriptor(ctx, f, d, path, mountPath, mo)
+}
+
+// MountAll mounts each squashfs partition of the SIF file at path into a directory beneath
+// baseDir, named according to the object ID of the partition. Directories are created as
+// required. The paths of the mounted partitions are returned.
+//
+// By default, all squashfs partitions are mounted. To mount a subset, consider using
+// OptMountPartitionType or OptMountDescriptorSelector.
+//
+// If an error occurs, partitions that were mounted before the error remain mounted, and their
+// paths are returned alongside the error.
+//
+// MountAll may start one or more underlying processes. By default, stdout and stderr of these
+// processes is discarded. To modify this behavior, consider using OptMountStdout and/or
+// OptMountStderr.
+//
+// By default, MountAll searches for a squashfuse binary in the directories named by the PATH
+// environment variable. To override this behavior, consider using OptMountSquashfusePath().
+func MountAll(ctx context.Context, path, baseDir string, opts ...MountOpt) ([]string, error) {
+	mo, err := getMountOpts(opts...)
 	if err != nil {
-		return fmt.Errorf("failed to get partition metadata: %w", err)
+		return nil, err
 	}
 
-	switch fs {
-	case sif.FsSquash:
-		return mountSquashFS(ctx, d.Offset(), path, mountPath, mo)
-	default:
-		return errUnsupportedFSType
+	fns := []sif.DescriptorSelectorFunc{
+		sif.WithDataType(sif.DataPartition),
+		isSquashFS,
+	}
+	if mo.selector != nil {
+		fns = append(fns, mo.selector)
+	}
+
+	f, err := sif.LoadContainerFromPath(path, sif.OptLoadWithFlag(os.O_RDONLY))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image: %w", err)
+	}
+	defer func() { _ = f.UnloadContainer() }()
+
+	ds, err := f.GetDescriptors(fns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partition descriptors: %w", err)
+	}
+
+	mountPaths := make([]string, 0, len(ds))
+
+	for _, d := range ds {
+		mountPath := filepath.Join(baseDir, strconv.FormatUint(uint64(d.ID()), 10))
+
+		if err := os.MkdirAll(mountPath, 0o755); err != nil {
+			return mountPaths, err
+		}
+
+		if err := mountDescriptor(ctx, f, d, path, mountPath, mo); err != nil {
+			return mountPaths, fmt.Errorf("object %v: %w", d.ID(), err)
+		}
+
+		mountPaths = append(mountPaths, mountPath)
+	}
+
+	return mountPaths, nil
+}
+
+// isSquashFS selects partitions containing a squashfs filesystem.
+func isSquashFS(d sif.Descriptor) (bool, error) {
+	fs, _, _, err := d.PartitionMetadata()
+	if err != nil {
+		return false, err
 	}
+	return fs == sif.FsSquash, nil
 }
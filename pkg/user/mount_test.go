@@ -0,0 +1,351 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+package user
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/sylabs/sif/v2/internal/pkg/exectest"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// makeMountSIF returns the path to a SIF containing squashfs primary system (ID 1) and data
+// (ID 2) partitions, an ext3 data partition (ID 3), a raw data partition (ID 4), and a generic
+// object (ID 5).
+func makeMountSIF(t *testing.T) string {
+	t.Helper()
+
+	input := filepath.Join("..", "..", "test", "input")
+
+	squash, err := os.ReadFile(filepath.Join(input, "root.squashfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ext3, err := os.ReadFile(filepath.Join(input, "root.ext3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dis []sif.DescriptorInput
+
+	for _, di := range []struct {
+		t    sif.DataType
+		b    []byte
+		opts []sif.DescriptorInputOpt
+	}{
+		{sif.DataPartition, squash, []sif.DescriptorInputOpt{
+			sif.OptPartitionMetadata(sif.FsSquash, sif.PartPrimSys, "amd64"),
+		}},
+		{sif.DataPartition, squash, []sif.DescriptorInputOpt{
+			sif.OptPartitionMetadata(sif.FsSquash, sif.PartData, "amd64"),
+		}},
+		{sif.DataPartition, ext3, []sif.DescriptorInputOpt{
+			sif.OptPartitionMetadata(sif.FsExt3, sif.PartData, "amd64"),
+		}},
+		{sif.DataPartition, []byte{0xde, 0xad, 0xbe, 0xef}, []sif.DescriptorInputOpt{
+			sif.OptPartitionMetadata(sif.FsRaw, sif.PartData, "amd64"),
+		}},
+		{sif.DataGeneric, []byte{0xfa, 0xce}, nil},
+	} {
+		d, err := sif.NewDescriptorInput(di.t, bytes.NewReader(di.b), di.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dis = append(dis, d)
+	}
+
+	path := filepath.Join(t.TempDir(), "image.sif")
+
+	f, err := sif.CreateContainerAtPath(path, sif.OptCreateDeterministic(), sif.OptCreateWithDescriptors(dis...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.UnloadContainer(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// getDescriptor returns the descriptor of the object with the specified ID in the SIF file at path.
+func getDescriptor(t *testing.T, path string, id uint32) sif.Descriptor {
+	t.Helper()
+
+	f, err := sif.LoadContainerFromPath(path, sif.OptLoadWithFlag(os.O_RDONLY))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.UnloadContainer() }()
+
+	d, err := f.GetDescriptor(sif.WithID(id))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d
+}
+
+func TestMount(t *testing.T) {
+	opts := []MountOpt{
+		OptMountSquashfusePath(exectest.Script(t, "squashfuse", `echo squashfuse "$@"`)),
+		OptMountFuse2fsPath(exectest.Script(t, "fuse2fs", `echo fuse2fs "$@"`)),
+	}
+	path := makeMountSIF(t)
+
+	// fuseArgs returns the expected squashfuse or fuse2fs arguments.
+	fuseArgs := func(name, mode string) func(sif.Descriptor, string) string {
+		return func(d sif.Descriptor, mountPath string) string {
+			return fmt.Sprintf("%v -o %v,offset=%d %v %v\n", name, mode, d.Offset(), path, mountPath)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		opts     []MountOpt
+		wantID   uint32
+		wantArgs func(sif.Descriptor, string) string
+		wantErr  error
+	}{
+		{
+			name:     "Default",
+			wantID:   1,
+			wantArgs: fuseArgs("squashfuse", "ro"),
+		},
+		{
+			name:     "ObjectID",
+			opts:     []MountOpt{OptMountObjectID(2)},
+			wantID:   2,
+			wantArgs: fuseArgs("squashfuse", "ro"),
+		},
+		{
+			name:    "ObjectIDNotFound",
+			opts:    []MountOpt{OptMountObjectID(6)},
+			wantErr: sif.ErrObjectNotFound,
+		},
+		{
+			name:     "PartitionType",
+			opts:     []MountOpt{OptMountPartitionType(sif.PartPrimSys)},
+			wantID:   1,
+			wantArgs: fuseArgs("squashfuse", "ro"),
+		},
+		{
+			name:    "PartitionTypeMultiple",
+			opts:    []MountOpt{OptMountPartitionType(sif.PartData)},
+			wantErr: sif.ErrMultipleObjectsFound,
+		},
+		{
+			name: "DescriptorSelector",
+			opts: []MountOpt{OptMountDescriptorSelector(func(d sif.Descriptor) (bool, error) {
+				_, pt, _, err := d.PartitionMetadata()
+				return err == nil && pt == sif.PartData && d.ID() == 2, nil
+			})},
+			wantID:   2,
+			wantArgs: fuseArgs("squashfuse", "ro"),
+		},
+		{
+			name:    "SquashReadWrite",
+			opts:    []MountOpt{OptMountReadWrite(true)},
+			wantErr: errReadWriteNotSupported,
+		},
+		{
+			name:     "Ext3",
+			opts:     []MountOpt{OptMountObjectID(3)},
+			wantID:   3,
+			wantArgs: fuseArgs("fuse2fs", "ro"),
+		},
+		{
+			name:     "Ext3ReadWrite",
+			opts:     []MountOpt{OptMountObjectID(3), OptMountReadWrite(true)},
+			wantID:   3,
+			wantArgs: fuseArgs("fuse2fs", "rw"),
+		},
+		{
+			name:    "RawReadWrite",
+			opts:    []MountOpt{OptMountObjectID(4), OptMountReadWrite(true)},
+			wantErr: errReadWriteNotSupported,
+		},
+		{
+			name:    "Fuse2fsPathInvalid",
+			opts:    []MountOpt{OptMountFuse2fsPath("fuse2fs")},
+			wantErr: errFuse2fsPathInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			mountPath := t.TempDir()
+
+			opts := append(slices.Clone(opts), OptMountStdout(&b))
+			opts = append(opts, tt.opts...)
+
+			if got, want := Mount(context.Background(), path, mountPath, opts...), tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if tt.wantErr == nil {
+				want := tt.wantArgs(getDescriptor(t, path, tt.wantID), mountPath)
+
+				if got := b.String(); got != want {
+					t.Errorf("got args %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetPrimaryPartition(t *testing.T) {
+	// makeImage returns an image containing a primary system partition for each of archs.
+	makeImage := func(t *testing.T, archs ...string) *sif.FileImage {
+		t.Helper()
+
+		dis := make([]sif.DescriptorInput, 0, len(archs))
+		for _, arch := range archs {
+			di, err := sif.NewDescriptorInput(sif.DataPartition, bytes.NewReader([]byte{0xfa, 0xce}),
+				sif.OptPartitionMetadata(sif.FsSquash, sif.PartPrimSys, arch),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dis = append(dis, di)
+		}
+
+		f, err := sif.CreateContainer(&sif.Buffer{}, sif.OptCreateDeterministic(), sif.OptCreateWithDescriptors(dis...))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return f
+	}
+
+	tests := []struct {
+		name    string
+		archs   []string
+		goarch  string
+		wantID  uint32
+		wantErr error
+	}{
+		{
+			name:    "NoObjects",
+			goarch:  "amd64",
+			wantErr: sif.ErrNoObjects,
+		},
+		{
+			name:   "Host",
+			archs:  []string{"amd64"},
+			goarch: "amd64",
+			wantID: 1,
+		},
+		{
+			name:   "Other",
+			archs:  []string{"arm64"},
+			goarch: "amd64",
+			wantID: 1,
+		},
+		{
+			name:   "MultiArchHost",
+			archs:  []string{"arm64", "amd64", "ppc64le"},
+			goarch: "amd64",
+			wantID: 2,
+		},
+		{
+			name:    "MultiArchOther",
+			archs:   []string{"arm64", "ppc64le"},
+			goarch:  "amd64",
+			wantErr: sif.ErrMultipleObjectsFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := getPrimaryPartition(makeImage(t, tt.archs...), tt.goarch)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if got, want := d.ID(), tt.wantID; got != want {
+				t.Errorf("got ID %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMountAll(t *testing.T) {
+	squashfusePath := exectest.Script(t, "squashfuse", `echo squashfuse "$@"`)
+	path := makeMountSIF(t)
+
+	tests := []struct {
+		name    string
+		opts    []MountOpt
+		wantIDs []uint32
+	}{
+		{
+			name:    "All",
+			wantIDs: []uint32{1, 2},
+		},
+		{
+			name:    "PartitionType",
+			opts:    []MountOpt{OptMountPartitionType(sif.PartData)},
+			wantIDs: []uint32{2},
+		},
+		{
+			name:    "DescriptorSelector",
+			opts:    []MountOpt{OptMountDescriptorSelector(sif.WithID(5))},
+			wantIDs: []uint32{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+
+			baseDir := filepath.Join(t.TempDir(), "mnt")
+
+			opts := append([]MountOpt{
+				OptMountSquashfusePath(squashfusePath),
+				OptMountStdout(&b),
+			}, tt.opts...)
+
+			mountPaths, err := MountAll(context.Background(), path, baseDir, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var wantArgs string
+			wantPaths := make([]string, 0, len(tt.wantIDs))
+
+			for _, id := range tt.wantIDs {
+				mountPath := filepath.Join(baseDir, strconv.FormatUint(uint64(id), 10))
+				wantPaths = append(wantPaths, mountPath)
+				wantArgs += fmt.Sprintf("squashfuse -o ro,offset=%d %v %v\n",
+					getDescriptor(t, path, id).Offset(), path, mountPath)
+
+				if fi, err := os.Stat(mountPath); err != nil || !fi.IsDir() {
+					t.Errorf("mount path %v not created", mountPath)
+				}
+			}
+
+			if got, want := mountPaths, wantPaths; !slices.Equal(got, want) {
+				t.Errorf("got mount paths %v, want %v", got, want)
+			}
+
+			if got, want := b.String(), wantArgs; got != want {
+				t.Errorf("got args %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2026, Sylabs Inc. All rights reserved.
+// This software is licensed under a 3-clause BSD license. Please consult the
+// LICENSE file distributed with the sources of this project regarding your
+// rights to use or distribute this software.
+
+//go:build linux || darwin || freebsd
+
+package user
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sylabs/sif/v2/internal/pkg/exectest"
+	"github.com/sylabs/sif/v2/pkg/encryption"
+	"github.com/sylabs/sif/v2/pkg/sif"
+)
+
+// makeEncryptedSIF returns the path to a SIF containing an encrypted squashfs primary system
+// partition, the passphrase of which is encrypted using test/keys/rsa-public.pem.
+func makeEncryptedSIF(t *testing.T) string {
+	t.Helper()
+
+	squash, err := os.ReadFile(filepath.Join("..", "..", "test", "input", "root.squashfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "image.sif")
+
+	f, err := sif.CreateContainerAtPath(path, sif.OptCreateDeterministic())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.UnloadContainer() }()
+
+	if err := encryption.AddSquashFS(f, bytes.NewReader(squash), &getTestKey(t).PublicKey); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// getTestKey returns the RSA private key read from test/keys/rsa-private.pem.
+func getTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	b, err := os.ReadFile(filepath.Join("..", "..", "test", "keys", "rsa-private.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pk, err := cryptoutils.UnmarshalPEMToPrivateKey(b, cryptoutils.SkipPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, ok := pk.(*rsa.PrivateKey)
+	if !ok {
+		t.Fatalf("unexpected key type %T", pk)
+	}
+
+	return key
+}
+
+// requireFUSE skips the test if a FUSE filesystem cannot be mounted.
+func requireFUSE(t *testing.T) {
+	t.Helper()
+
+	server, err := fs.Mount(t.TempDir(), &fs.Inode{}, &fs.Options{
+		MountOptions: fuse.MountOptions{DirectMount: true},
+	})
+	if err != nil {
+		t.Skipf("FUSE not available: %v", err)
+	}
+
+	if err := server.Unmount(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMount_Raw(t *testing.T) {
+	requireFUSE(t)
+
+	path := makeMountSIF(t)
+	mountPath := t.TempDir()
+
+	if err := Mount(context.Background(), path, mountPath, OptMountObjectID(4)); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := unmountDetach(mountPath); err != nil {
+			t.Error(err)
+		}
+	})
+
+	rawPath := filepath.Join(mountPath, rawName)
+
+	got, err := os.ReadFile(rawPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []byte{0xde, 0xad, 0xbe, 0xef}; !bytes.Equal(got, want) {
+		t.Errorf("got data %x, want %x", got, want)
+	}
+
+	if _, err := os.OpenFile(rawPath, os.O_WRONLY, 0); !errors.Is(err, syscall.EROFS) {
+		t.Errorf("got error %v, want %v", err, syscall.EROFS)
+	}
+}
+
+func TestMount_Encrypted(t *testing.T) {
+	requireFUSE(t)
+
+	path := makeEncryptedSIF(t)
+
+	want, err := os.ReadFile(filepath.Join("..", "..", "test", "input", "root.squashfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []MountOpt
+		wantErr error
+	}{
+		{
+			name: "OK",
+			opts: []MountOpt{OptMountDecryptionKey(getTestKey(t))},
+		},
+		{
+			name:    "NoKey",
+			wantErr: errDecryptionKeyRequired,
+		},
+		{
+			name:    "WrongKey",
+			opts:    []MountOpt{OptMountDecryptionKey(otherKey)},
+			wantErr: rsa.ErrDecryption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Setenv("TMPDIR", tmpDir)
+
+			// Copy the image passed to squashfuse, and record the type of filesystem containing it,
+			// so that they can be inspected.
+			copyPath := filepath.Join(t.TempDir(), "copy.squashfs")
+			fsTypePath := filepath.Join(t.TempDir(), "fstype")
+			squashfusePath := exectest.Script(t, "squashfuse",
+				fmt.Sprintf("cp \"$3\" %q\nstat -f -c %%T \"$3\" >%q", copyPath, fsTypePath))
+
+			opts := append([]MountOpt{OptMountSquashfusePath(squashfusePath)}, tt.opts...)
+
+			err := Mount(context.Background(), path, t.TempDir(), opts...)
+			if got, want := err, tt.wantErr; !errors.Is(got, want) {
+				t.Fatalf("got error %v, want %v", got, want)
+			}
+
+			if err == nil {
+				got, err := os.ReadFile(copyPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.HasPrefix(got, want) {
+					t.Errorf("decrypted image does not match")
+				}
+
+				// The decrypted image must be served through FUSE, rather than written to disk.
+				fsType, err := os.ReadFile(fsTypePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got, want := string(fsType), "fuse"; !strings.HasPrefix(got, want) {
+					t.Errorf("got filesystem type %q, want %q", got, want)
+				}
+			}
+
+			// The decrypted image must not be left behind.
+			if des, err := os.ReadDir(tmpDir); err != nil {
+				t.Fatal(err)
+			} else if len(des) > 0 {
+				t.Errorf("got %v temporary files, want 0", len(des))
+			}
+		})
+	}
+}
+
+func TestMount_EncryptedAfterReturn(t *testing.T) {
+	requireFUSE(t)
+
+	path := makeEncryptedSIF(t)
+
+	want, err := os.ReadFile(filepath.Join("..", "..", "test", "input", "root.squashfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TMPDIR", t.TempDir())
+
+	dir := t.TempDir()
+	startPath := filepath.Join(dir, "start")
+	copyPath := filepath.Join(dir, "copy.squashfs")
+	donePath := filepath.Join(dir, "done")
+
+	// Open the image passed to squashfuse, and copy it in the background once started, so that it
+	// is read after Mount returns.
+	squashfusePath := exectest.Script(t, "squashfuse", fmt.Sprintf(
+		"exec 3<\"$3\"\n"+
+			"(while [ ! -e %q ]; do sleep 0.01; done; cat <&3 >%q; touch %q) </dev/null >/dev/null 2>&1 &",
+		startPath, copyPath, donePath))
+
+	if err := Mount(context.Background(), path, t.TempDir(),
+		OptMountSquashfusePath(squashfusePath),
+		OptMountDecryptionKey(getTestKey(t)),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(startPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(10 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(donePath); err == nil {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for copy")
+		}
+	}
+
+	got, err := os.ReadFile(copyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(got, want) {
+		t.Errorf("decrypted image does not match")
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2024, Sylabs Inc. All rights reserved.
+// Copyright (c) 2020-2026, Sylabs Inc. All rights reserved.
 // This software is licensed under a 3-clause BSD license. Please consult the LICENSE.md file
 // distributed with the sources of this project regarding your rights to use or distribute this
 // software.
@@ -10,11 +10,14 @@ import (
 	"crypto"
 	"errors"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sigstore/sigstore/pkg/signature"
 	"github.com/sylabs/sif/v2/pkg/integrity"
@@ -126,6 +129,21 @@ func generateImages() error {
 		)
 	}
 
+	partPrimSysAMD64 := func() (sif.DescriptorInput, error) {
+		b, err := os.ReadFile(filepath.Join("..", "input", "root.squashfs"))
+		if err != nil {
+			return sif.DescriptorInput{}, err
+		}
+
+		return sif.NewDescriptorInput(sif.DataPartition, bytes.NewReader(b),
+			sif.OptPartitionPlatformMetadata(sif.FsSquash, sif.PartPrimSys, v1.Platform{
+				OS:           "linux",
+				Architecture: "amd64",
+				Variant:      "v3",
+			}),
+		)
+	}
+
 	partSystemGroup2 := func() (sif.DescriptorInput, error) {
 		b, err := os.ReadFile(filepath.Join("..", "input", "root.ext3"))
 		if err != nil {
@@ -139,10 +157,11 @@ func generateImages() error {
 	}
 
 	images := []struct {
-		path     string
-		diFns    []func() (sif.DescriptorInput, error)
-		opts     []sif.CreateOpt
-		signOpts []integrity.SignerOpt
+		path        string
+		diFns       []func() (sif.DescriptorInput, error)
+		opts        []sif.CreateOpt
+		annotations map[uint32]map[string]string
+		signOpts    []integrity.SignerOpt
 	}{
 		// Images with no objects.
 		{
@@ -210,6 +229,22 @@ func generateImages() error {
 				partPrimSys,
 			},
 		},
+		{
+			path: "one-group-annotated.sif",
+			diFns: []func() (sif.DescriptorInput, error){
+				partSystem,
+				partPrimSys,
+			},
+			annotations: map[uint32]map[string]string{
+				1: {
+					"org.opencontainers.image.source":   "https://github.com/sylabs/sif",
+					"org.opencontainers.image.revision": "4a5b7e4b0f0d8c7f6a1e3f4b2c6d9e8a7b5c3d1f",
+				},
+				2: {
+					"org.opencontainers.image.source": "https://github.com/sylabs/sif",
+				},
+			},
+		},
 		{
 			path: "one-group-signed-dsse.sif",
 			diFns: []func() (sif.DescriptorInput, error){
@@ -231,6 +266,15 @@ func generateImages() error {
 			},
 		},
 
+		// Images with a primary system partition for each of two architectures.
+		{
+			path: "multi-arch.sif",
+			diFns: []func() (sif.DescriptorInput, error){
+				partPrimSys,
+				partPrimSysAMD64,
+			},
+		},
+
 		// Images with three partitions in two groups.
 		{
 			path: "two-groups.sif",
@@ -290,6 +334,12 @@ func generateImages() error {
 			}
 		}()
 
+		for _, id := range slices.Sorted(maps.Keys(image.annotations)) {
+			if err := f.SetAnnotations(id, image.annotations[id], sif.OptSetDeterministic()); err != nil {
+				return err
+			}
+		}
+
 		if opts := image.signOpts; opts != nil {
 			opts = append(opts,
 				integrity.OptSignWithTime(func() time.Time { return time.Date(2020, 6, 30, 0, 1, 56, 0, time.UTC) }),